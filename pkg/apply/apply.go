@@ -8,6 +8,8 @@ package apply
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/simplekube/kit/pkg/k8sutil"
 
@@ -93,16 +95,79 @@ func GetLastAppliedByAnnKey(obj *unstructured.Unstructured, annKey string) (map[
 // Merge is based on a 3-way apply that takes in observed state,
 // last applied state & desired state into consideration.
 func Merge(observed, lastApplied, desired map[string]interface{}) (map[string]interface{}, error) {
+	return MergeWithOptions(observed, lastApplied, desired, MergeOptions{})
+}
+
+// MergeOptions tunes the detection of merge keys used by
+// MergeWithOptions
+type MergeOptions struct {
+	// IncludeStatus when true merges the lists of maps found in status
+	// e.g. status.conditions on the basis of their "type" instead of
+	// replacing them wholesale
+	IncludeStatus bool
+
+	// ExtraMergeKeys are guessed as merge keys in addition to the known
+	// merge keys. These have lower precedence than the known merge keys.
+	ExtraMergeKeys []string
+}
+
+// MergeWithOptions is same as Merge but lets the caller tune the merge
+// of lists of maps
+//
+// NOTE:
+//	Merge is same as MergeWithOptions with empty options
+func MergeWithOptions(observed, lastApplied, desired map[string]interface{}, opts MergeOptions) (map[string]interface{}, error) {
 	// Make a copy of observed & use it as the destination for final merged state
 	observedAsDest := runtime.DeepCopyJSON(observed)
 
-	if _, err := mergeToObserved("", observedAsDest, lastApplied, desired); err != nil {
+	m := newMerger(opts)
+	if _, err := m.mergeToObserved("", "", observedAsDest, lastApplied, desired); err != nil {
 		return nil, errors.Wrapf(err, "merge desired to observed")
 	}
 	return observedAsDest, nil
 }
 
-func mergeToObserved(fieldPath string, observed, lastApplied, desired interface{}) (interface{}, error) {
+// merger holds the merge keys that are guessed while merging lists of
+// maps
+type merger struct {
+	mergeKeys       []string
+	statusMergeKeys []string
+
+	// registeredMergeKeys are the merge keys registered against
+	// field paths via RegisterMergeKey
+	registeredMergeKeys map[string]string
+}
+
+// newMerger returns a merger based on the provided options
+func newMerger(opts MergeOptions) merger {
+	mergeKeys := append(append([]string{}, knownMergeKeys...), opts.ExtraMergeKeys...)
+	statusMergeKeys := mergeKeys
+	if opts.IncludeStatus {
+		statusMergeKeys = append(append([]string{}, mergeKeys...), statusMergeKey)
+	}
+	return merger{
+		mergeKeys:           mergeKeys,
+		statusMergeKeys:     statusMergeKeys,
+		registeredMergeKeys: getRegisteredMergeKeys(),
+	}
+}
+
+// mergeKeysFor returns the merge keys applicable to the provided
+// field path
+func (m merger) mergeKeysFor(fieldPath string) []string {
+	if strings.HasPrefix(fieldPath, "[status]") {
+		return m.statusMergeKeys
+	}
+	return m.mergeKeys
+}
+
+// mergeToObserved merges the desired value into the observed value
+//
+// NOTE:
+//	fieldPath identifies the value being merged e.g. [spec][containers][app]
+// while schemaPath identifies its field irrespective of the list items
+// e.g. spec.containers[]
+func (m merger) mergeToObserved(fieldPath, schemaPath string, observed, lastApplied, desired interface{}) (interface{}, error) {
 	switch observedVal := observed.(type) {
 	case map[string]interface{}:
 		// In this case, observed is a **map**.
@@ -124,7 +189,7 @@ func mergeToObserved(fieldPath string, observed, lastApplied, desired interface{
 					observed, desired, fieldPath,
 				)
 		}
-		return mergeMapToObserved(fieldPath, observedVal, lastAppliedVal, desiredVal)
+		return m.mergeMapToObserved(fieldPath, schemaPath, observedVal, lastAppliedVal, desiredVal)
 	case []interface{}:
 		// In this case observed is an **array**.
 		// Make sure desired & last applied are arrays too.
@@ -145,7 +210,7 @@ func mergeToObserved(fieldPath string, observed, lastApplied, desired interface{
 					observed, desired, fieldPath,
 				)
 		}
-		return mergeArrayToObserved(fieldPath, observedVal, lastAppliedVal, desiredVal)
+		return m.mergeArrayToObserved(fieldPath, schemaPath, observedVal, lastAppliedVal, desiredVal)
 	default:
 		// Observed is either a **scalar** or **null**.
 		//
@@ -161,7 +226,23 @@ func mergeToObserved(fieldPath string, observed, lastApplied, desired interface{
 	}
 }
 
-func mergeMapToObserved(fieldPath string, observed, lastApplied, desired map[string]interface{}) (interface{}, error) {
+func (m merger) mergeMapToObserved(fieldPath, schemaPath string, observed, lastApplied, desired map[string]interface{}) (interface{}, error) {
+	return m.mergeFieldsToObserved(fieldPath, observed, lastApplied, desired, func(key string) string {
+		if schemaPath == "" {
+			return key
+		}
+		return schemaPath + "." + key
+	})
+}
+
+// mergeFieldsToObserved merges the fields of the desired map into the
+// observed map. The schema path of each field is derived via the provided
+// function.
+func (m merger) mergeFieldsToObserved(
+	fieldPath string,
+	observed, lastApplied, desired map[string]interface{},
+	schemaPathFor func(key string) string,
+) (interface{}, error) {
 	// Remove fields that were present in lastApplied, but no longer
 	// in desired. In other words, this decision to delete a field
 	// is based on last applied state.
@@ -183,7 +264,7 @@ func mergeMapToObserved(fieldPath string, observed, lastApplied, desired map[str
 	for key, desiredVal := range desired {
 		// destination is mutated here either as an add or update map operation
 		nestedPath := fmt.Sprintf("%s[%s]", fieldPath, key)
-		observed[key], err = mergeToObserved(nestedPath, observed[key], lastApplied[key], desiredVal)
+		observed[key], err = m.mergeToObserved(nestedPath, schemaPathFor(key), observed[key], lastApplied[key], desiredVal)
 		if err != nil {
 			return nil, err
 		}
@@ -195,11 +276,17 @@ func mergeMapToObserved(fieldPath string, observed, lastApplied, desired map[str
 	return observed, nil
 }
 
-func mergeArrayToObserved(fieldPath string, observed, lastApplied, desired []interface{}) (interface{}, error) {
+func (m merger) mergeArrayToObserved(fieldPath, schemaPath string, observed, lastApplied, desired []interface{}) (interface{}, error) {
+	// A merge key registered against this path takes precedence over
+	// the guessed merge key
+	if mergeKey, ok := m.registeredMergeKeys[schemaPath]; ok && isListMapKey(mergeKey, observed, lastApplied, desired) {
+		return m.mergeListMapToObserved(fieldPath, schemaPath, mergeKey, observed, lastApplied, desired)
+	}
+
 	// If it looks like a list of map, use the special mergeListMapToObserved
 	// by determining the best possible **merge key**
-	if mergeKey := detectListMapKey(observed, lastApplied, desired); mergeKey != "" {
-		return mergeListMapToObserved(fieldPath, mergeKey, observed, lastApplied, desired)
+	if mergeKey := detectListMapKeyFrom(m.mergeKeysFor(fieldPath), observed, lastApplied, desired); mergeKey != "" {
+		return m.mergeListMapToObserved(fieldPath, schemaPath, mergeKey, observed, lastApplied, desired)
 	}
 
 	// It's a normal array of scalars.
@@ -213,14 +300,17 @@ func mergeArrayToObserved(fieldPath string, observed, lastApplied, desired []int
 	return desired, nil
 }
 
-func mergeListMapToObserved(fieldPath, mergeKey string, observed, lastApplied, desired []interface{}) (interface{}, error) {
+func (m merger) mergeListMapToObserved(fieldPath, schemaPath, mergeKey string, observed, lastApplied, desired []interface{}) (interface{}, error) {
 	// transform the lists to corresponding maps, keyed by the mergeKey field
 	observedMap := makeMapFromList(mergeKey, observed)
 	lastAppliedMap := makeMapFromList(mergeKey, lastApplied)
 	desiredMap := makeMapFromList(mergeKey, desired)
 
 	// once in map, try map based merge
-	_, err := mergeMapToObserved(fieldPath, observedMap, lastAppliedMap, desiredMap)
+	// every item shares the same schema path irrespective of its merge key
+	_, err := m.mergeFieldsToObserved(fieldPath, observedMap, lastAppliedMap, desiredMap, func(string) string {
+		return schemaPath + "[]"
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -295,6 +385,7 @@ func stringMergeKey(val interface{}) string {
 // 	As of now we don't do merges on status because the controller is
 // solely responsible for providing the entire contents of status.
 // As a result, we don't try to handle things like status.conditions
+// unless MergeOptions.IncludeStatus is set
 var knownMergeKeys = []string{
 	"uid",
 	"id",
@@ -303,11 +394,69 @@ var knownMergeKeys = []string{
 	"key",
 	"component",
 	"containerPort",
-	"container-port", // TODO @amit.das Provide a way to register customMergeKeys
+	"container-port", // Note: Use RegisterMergeKey for path specific merge keys
 	"port",
 	"ip",
 }
 
+// statusMergeKey is guessed as the merge key of lists of maps found in
+// status e.g. status.conditions when MergeOptions.IncludeStatus is set
+const statusMergeKey = "type"
+
+var (
+	registeredMergeKeys   = map[string]string{}
+	registeredMergeKeysMu sync.RWMutex
+)
+
+// RegisterMergeKey registers the merge key of the list of maps found at
+// the provided dotted field path. Lists nested in lists are referred with
+// [] e.g. spec.template.spec.containers[].volumeMounts. A registered merge
+// key takes precedence over the guessed merge key.
+//
+// NOTE:
+//	The registered merge key is ignored if any of the items lack it
+func RegisterMergeKey(fieldPath, mergeKey string) {
+	registeredMergeKeysMu.Lock()
+	defer registeredMergeKeysMu.Unlock()
+	registeredMergeKeys[fieldPath] = mergeKey
+}
+
+// UnregisterMergeKey removes the merge key registered against the
+// provided field path
+func UnregisterMergeKey(fieldPath string) {
+	registeredMergeKeysMu.Lock()
+	defer registeredMergeKeysMu.Unlock()
+	delete(registeredMergeKeys, fieldPath)
+}
+
+// getRegisteredMergeKeys returns a copy of the registered merge keys
+func getRegisteredMergeKeys() map[string]string {
+	registeredMergeKeysMu.RLock()
+	defer registeredMergeKeysMu.RUnlock()
+	keys := make(map[string]string, len(registeredMergeKeys))
+	for path, key := range registeredMergeKeys {
+		keys[path] = key
+	}
+	return keys
+}
+
+// isListMapKey returns true if every item of the provided lists is a map
+// with the provided key
+func isListMapKey(mergeKey string, lists ...[]interface{}) bool {
+	for _, list := range lists {
+		for _, item := range list {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			if _, ok := itemMap[mergeKey]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // detectListMapKey tries to guess whether a field is a
 // k8s-style "list of maps".
 //
@@ -341,6 +490,12 @@ var knownMergeKeys = []string{
 //	If any particular list is empty then common keys will be formed
 // out of non-empty lists.
 func detectListMapKey(lists ...[]interface{}) string {
+	return detectListMapKeyFrom(knownMergeKeys, lists...)
+}
+
+// detectListMapKeyFrom is same as detectListMapKey but guesses the
+// merge key from the provided merge keys
+func detectListMapKeyFrom(mergeKeys []string, lists ...[]interface{}) string {
 	// Remember the set of keys that every object has in common
 	var commonKeys map[string]bool
 
@@ -377,7 +532,7 @@ func detectListMapKey(lists ...[]interface{}) string {
 	}
 	// If all objects have **one** of the known conventional
 	// merge keys in common, we'll guess that this is a list map.
-	for _, key := range knownMergeKeys {
+	for _, key := range mergeKeys {
 		if commonKeys[key] {
 			// first possible match is the merge key
 			//
@@ -1992,6 +1992,137 @@ func TestMerge(t *testing.T) {
 	}
 }
 
+func TestMergeWithOptions(t *testing.T) {
+	observed := `{
+		"spec": {"rules": [{"type": "a", "v": 1}, {"type": "b", "v": 1}]},
+		"status": {"conditions": [
+			{"type": "Ready", "status": "False"},
+			{"type": "Synced", "status": "True"}
+		]}
+	}`
+	desired := `{
+		"spec": {"rules": [{"type": "a", "v": 2}]},
+		"status": {"conditions": [{"type": "Ready", "status": "True"}]}
+	}`
+	table := []struct {
+		name string
+		opts MergeOptions
+		want string
+	}{
+		{
+			name: "status lists are replaced by default",
+			want: `{
+				"spec": {"rules": [{"type": "a", "v": 2}]},
+				"status": {"conditions": [{"type": "Ready", "status": "True"}]}
+			}`,
+		},
+		{
+			name: "status conditions are merged by type when status is included",
+			opts: MergeOptions{IncludeStatus: true},
+			want: `{
+				"spec": {"rules": [{"type": "a", "v": 2}]},
+				"status": {"conditions": [
+					{"type": "Ready", "status": "True"},
+					{"type": "Synced", "status": "True"}
+				]}
+			}`,
+		},
+		{
+			name: "lists are merged by extra merge keys",
+			opts: MergeOptions{ExtraMergeKeys: []string{"type"}},
+			want: `{
+				"spec": {"rules": [{"type": "a", "v": 2}, {"type": "b", "v": 1}]},
+				"status": {"conditions": [
+					{"type": "Ready", "status": "True"},
+					{"type": "Synced", "status": "True"}
+				]}
+			}`,
+		},
+	}
+
+	for _, tc := range table {
+		observedMap := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(observed), &observedMap); err != nil {
+			t.Fatalf("Can't unmarshal observed: %v", err)
+		}
+		desiredMap := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(desired), &desiredMap); err != nil {
+			t.Fatalf("Can't unmarshal desired: %v", err)
+		}
+		want := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(tc.want), &want); err != nil {
+			t.Errorf("%s: Can't unmarshal tc.want: %v", tc.name, err)
+			continue
+		}
+
+		got, err := MergeWithOptions(observedMap, nil, desiredMap, tc.opts)
+		if err != nil {
+			t.Errorf("%s: MergeWithOptions error: %v", tc.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\nGot: %v\nWant: %v\nDiff: %s",
+				tc.name, got, want, diff.ObjectReflectDiff(got, want),
+			)
+		}
+	}
+}
+
+func TestMergeWithRegisteredMergeKey(t *testing.T) {
+	const volumeMountsPath = "spec.template.spec.containers[].volumeMounts"
+
+	// the same volume is mounted at two paths
+	observed := `{"spec": {"template": {"spec": {"containers": [
+		{"name": "app", "volumeMounts": [{"name": "data", "mountPath": "/a"}]}
+	]}}}}`
+	desired := `{"spec": {"template": {"spec": {"containers": [
+		{"name": "app", "volumeMounts": [
+			{"name": "data", "mountPath": "/a"},
+			{"name": "data", "mountPath": "/b", "subPath": "b"}
+		]}
+	]}}}}`
+	want := `{"spec": {"template": {"spec": {"containers": [
+		{"name": "app", "volumeMounts": [
+			{"name": "data", "mountPath": "/a"},
+			{"name": "data", "mountPath": "/b", "subPath": "b"}
+		]}
+	]}}}}`
+
+	observedMap := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(observed), &observedMap); err != nil {
+		t.Fatalf("Can't unmarshal observed: %v", err)
+	}
+	desiredMap := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(desired), &desiredMap); err != nil {
+		t.Fatalf("Can't unmarshal desired: %v", err)
+	}
+	wantMap := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(want), &wantMap); err != nil {
+		t.Fatalf("Can't unmarshal want: %v", err)
+	}
+
+	// guessed merge key i.e. name mis-merges the volume mounts
+	got, err := Merge(observedMap, nil, desiredMap)
+	if err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if reflect.DeepEqual(got, wantMap) {
+		t.Fatalf("Expected mis-merge without registered merge key: Got: %v", got)
+	}
+
+	RegisterMergeKey(volumeMountsPath, "mountPath")
+	defer UnregisterMergeKey(volumeMountsPath)
+
+	got, err = Merge(observedMap, nil, desiredMap)
+	if err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if !reflect.DeepEqual(got, wantMap) {
+		t.Errorf("Got: %v\nWant: %v\nDiff: %s", got, wantMap, diff.ObjectReflectDiff(got, wantMap))
+	}
+}
+
 func TestLastAppliedAnnotation(t *testing.T) {
 	// Round-trip some JSON through Set/Get methods.
 	inJSON := `{
@@ -0,0 +1,46 @@
+// Package helm renders Helm charts into Kubernetes API objects
+//
+// Note: This package is a separate module with its own go.mod
+package helm
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+)
+
+// BuildObjectsFromHelmChart renders the templates of the chart found in
+// the provided path into unstructured Kubernetes API objects. This is
+// same as running helm template against the chart with the provided
+// values, release name & namespace.
+//
+// Note: The chart is rendered in client only mode i.e. without talking
+// to the cluster. CRDs of the chart are included while its hooks are
+// not.
+func BuildObjectsFromHelmChart(chartPath string, values map[string]interface{}, releaseName, namespace string) ([]*unstructured.Unstructured, error) {
+	chart, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load chart %q", chartPath)
+	}
+
+	install := action.NewInstall(&action.Configuration{
+		Log: func(string, ...interface{}) {},
+	})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = true
+	install.ReleaseName = releaseName
+	install.Namespace = namespace
+
+	release, err := install.Run(chart, values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "render chart %q", chartPath)
+	}
+	return k8sutil.ReadKubernetesObjects(strings.NewReader(release.Manifest))
+}
@@ -0,0 +1,61 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+)
+
+func TestBuildObjectsFromHelmChart(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name         string
+		chartPath    string
+		values       map[string]interface{}
+		expectedData map[string]string
+		isError      bool
+	}{
+		{
+			name:         "should render the chart with its default values",
+			chartPath:    "testdata/minimal",
+			expectedData: map[string]string{"env": "dev", "replicas": "1"},
+		},
+		{
+			name:         "should render the chart with the provided values",
+			chartPath:    "testdata/minimal",
+			values:       map[string]interface{}{"env": "staging"},
+			expectedData: map[string]string{"env": "staging", "replicas": "1"},
+		},
+		{
+			name:      "should fail for invalid chart path",
+			chartPath: "testdata/missing",
+			isError:   true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			objs, err := BuildObjectsFromHelmChart(scenario.chartPath, scenario.values, "checks", "testing")
+			if scenario.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if !assert.Len(t, objs, 1) {
+				return
+			}
+			assert.Equal(t, "checks-settings", objs[0].GetName())
+			assert.Equal(t, "testing", objs[0].GetNamespace())
+			var cm corev1.ConfigMap
+			assert.NoError(t, k8sutil.ToTyped(objs[0], &cm))
+			assert.Equal(t, scenario.expectedData, cm.Data)
+		})
+	}
+}
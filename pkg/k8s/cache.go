@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectCache caches the objects fetched by Get. Objects are keyed by
+// k8sutil.ObjKey & are invalidated by the operations that write to the
+// cluster e.g. Create, Update, Patch, Upsert, Apply, Delete & Scale. This
+// reduces the API calls of read heavy checks e.g. a Job that gets the
+// same object across its steps.
+//
+// Note: Writes made by others e.g. controllers are not observed by the
+// cache. Hence this should be used for objects that are not expected to
+// change outside the run.
+//
+// Note: This is safe for concurrent use
+type ObjectCache struct {
+	mu      sync.RWMutex
+	objects map[string]client.Object
+}
+
+// NewObjectCache returns a new instance of ObjectCache
+func NewObjectCache() *ObjectCache {
+	return &ObjectCache{
+		objects: map[string]client.Object{},
+	}
+}
+
+// get returns a copy of the cached object with the key of the provided
+// object. Objects of a type other than that of the provided object are
+// not returned.
+func (c *ObjectCache) get(given client.Object) (client.Object, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cached, found := c.objects[k8sutil.ObjKey(given)]
+	if !found || reflect.TypeOf(cached) != reflect.TypeOf(given) {
+		return nil, false
+	}
+	return cached.DeepCopyObject().(client.Object), true
+}
+
+// set caches a copy of the provided object
+func (c *ObjectCache) set(obj client.Object) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.objects[k8sutil.ObjKey(obj)] = obj.DeepCopyObject().(client.Object)
+}
+
+// Invalidate removes the provided object from the cache
+func (c *ObjectCache) Invalidate(obj client.Object) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.objects, k8sutil.ObjKey(obj))
+}
+
+// Len returns the number of cached objects
+func (c *ObjectCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.objects)
+}
+
+// Reset removes all the cached objects
+func (c *ObjectCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.objects = map[string]client.Object{}
+}
+
+// invalidateCache removes the provided object from the cache set in the
+// provided options if any
+//
+// Note: The object's namespace defaults to RunOptions.DefaultNamespace
+// similar to Get
+func invalidateCache(opts *RunOptions, obj client.Object) {
+	if opts == nil || opts.Cache == nil || obj == nil {
+		return
+	}
+	if defaulted, err := setDefaultNamespace(opts, obj); err == nil {
+		obj = defaulted
+	}
+	opts.Cache.Invalidate(obj)
+}
@@ -0,0 +1,223 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/simplekube/kit/pkg/pointer"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetWithCache(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cached", Namespace: "default"},
+		Data:       map[string]string{"color": "red"},
+	}
+	unstruct := &unstructured.Unstructured{}
+	unstruct.SetAPIVersion("v1")
+	unstruct.SetKind("ConfigMap")
+	unstruct.SetName("cached")
+	unstruct.SetNamespace("default")
+
+	var scenarios = []struct {
+		name          string
+		write         func(ctx context.Context, opts RunOption) error
+		expectedGets  int // gets that reach the client after the write
+		expectedColor string
+	}{
+		{
+			name:          "should serve repeated gets from the cache",
+			expectedGets:  0,
+			expectedColor: "red",
+		},
+		{
+			name: "should invalidate the cache after an update",
+			write: func(ctx context.Context, opts RunOption) error {
+				actual, err := Get(ctx, cm, opts)
+				if err != nil {
+					return err
+				}
+				actual.(*corev1.ConfigMap).Data["color"] = "blue"
+				_, err = Update(ctx, actual, opts)
+				return err
+			},
+			expectedGets:  1,
+			expectedColor: "blue",
+		},
+		{
+			name: "should invalidate the cache after an upsert",
+			write: func(ctx context.Context, opts RunOption) error {
+				desired := cm.DeepCopy()
+				desired.Data = map[string]string{"color": "green"}
+				_, err := Upsert(ctx, desired, opts)
+				return err
+			},
+			expectedGets:  1,
+			expectedColor: "green",
+		},
+		{
+			name: "should not serve objects of another type",
+			write: func(ctx context.Context, opts RunOption) error {
+				_, err := Get(ctx, unstruct, opts)
+				return err
+			},
+			expectedGets:  1,
+			expectedColor: "red",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			cli := &callCountingClient{Client: fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build()}
+			opts := &RunOptions{Client: cli, Cache: NewObjectCache()}
+
+			_, err := Get(ctx, cm, opts)
+			assert.NoError(t, err)
+			if scenario.write != nil {
+				assert.NoError(t, scenario.write(ctx, opts))
+			}
+			cli.gets = 0
+			for i := 0; i < 3; i++ {
+				got, err := Get(ctx, cm, opts)
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expectedColor, got.(*corev1.ConfigMap).Data["color"])
+			}
+			assert.Equal(t, scenario.expectedGets, cli.gets)
+		})
+	}
+}
+
+func TestObjectCacheIsSafeForConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	var objs []client.Object
+	for i := 0; i < 10; i++ {
+		objs = append(objs, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("concurrent-%d", i), Namespace: "default"},
+		})
+	}
+	cli := fake.NewClientBuilder().WithObjects(objs...).Build()
+	opts := &RunOptions{Client: cli, Cache: NewObjectCache()}
+
+	var wg sync.WaitGroup
+	for _, obj := range objs {
+		wg.Add(2)
+		go func(obj client.Object) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				_, err := Get(context.Background(), obj, opts)
+				assert.NoError(t, err)
+			}
+		}(obj)
+		go func(obj client.Object) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				opts.Cache.Invalidate(obj)
+			}
+		}(obj)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, opts.Cache.Len(), len(objs))
+}
+
+// scaleServer serves the scale subresource of the deployments found in
+// the provided client similar to the api server
+func scaleServer(cli client.Client) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// path is of the form /apis/apps/v1/namespaces/<ns>/deployments/<name>/scale
+		segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/apis/apps/v1/namespaces/"), "/")
+		if len(segments) != 4 || segments[1] != "deployments" || segments[3] != "scale" {
+			http.NotFound(w, r)
+			return
+		}
+		deploy := &appsv1.Deployment{}
+		if err := cli.Get(r.Context(), client.ObjectKey{Namespace: segments[0], Name: segments[2]}, deploy); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPut {
+			var scale autoscalingv1.Scale
+			if err := json.NewDecoder(r.Body).Decode(&scale); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			deploy.Spec.Replicas = pointer.Int32(scale.Spec.Replicas)
+			if err := cli.Update(r.Context(), deploy); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&autoscalingv1.Scale{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "autoscaling/v1", Kind: "Scale"},
+			ObjectMeta: metav1.ObjectMeta{Name: deploy.Name, Namespace: deploy.Namespace},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: *deploy.Spec.Replicas},
+		})
+	}))
+}
+
+func TestCacheIsInvalidatedByScale(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "scaled", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(1)},
+	}
+	cli := fake.NewClientBuilder().WithObjects(deploy.DeepCopy()).Build()
+	srv := scaleServer(cli)
+	defer srv.Close()
+	cs, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL})
+	assert.NoError(t, err)
+	opts := &RunOptions{Client: cli, Clientset: cs, Cache: NewObjectCache()}
+
+	_, err = Get(ctx, deploy, opts)
+	assert.NoError(t, err)
+	_, err = Scale(ctx, deploy, 3, opts)
+	assert.NoError(t, err)
+	got, err := Get(ctx, deploy, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), *got.(*appsv1.Deployment).Spec.Replicas)
+}
+
+func TestCacheIsInvalidatedByEnsureNamespace(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cli := &activatingClient{Client: fake.NewClientBuilder().Build()}
+	opts := &RunOptions{Client: cli, Cache: NewObjectCache()}
+	// a namespace that was observed before it was deleted out of band
+	opts.Cache.set(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "recreated", Labels: map[string]string{"stale": "true"}},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	})
+
+	_, err := EnsureNamespace(ctx, "recreated", opts)
+	assert.NoError(t, err)
+	got, err := Get(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "recreated"}}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.NamespaceActive, got.(*corev1.Namespace).Status.Phase)
+	assert.Empty(t, got.GetLabels())
+}
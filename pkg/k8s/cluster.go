@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// ClusterSet holds the run options of a set of named clusters to run the
+// same runner e.g. a Job against each of them.
+//
+// Note: This is safe for concurrent use
+type ClusterSet struct {
+	mu       sync.RWMutex
+	clusters map[string]*RunOptions
+	names    []string
+}
+
+// NewClusterSet returns a new instance of ClusterSet
+func NewClusterSet() *ClusterSet {
+	return &ClusterSet{
+		clusters: map[string]*RunOptions{},
+	}
+}
+
+// Add adds the cluster with the provided name & options to the set. The
+// options must set either the client or the rest config of the cluster.
+// A garbage collector is set for the cluster if the options do not set
+// a registrar.
+func (c *ClusterSet) Add(name string, options ...RunOption) error {
+	if c == nil {
+		return errors.New("nil cluster set")
+	}
+	if name == "" {
+		return errors.New("empty cluster name")
+	}
+	opts, err := FromRunOptions(options...)
+	if err != nil {
+		return errors.Wrapf(err, "cluster %q", name)
+	}
+	if opts.Client == nil && opts.RestConfig == nil {
+		return errors.Errorf("cluster %q: nil client & rest config", name)
+	}
+	if opts.GCRegistrar == nil {
+		opts.GCRegistrar = NewGarbageCollector()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clusters == nil {
+		c.clusters = map[string]*RunOptions{}
+	}
+	if _, found := c.clusters[name]; found {
+		return errors.Errorf("cluster %q is already added", name)
+	}
+	c.clusters[name] = opts
+	c.names = append(c.names, name)
+	return nil
+}
+
+// Names returns the names of the clusters in the order of their addition
+func (c *ClusterSet) Names() []string {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, len(c.names))
+	copy(names, c.names)
+	return names
+}
+
+// Options returns the run options of the cluster with the provided name
+func (c *ClusterSet) Options(name string) (*RunOptions, error) {
+	if c == nil {
+		return nil, errors.New("nil cluster set")
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	opts, found := c.clusters[name]
+	if !found {
+		return nil, errors.Errorf("cluster %q not found", name)
+	}
+	return opts, nil
+}
+
+// RunAcross runs the provided runner against each of the named clusters
+// one after the other irrespective of their failures. All the clusters
+// are used if no names are provided. Errors are labeled with the names of
+// their clusters & returned as an aggregate.
+//
+// Note: The options of a cluster take precedence over the provided
+// options e.g. the cluster's client is used even if the provided options
+// set a client
+func (c *ClusterSet) RunAcross(ctx context.Context, clusterNames []string, runner Runner, options ...RunOption) error {
+	if c == nil {
+		return errors.New("nil cluster set")
+	}
+	if runner == nil {
+		return errors.New("nil runner")
+	}
+	if len(clusterNames) == 0 {
+		clusterNames = c.Names()
+	}
+	var finalError *multierror.Error
+	for _, name := range clusterNames {
+		clusterOpts, err := c.Options(name)
+		if err != nil {
+			finalError = multierror.Append(finalError, err)
+			continue
+		}
+		runOptions := append(append([]RunOption{}, options...), clusterOpts)
+		if err := runner.Run(ctx, runOptions...); err != nil {
+			finalError = multierror.Append(finalError, errors.Wrapf(err, "cluster %q", name))
+		}
+	}
+	return finalError.ErrorOrNil()
+}
+
+// Teardown runs the garbage collector of each cluster against that
+// cluster irrespective of their failures & then resets the garbage
+// collectors of type *BaseRegistrar. Errors are labeled with the names
+// of their clusters & returned as an aggregate.
+func (c *ClusterSet) Teardown(ctx context.Context) error {
+	if c == nil {
+		return errors.New("nil cluster set")
+	}
+	var finalError *multierror.Error
+	for _, name := range c.Names() {
+		clusterOpts, err := c.Options(name)
+		if err != nil {
+			finalError = multierror.Append(finalError, err)
+			continue
+		}
+		if registrar, ok := clusterOpts.GCRegistrar.(*BaseRegistrar); ok {
+			err = TeardownAndReset(ctx, registrar, clusterOpts)
+		} else {
+			err = Teardown(ctx, clusterOpts.GCRegistrar, clusterOpts)
+		}
+		if err != nil {
+			finalError = multierror.Append(finalError, errors.Wrapf(err, "cluster %q", name))
+		}
+	}
+	return finalError.ErrorOrNil()
+}
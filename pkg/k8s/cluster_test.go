@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// createRunner creates a copy of the provided object when run
+type createRunner struct {
+	obj client.Object
+}
+
+func (r *createRunner) Run(ctx context.Context, opts ...RunOption) error {
+	_, err := Create(ctx, r.obj.DeepCopyObject().(client.Object), opts...)
+	return err
+}
+
+func TestClusterSetAdd(t *testing.T) {
+	t.Parallel()
+
+	set := NewClusterSet()
+	assert.NoError(t, set.Add("east", &RunOptions{Client: fake.NewClientBuilder().Build()}))
+	assert.Error(t, set.Add("east", &RunOptions{Client: fake.NewClientBuilder().Build()}), "duplicate name")
+	assert.Error(t, set.Add("", &RunOptions{Client: fake.NewClientBuilder().Build()}), "empty name")
+	assert.Error(t, set.Add("west"), "nil client & rest config")
+	assert.Equal(t, []string{"east"}, set.Names())
+
+	opts, err := set.Options("east")
+	assert.NoError(t, err)
+	assert.NotNil(t, opts.GCRegistrar, "should set a garbage collector per cluster")
+	_, err = set.Options("west")
+	assert.Error(t, err)
+}
+
+func TestClusterSetRunAcross(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "across", Namespace: "default"}}
+	clients := map[string]client.Client{
+		"east": fake.NewClientBuilder().Build(),
+		"west": fake.NewClientBuilder().Build(),
+	}
+	set := NewClusterSet()
+	for _, name := range []string{"east", "west"} {
+		assert.NoError(t, set.Add(name, &RunOptions{Client: clients[name]}))
+	}
+
+	// run against all the clusters
+	err := set.RunAcross(ctx, nil, &Job{Runners: []Runner{&createRunner{obj: cm}}})
+	assert.NoError(t, err)
+	for name, cli := range clients {
+		_, err := Get(ctx, cm, &RunOptions{Client: cli})
+		assert.NoError(t, err, name)
+		opts, _ := set.Options(name)
+		assert.Len(t, opts.GCRegistrar.GetKeys(), 1, "should register in the cluster's garbage collector: %s", name)
+	}
+
+	// errors are labeled with their clusters
+	err = set.RunAcross(ctx, []string{"west", "north"}, &createRunner{obj: cm})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `cluster "west"`)
+	assert.Contains(t, err.Error(), "already exists")
+	assert.Contains(t, err.Error(), `cluster "north" not found`)
+	assert.NotContains(t, err.Error(), `cluster "east"`)
+
+	// teardown deletes the objects from their own clusters
+	assert.NoError(t, set.Teardown(ctx))
+	for name, cli := range clients {
+		_, err := Get(ctx, cm, &RunOptions{Client: cli})
+		assert.True(t, apierrors.IsNotFound(errors.Cause(err)), name)
+		opts, _ := set.Options(name)
+		assert.Empty(t, opts.GCRegistrar.GetKeys(), name)
+	}
+}
@@ -0,0 +1,91 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ApplyConflict is a field that could not be applied since it is owned
+// by another field manager
+type ApplyConflict struct {
+	// Manager is the field manager that owns the field
+	Manager string
+
+	// Field is the path of the field e.g. .spec.replicas
+	Field string
+}
+
+// ApplyConflictError is returned by Apply when the conflict strategy is
+// ConflictStrategyReport & the applied fields conflict with the fields
+// owned by other field managers
+type ApplyConflictError struct {
+	Key       client.ObjectKey
+	Conflicts []ApplyConflict
+}
+
+// Error implements the error interface
+func (e *ApplyConflictError) Error() string {
+	var conflicts = make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		conflicts = append(conflicts, fmt.Sprintf("%s owned by %q", c.Field, c.Manager))
+	}
+	return fmt.Sprintf("apply conflicts: %s: %s", e.Key, strings.Join(conflicts, ", "))
+}
+
+// IsApplyConflictError returns true if the provided error is due to
+// conflicts with the fields owned by other field managers
+func IsApplyConflictError(err error) bool {
+	var conflictErr *ApplyConflictError
+	return errors.As(err, &conflictErr)
+}
+
+// conflictManagerRegex extracts the field manager from the message of a
+// conflict cause e.g. conflict with "kubectl" using apps/v1
+var conflictManagerRegex = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// parseApplyConflicts returns the conflicting fields & their managers
+// found in the causes of the provided server side apply conflict error
+func parseApplyConflicts(err error) []ApplyConflict {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+	var conflicts []ApplyConflict
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		var conflict = ApplyConflict{Field: cause.Field}
+		if match := conflictManagerRegex.FindStringSubmatch(cause.Message); len(match) == 2 {
+			conflict.Manager = match[1]
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// ParseApplyConflict returns the conflicting fields & their managers of
+// the provided server side apply conflict error. It returns false if the
+// error is not due to conflicts with other field managers.
+//
+// Note: Wrapped API errors as well as *ApplyConflictError are supported
+func ParseApplyConflict(err error) ([]ApplyConflict, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var conflictErr *ApplyConflictError
+	if errors.As(err, &conflictErr) {
+		return conflictErr.Conflicts, len(conflictErr.Conflicts) != 0
+	}
+	if !apierrors.IsConflict(err) {
+		return nil, false
+	}
+	conflicts := parseApplyConflicts(err)
+	return conflicts, len(conflicts) != 0
+}
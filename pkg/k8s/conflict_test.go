@@ -0,0 +1,244 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fieldManagerClient simulates the field ownership of server side apply
+// for the data keys of config maps. An apply that sets a key owned by
+// another field manager conflicts unless the ownership is forced.
+//
+// Note: The fake client does not support server side apply
+type fieldManagerClient struct {
+	client.Client
+	owners map[string]string
+}
+
+func (c *fieldManagerClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	var patchOpts = &client.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	cm := obj.(*corev1.ConfigMap)
+
+	var causes []metav1.StatusCause
+	var keys []string
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field := fmt.Sprintf(".data.%s", key)
+		owner, found := c.owners[field]
+		if !found || owner == patchOpts.FieldManager {
+			continue
+		}
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: fmt.Sprintf("conflict with %q: %s", owner, field),
+			Field:   field,
+		})
+	}
+	if len(causes) != 0 && (patchOpts.Force == nil || !*patchOpts.Force) {
+		return apierrors.NewApplyConflict(causes, fmt.Sprintf("Apply failed with %d conflicts", len(causes)))
+	}
+	for _, key := range keys {
+		c.owners[fmt.Sprintf(".data.%s", key)] = patchOpts.FieldManager
+	}
+	return nil
+}
+
+func TestApplyWithConflictStrategy(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name              string
+		strategy          ConflictStrategy
+		isError           bool
+		isConflictError   bool
+		expectedConflicts []ApplyConflict
+		expectedOwner     string
+	}{
+		{
+			name:          "should force the ownership by default",
+			expectedOwner: "controller-b",
+		},
+		{
+			name:          "should force the ownership with force strategy",
+			strategy:      ConflictStrategyForce,
+			expectedOwner: "controller-b",
+		},
+		{
+			name:     "should fail on conflicts with fail strategy",
+			strategy: ConflictStrategyFail,
+			isError:  true,
+			expectedConflicts: []ApplyConflict{
+				{Manager: "controller-a", Field: ".data.color"},
+			},
+			expectedOwner: "controller-a",
+		},
+		{
+			name:            "should report the conflicts with report strategy",
+			strategy:        ConflictStrategyReport,
+			isError:         true,
+			isConflictError: true,
+			expectedConflicts: []ApplyConflict{
+				{Manager: "controller-a", Field: ".data.color"},
+			},
+			expectedOwner: "controller-a",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := &fieldManagerClient{
+				Client: fake.NewClientBuilder().Build(),
+				owners: map[string]string{},
+			}
+			cm := func(data map[string]string) *corev1.ConfigMap {
+				return &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "two-managers", Namespace: "default"},
+					Data:       data,
+				}
+			}
+
+			_, err := Apply(context.Background(), cm(map[string]string{"color": "red"}), &RunOptions{
+				Client:     cli,
+				FieldOwner: "controller-a",
+			})
+			assert.NoError(t, err)
+
+			_, err = Apply(context.Background(), cm(map[string]string{"color": "blue", "size": "large"}), &RunOptions{
+				Client:           cli,
+				FieldOwner:       "controller-b",
+				ConflictStrategy: scenario.strategy,
+			})
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, scenario.isConflictError, IsApplyConflictError(err))
+			if scenario.isConflictError {
+				var conflictErr *ApplyConflictError
+				assert.ErrorAs(t, err, &conflictErr)
+				assert.Equal(t, scenario.expectedConflicts, conflictErr.Conflicts)
+			}
+			conflicts, _ := ParseApplyConflict(err)
+			assert.Equal(t, scenario.expectedConflicts, conflicts)
+			assert.Equal(t, scenario.expectedOwner, cli.owners[".data.color"])
+		})
+	}
+}
+
+func TestApplyWithInvalidConflictStrategy(t *testing.T) {
+	t.Parallel()
+
+	_, err := Apply(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid-strategy", Namespace: "default"},
+	}, &RunOptions{
+		Client:           fake.NewClientBuilder().Build(),
+		ConflictStrategy: "Ignore",
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported conflict strategy")
+}
+
+func TestParseApplyConflict(t *testing.T) {
+	t.Parallel()
+
+	applyConflict := apierrors.NewApplyConflict([]metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kubectl-client-side-apply" using apps/v1`,
+			Field:   ".spec.replicas",
+		},
+		{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "not a conflict",
+			Field:   ".spec.selector",
+		},
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "hpa-controller" using apps/v1`,
+			Field:   `.spec.template.spec.containers[name="app"].image`,
+		},
+	}, "Apply failed with 2 conflicts")
+	expectedConflicts := []ApplyConflict{
+		{Manager: "kubectl-client-side-apply", Field: ".spec.replicas"},
+		{Manager: "hpa-controller", Field: `.spec.template.spec.containers[name="app"].image`},
+	}
+
+	var scenarios = []struct {
+		name              string
+		err               error
+		expectedConflicts []ApplyConflict
+		expectedOK        bool
+	}{
+		{
+			name:              "should parse the conflicts of a server side apply error",
+			err:               applyConflict,
+			expectedConflicts: expectedConflicts,
+			expectedOK:        true,
+		},
+		{
+			name:              "should parse the conflicts of a wrapped error",
+			err:               errors.Wrap(applyConflict, "failed to apply"),
+			expectedConflicts: expectedConflicts,
+			expectedOK:        true,
+		},
+		{
+			name:              "should parse the conflicts of an error wrapped via fmt",
+			err:               fmt.Errorf("apply: %w", applyConflict),
+			expectedConflicts: expectedConflicts,
+			expectedOK:        true,
+		},
+		{
+			name: "should return the conflicts of an apply conflict error",
+			err: &ApplyConflictError{Conflicts: []ApplyConflict{
+				{Manager: "controller-a", Field: ".data.color"},
+			}},
+			expectedConflicts: []ApplyConflict{
+				{Manager: "controller-a", Field: ".data.color"},
+			},
+			expectedOK: true,
+		},
+		{
+			name: "should not parse a conflict due to a stale resource version",
+			err: apierrors.NewConflict(
+				schema.GroupResource{Resource: "configmaps"}, "stale", errors.New("object was modified"),
+			),
+		},
+		{
+			name: "should not parse an error other than conflict",
+			err:  apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "missing"),
+		},
+		{
+			name: "should not parse nil error",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			conflicts, ok := ParseApplyConflict(scenario.err)
+			assert.Equal(t, scenario.expectedOK, ok)
+			assert.Equal(t, scenario.expectedConflicts, conflicts)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// configMapGroupKind is the group & kind of a ConfigMap
+var configMapGroupKind = schema.GroupKind{Kind: "ConfigMap"}
+
+// AssertDataRunner asserts that the provided ConfigMap or Secret has the
+// provided key with the expected value when run. The value of a Secret
+// is compared after it is base64 decoded.
+//
+// Note: The values of a Secret or of a kind set in RunOptions.RedactKinds
+// are never included in the returned error
+type AssertDataRunner struct {
+	Resource      client.Object
+	Key           string
+	ExpectedValue string
+}
+
+// compile time check to assert if the structure
+// AssertDataRunner implements the interface Runner
+var _ Runner = (*AssertDataRunner)(nil)
+
+// Run fetches the resource & compares the value of the key
+func (a *AssertDataRunner) Run(ctx context.Context, options ...RunOption) error {
+	if a == nil || a.Resource == nil {
+		return errors.New("nil assert data runner")
+	}
+	if a.Key == "" {
+		return errors.Errorf("%s: empty key", a)
+	}
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	gvk, err := gvkForObject(a.Resource, opts.Scheme)
+	if err != nil {
+		return errors.Wrap(err, "extract gvk")
+	}
+	if gk := gvk.GroupKind(); gk != configMapGroupKind && gk != secretGroupKind {
+		return errors.Errorf("%s: unsupported kind %s: want ConfigMap or Secret", a, gvk.Kind)
+	}
+	actual, err := Get(ctx, a.Resource, opts)
+	if err != nil {
+		return errors.Wrapf(err, "%s", a)
+	}
+
+	switch gvk.GroupKind() {
+	case configMapGroupKind:
+		var cm corev1.ConfigMap
+		if err := toTypedObject(actual, &cm); err != nil {
+			return err
+		}
+		value, found := cm.Data[a.Key]
+		if !found {
+			binaryValue, isBinary := cm.BinaryData[a.Key]
+			value, found = string(binaryValue), isBinary
+		}
+		if !found {
+			return errors.Errorf("%s: key %q not found", a, a.Key)
+		}
+		if value == a.ExpectedValue {
+			return nil
+		}
+		if newRedactor(opts).isRedacted(actual) {
+			return errors.Errorf("%s: key %q: value does not match the expected value", a, a.Key)
+		}
+		return errors.Errorf("%s: key %q: want %q got %q", a, a.Key, a.ExpectedValue, value)
+	case secretGroupKind:
+		var secret corev1.Secret
+		if err := toTypedObject(actual, &secret); err != nil {
+			return err
+		}
+		value, found := secret.Data[a.Key]
+		if !found {
+			return errors.Errorf("%s: key %q not found", a, a.Key)
+		}
+		if string(value) != a.ExpectedValue {
+			return errors.Errorf("%s: key %q: value does not match the expected value", a, a.Key)
+		}
+	}
+	return nil
+}
+
+// String describes the runner
+func (a *AssertDataRunner) String() string {
+	if a == nil || a.Resource == nil {
+		return "assert data"
+	}
+	return fmt.Sprintf("assert data %s/%s", a.Resource.GetNamespace(), a.Resource.GetName())
+}
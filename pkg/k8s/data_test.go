@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAssertDataRunner(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"env": "staging"},
+		BinaryData: map[string][]byte{"blob": []byte("binary")},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}
+	unstructSecret := &unstructured.Unstructured{}
+	unstructSecret.SetAPIVersion("v1")
+	unstructSecret.SetKind("Secret")
+	unstructSecret.SetName("creds")
+	unstructSecret.SetNamespace("default")
+	cli := fake.NewClientBuilder().WithObjects(cm.DeepCopy(), secret.DeepCopy()).Build()
+
+	var scenarios = []struct {
+		name           string
+		resource       client.Object
+		key            string
+		expectedValue  string
+		isError        bool
+		errContains    string
+		redactKinds    []schema.GroupKind
+		errNotContains string
+	}{
+		{
+			name:          "should pass when the config map has the key with the expected value",
+			resource:      cm,
+			key:           "env",
+			expectedValue: "staging",
+		},
+		{
+			name:          "should pass when the config map has the binary key with the expected value",
+			resource:      cm,
+			key:           "blob",
+			expectedValue: "binary",
+		},
+		{
+			name:          "should fail with both the values when the config map value differs",
+			resource:      cm,
+			key:           "env",
+			expectedValue: "prod",
+			isError:       true,
+			errContains:   `key "env": want "prod" got "staging"`,
+		},
+		{
+			name:           "should fail without the values when the config map is redacted",
+			resource:       cm,
+			key:            "env",
+			expectedValue:  "prod",
+			redactKinds:    []schema.GroupKind{{Kind: "ConfigMap"}},
+			isError:        true,
+			errContains:    `key "env": value does not match`,
+			errNotContains: "staging",
+		},
+		{
+			name:        "should fail naming the key that is absent in the config map",
+			resource:    cm,
+			key:         "missing",
+			isError:     true,
+			errContains: `key "missing" not found`,
+		},
+		{
+			name:          "should pass when the secret has the key with the decoded value",
+			resource:      secret,
+			key:           "password",
+			expectedValue: "s3cr3t",
+		},
+		{
+			name:          "should pass for unstructured secret",
+			resource:      unstructSecret,
+			key:           "password",
+			expectedValue: "s3cr3t",
+		},
+		{
+			name:           "should fail without the values when the secret value differs",
+			resource:       secret,
+			key:            "password",
+			expectedValue:  "guess",
+			isError:        true,
+			errContains:    `key "password": value does not match`,
+			errNotContains: "s3cr3t",
+		},
+		{
+			name:        "should fail naming the key that is absent in the secret",
+			resource:    secret,
+			key:         "token",
+			isError:     true,
+			errContains: `key "token" not found`,
+		},
+		{
+			name:        "should fail for unsupported kind",
+			resource:    &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			key:         "env",
+			isError:     true,
+			errContains: "unsupported kind Namespace",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &AssertDataRunner{
+				Resource:      scenario.resource,
+				Key:           scenario.key,
+				ExpectedValue: scenario.expectedValue,
+			}
+			err := r.Run(context.Background(), &RunOptions{Client: cli, RedactKinds: scenario.redactKinds})
+			if !scenario.isError {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), scenario.errContains)
+			if scenario.errNotContains != "" {
+				assert.NotContains(t, err.Error(), scenario.errNotContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WaitForDeletion polls the provided object till it is not found. This
+// is useful to wait for objects that are deleted elsewhere e.g. by a
+// controller or by an earlier step. Retry interval & retry timeout are
+// derived from KindDefaults.
+//
+// Note: Use WaitForDeletionRunner to remove the finalizers of an object
+// that is stuck in terminating state
+func WaitForDeletion(ctx context.Context, given client.Object, options ...RunOption) error {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	if given == nil {
+		return errors.New("nil object")
+	}
+	return errors.Wrapf(
+		waitForAbsence(ctx, opts, given),
+		"wait for deletion: %s", k8sutil.DescribeObj(given),
+	)
+}
+
+// WaitForDeletionRunner waits till the provided object is not found when
+// run. Unset interval &/ timeout are derived from KindDefaults.
+type WaitForDeletionRunner struct {
+	Object   client.Object
+	Interval time.Duration
+	Timeout  time.Duration
+
+	// ForceFinalizerRemoval when true removes the finalizers of the
+	// object if it is still terminating after the timeout & waits again
+	// for the object to be deleted. The spec.finalizers of a Namespace
+	// are removed as well via its finalize subresource.
+	//
+	// Note: An object that is not terminating i.e. that has not been
+	// deleted is never updated
+	ForceFinalizerRemoval bool
+}
+
+// compile time check to assert if the structure
+// WaitForDeletionRunner implements the interface Runner
+var _ Runner = (*WaitForDeletionRunner)(nil)
+
+// Run waits for the object to be deleted
+func (w *WaitForDeletionRunner) Run(ctx context.Context, options ...RunOption) error {
+	if w == nil || w.Object == nil {
+		return errors.New("nil wait for deletion runner")
+	}
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	within := EventuallyOptions{
+		RetryInterval: w.Interval,
+		RetryTimeout:  w.Timeout,
+	}
+	err = waitForAbsenceWithin(ctx, opts, w.Object, within)
+	if err == nil || !w.ForceFinalizerRemoval || ctx.Err() != nil {
+		return errors.Wrapf(err, "%s", w)
+	}
+
+	actual, getErr := Get(ctx, w.Object, opts)
+	if apierrors.IsNotFound(errors.Cause(getErr)) {
+		return nil
+	}
+	if getErr != nil {
+		return errors.Wrapf(getErr, "%s", w)
+	}
+	if actual.GetDeletionTimestamp() == nil {
+		return errors.Wrapf(err, "%s: not terminating", w)
+	}
+	finalizers := actual.GetFinalizers()
+	if err := removeAllFinalizers(ctx, opts, actual); err != nil {
+		return errors.Wrapf(err, "%s", w)
+	}
+	gvk, err := gvkForObject(actual, opts.Scheme)
+	if err != nil {
+		return errors.Wrapf(err, "%s: extract gvk", w)
+	}
+	if gvk.GroupKind() == namespaceGroupKind {
+		content, err := toUnstructuredContent(actual)
+		if err != nil {
+			return errors.Wrapf(err, "%s", w)
+		}
+		specFinalizers, _, _ := unstructured.NestedStringSlice(content, "spec", "finalizers")
+		finalizers = append(finalizers, specFinalizers...)
+		if err := finalizeNamespace(ctx, opts, actual.GetName()); err != nil {
+			return errors.Wrapf(err, "%s", w)
+		}
+	}
+	return errors.Wrapf(
+		waitForAbsenceWithin(ctx, opts, w.Object, within),
+		"%s: after removing finalizers %q", w, finalizers,
+	)
+}
+
+// String describes the runner
+func (w *WaitForDeletionRunner) String() string {
+	if w == nil || w.Object == nil {
+		return "wait for deletion"
+	}
+	return fmt.Sprintf("wait for deletion of %s/%s", w.Object.GetNamespace(), w.Object.GetName())
+}
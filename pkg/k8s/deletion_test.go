@@ -0,0 +1,250 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWaitForDeletionRunner(t *testing.T) {
+	t.Parallel()
+
+	protected := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "protected",
+				Namespace:  "default",
+				Finalizers: []string{"protect.io/testing"},
+			},
+		}
+	}
+
+	var scenarios = []struct {
+		name                  string
+		existing              []client.Object
+		isDeleted             bool
+		forceFinalizerRemoval bool
+		isError               bool
+		errContains           string
+		expectedFinalizers    []string
+	}{
+		{
+			name: "should pass when the object is not found",
+		},
+		{
+			name:        "should fail when the object is stuck in terminating state",
+			existing:    []client.Object{protected()},
+			isDeleted:   true,
+			isError:     true,
+			errContains: "still present",
+			expectedFinalizers: []string{
+				"protect.io/testing",
+			},
+		},
+		{
+			name:                  "should remove the finalizers of the object that is stuck in terminating state",
+			existing:              []client.Object{protected()},
+			isDeleted:             true,
+			forceFinalizerRemoval: true,
+		},
+		{
+			name:                  "should not remove the finalizers of the object that is not terminating",
+			existing:              []client.Object{protected()},
+			forceFinalizerRemoval: true,
+			isError:               true,
+			errContains:           "not terminating",
+			expectedFinalizers: []string{
+				"protect.io/testing",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			opts := &RunOptions{Client: fake.NewClientBuilder().WithObjects(scenario.existing...).Build()}
+			if scenario.isDeleted {
+				assert.NoError(t, Delete(ctx, protected(), opts))
+			}
+
+			r := &WaitForDeletionRunner{
+				Object:                protected(),
+				Interval:              10 * time.Millisecond,
+				Timeout:               50 * time.Millisecond,
+				ForceFinalizerRemoval: scenario.forceFinalizerRemoval,
+			}
+			err := r.Run(ctx, opts)
+			if scenario.isError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), scenario.errContains)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			actual, err := Get(ctx, protected(), opts)
+			if scenario.expectedFinalizers == nil {
+				assert.True(t, apierrors.IsNotFound(errors.Cause(err)), "should be deleted")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectedFinalizers, actual.GetFinalizers())
+		})
+	}
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "deleted-elsewhere",
+			Namespace:  "default",
+			Finalizers: []string{"protect.io/testing"},
+		},
+	}
+	opts := &RunOptions{Client: fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build()}
+	assert.NoError(t, Delete(ctx, cm, opts))
+
+	// simulate the controller that owns the finalizer
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, err := RemoveFinalizer(ctx, cm, "protect.io/testing", opts)
+		assert.NoError(t, err)
+	}()
+
+	err := WaitForDeletion(ctx, cm, opts)
+	assert.NoError(t, err)
+}
+
+// namespaceServer serves the get & the finalize subresource of the
+// namespaces found in the provided client similar to the api server i.e.
+// a terminating namespace is deleted once its spec.finalizers are removed
+func namespaceServer(cli client.Client) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// path is of the form /api/v1/namespaces/<name>[/finalize]
+		segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/namespaces/"), "/")
+		ns := &corev1.Namespace{}
+		if err := cli.Get(r.Context(), client.ObjectKey{Name: segments[0]}, ns); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		switch {
+		case len(segments) == 1 && r.Method == http.MethodGet:
+		case len(segments) == 2 && segments[1] == "finalize" && r.Method == http.MethodPut:
+			var desired corev1.Namespace
+			if err := json.NewDecoder(r.Body).Decode(&desired); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ns.Spec.Finalizers = desired.Spec.Finalizers
+			var err error
+			if len(ns.Spec.Finalizers) == 0 && ns.DeletionTimestamp != nil {
+				err = cli.Delete(r.Context(), ns)
+			} else {
+				err = cli.Update(r.Context(), ns)
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		ns.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ns)
+	}))
+}
+
+func TestWaitForDeletionRunnerWithNamespace(t *testing.T) {
+	t.Parallel()
+
+	terminating := func() *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "terminating",
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			},
+			Spec: corev1.NamespaceSpec{
+				Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes},
+			},
+			Status: corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+		}
+	}
+
+	var scenarios = []struct {
+		name                   string
+		forceFinalizerRemoval  bool
+		isError                bool
+		errContains            string
+		expectedSpecFinalizers []corev1.FinalizerName
+	}{
+		{
+			name:                   "should fail when the namespace is stuck in terminating state",
+			isError:                true,
+			errContains:            "still present",
+			expectedSpecFinalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes},
+		},
+		{
+			name:                  "should finalize the namespace that is stuck in terminating state",
+			forceFinalizerRemoval: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			cli := fake.NewClientBuilder().WithObjects(terminating()).Build()
+			srv := namespaceServer(cli)
+			defer srv.Close()
+			cs, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL})
+			assert.NoError(t, err)
+			opts := &RunOptions{Client: cli, Clientset: cs}
+
+			r := &WaitForDeletionRunner{
+				Object:                terminating(),
+				Interval:              10 * time.Millisecond,
+				Timeout:               50 * time.Millisecond,
+				ForceFinalizerRemoval: scenario.forceFinalizerRemoval,
+			}
+			err = r.Run(ctx, opts)
+			if scenario.isError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), scenario.errContains)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			actual := &corev1.Namespace{}
+			err = cli.Get(ctx, client.ObjectKey{Name: "terminating"}, actual)
+			if scenario.expectedSpecFinalizers == nil {
+				assert.True(t, apierrors.IsNotFound(err), "should be deleted")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectedSpecFinalizers, actual.Spec.Finalizers)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// countEvents returns the number of occurrences of the events that match
+// the provided reason & type
+//
+// Note: An empty event type matches all types
+func countEvents(events []corev1.Event, reason, eventType string) int {
+	var count int
+	for _, event := range events {
+		if event.Reason != reason {
+			continue
+		}
+		if eventType != "" && event.Type != eventType {
+			continue
+		}
+		if event.Count > 0 {
+			// similar events are aggregated into a single event
+			count += int(event.Count)
+		} else {
+			count++
+		}
+	}
+	return count
+}
+
+// ListEventsForObject lists the events whose involved object is the
+// provided object
+func ListEventsForObject(ctx context.Context, involved client.Object, options ...RunOption) ([]corev1.Event, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	if involved == nil {
+		return nil, errors.New("nil object")
+	}
+	var events corev1.EventList
+	err = opts.Client.List(
+		ctx,
+		&events,
+		client.InNamespace(involved.GetNamespace()),
+		client.MatchingFields{"involvedObject.name": involved.GetName()},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list events")
+	}
+
+	// filter by uid when available since objects of different kinds
+	// may share the same name
+	var result = make([]corev1.Event, 0, len(events.Items))
+	for _, event := range events.Items {
+		if involved.GetUID() != "" && event.InvolvedObject.UID != involved.GetUID() {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+// AssertEvent returns true if at least minCount events of the provided
+// reason & type e.g. Normal or Warning were recorded against the provided
+// object
+//
+// Note: An empty event type matches all types
+func AssertEvent(ctx context.Context, involved client.Object, reason, eventType string, minCount int, options ...RunOption) (result bool, diff string, err error) {
+	actual, err := Get(ctx, involved, options...)
+	if err != nil {
+		return false, "", err
+	}
+	events, err := ListEventsForObject(ctx, actual, options...)
+	if err != nil {
+		return false, "", err
+	}
+	count := countEvents(events, reason, eventType)
+	if count < minCount {
+		return false, fmt.Sprintf(
+			"want at least %d events with reason %q & type %q got %d",
+			minCount, reason, eventType, count,
+		), nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCountEvents(t *testing.T) {
+	t.Parallel()
+
+	events := []corev1.Event{
+		{Reason: "SuccessfulCreate", Type: corev1.EventTypeNormal, Count: 3},
+		{Reason: "SuccessfulCreate", Type: corev1.EventTypeNormal},
+		{Reason: "FailedScheduling", Type: corev1.EventTypeWarning, Count: 2},
+	}
+
+	var scenarios = []struct {
+		name      string
+		reason    string
+		eventType string
+		expected  int
+	}{
+		{
+			name:      "should count aggregated & non aggregated events",
+			reason:    "SuccessfulCreate",
+			eventType: corev1.EventTypeNormal,
+			expected:  4,
+		},
+		{
+			name:     "should match all types when type is not set",
+			reason:   "FailedScheduling",
+			expected: 2,
+		},
+		{
+			name:      "should not count events of a different type",
+			reason:    "FailedScheduling",
+			eventType: corev1.EventTypeNormal,
+		},
+		{
+			name:   "should not count events of a different reason",
+			reason: "Killing",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := countEvents(events, scenario.reason, scenario.eventType)
+			assert.Equal(t, scenario.expected, got)
+		})
+	}
+}
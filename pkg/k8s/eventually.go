@@ -0,0 +1,368 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+	"github.com/simplekube/kit/pkg/pointer"
+	"github.com/simplekube/kit/pkg/util"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// KindDefaults provides the retry interval & retry timeout to be used
+// when eventually asserting a resource of a particular kind. A namespace
+// deletion, a PVC bind & a Deployment rollout have very different
+// timescales. These defaults are consulted only when the corresponding
+// values are not set explicitly.
+//
+// Note: This can be modified to suit the cluster under test
+var KindDefaults = map[schema.GroupKind]EventuallyOptions{
+	{Group: "", Kind: "Namespace"}: {
+		RetryInterval: 5 * time.Second,
+		RetryTimeout:  360 * time.Second,
+	},
+	{Group: "", Kind: "PersistentVolumeClaim"}: {
+		RetryInterval: 2 * time.Second,
+		RetryTimeout:  120 * time.Second,
+	},
+	{Group: "apps", Kind: "Deployment"}: {
+		RetryInterval: 2 * time.Second,
+		RetryTimeout:  300 * time.Second,
+	},
+	{Group: "apps", Kind: "StatefulSet"}: {
+		RetryInterval: 2 * time.Second,
+		RetryTimeout:  300 * time.Second,
+	},
+	{Group: "apps", Kind: "DaemonSet"}: {
+		RetryInterval: 2 * time.Second,
+		RetryTimeout:  300 * time.Second,
+	},
+}
+
+// EventuallyOptionsForObject returns the given eventually options with
+// unset retry interval &/ retry timeout filled in from KindDefaults based
+// on the kind of the provided object. Options are returned as is if there
+// are no defaults registered for this kind.
+func EventuallyOptionsForObject(obj client.Object, given EventuallyOptions, rscheme *runtime.Scheme) (EventuallyOptions, error) {
+	if given.RetryInterval != 0 && given.RetryTimeout != 0 {
+		return given, nil
+	}
+	gvk, err := apiutil.GVKForObject(obj, rscheme)
+	if err != nil {
+		return given, errors.Wrap(err, "failed to extract gvk")
+	}
+	defaults, found := KindDefaults[gvk.GroupKind()]
+	if !found {
+		return given, nil
+	}
+	if given.RetryInterval == 0 {
+		given.RetryInterval = defaults.RetryInterval
+	}
+	if given.RetryTimeout == 0 {
+		given.RetryTimeout = defaults.RetryTimeout
+	}
+	return given, nil
+}
+
+const (
+	// defaultRetryInterval is used when neither the caller nor the kind
+	// defaults provide a retry interval
+	defaultRetryInterval = time.Second
+
+	// defaultRetryTimeout is used when neither the caller nor the kind
+	// defaults provide a retry timeout
+	defaultRetryTimeout = 60 * time.Second
+)
+
+// hasCondition returns true if the provided object has a condition of
+// the provided type with the provided status in its status.conditions
+func hasCondition(obj map[string]interface{}, conditionType, expectedStatus string) (bool, error) {
+	conditions, _, err := unstructured.NestedSlice(obj, "status", "conditions")
+	if err != nil {
+		return false, errors.Wrap(err, "read status.conditions")
+	}
+	for _, item := range conditions {
+		cond, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond["type"] == conditionType {
+			return cond["status"] == expectedStatus, nil
+		}
+	}
+	return false, nil
+}
+
+// WaitForCondition polls the provided object till it has a status
+// condition of the provided type with the provided status e.g. type
+// Available with status True for a Deployment. Unset retry interval &/
+// retry timeout are derived from KindDefaults.
+func WaitForCondition(ctx context.Context, given client.Object, conditionType, expectedStatus string, eventually EventuallyOptions, options ...RunOption) error {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	if given == nil {
+		return errors.New("nil object")
+	}
+	eventually, err = EventuallyOptionsForObject(given, eventually, opts.Scheme)
+	if err != nil {
+		return err
+	}
+	if eventually.RetryInterval == 0 {
+		eventually.RetryInterval = defaultRetryInterval
+	}
+	if eventually.RetryTimeout == 0 {
+		eventually.RetryTimeout = defaultRetryTimeout
+	}
+	err = util.RetryWithContext(ctx, util.RetryOptions{
+		Immediate: true,
+		Interval:  eventually.RetryInterval,
+		Timeout:   eventually.RetryTimeout,
+	}, func() (bool, error) {
+		// each attempt observes the latest state
+		invalidateCache(opts, given)
+		actual, err := Get(ctx, given, opts)
+		if err != nil {
+			return false, err
+		}
+		content, err := toUnstructuredContent(actual)
+		if err != nil {
+			return true, err
+		}
+		found, err := hasCondition(content, conditionType, expectedStatus)
+		if err != nil {
+			return true, err
+		}
+		if !found {
+			return false, errors.Errorf("condition %s=%s not found", conditionType, expectedStatus)
+		}
+		return true, nil
+	})
+	return errors.Wrapf(err, "wait for condition %s=%s: %s", conditionType, expectedStatus, k8sutil.DescribeObj(given))
+}
+
+// AssertEventuallyRunner gets the provided resource in intervals till it
+// matches the provided assertion or the timeout expires. On timeout the
+// returned error is the *AssertionError of the last attempt including its
+// diff. Unset interval &/ timeout are derived from KindDefaults.
+type AssertEventuallyRunner struct {
+	Resource client.Object
+	Assert   AssertType
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// compile time check to assert if the structure
+// AssertEventuallyRunner implements the interface Runner
+var _ Runner = (*AssertEventuallyRunner)(nil)
+
+// Run polls the resource till the assertion matches
+func (a *AssertEventuallyRunner) Run(ctx context.Context, options ...RunOption) error {
+	if a == nil || a.Resource == nil {
+		return errors.New("nil assert eventually runner")
+	}
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	eventually, err := EventuallyOptionsForObject(a.Resource, EventuallyOptions{
+		RetryInterval: a.Interval,
+		RetryTimeout:  a.Timeout,
+	}, opts.Scheme)
+	if err != nil {
+		return err
+	}
+	if eventually.RetryInterval == 0 {
+		eventually.RetryInterval = defaultRetryInterval
+	}
+	if eventually.RetryTimeout == 0 {
+		eventually.RetryTimeout = defaultRetryTimeout
+	}
+
+	// the diff of the last attempt is what explains the failure
+	opts.VerboseDiff = pointer.Bool(true)
+	var lastErr error
+	err = util.RetryWithContext(ctx, util.RetryOptions{
+		Immediate: true,
+		Interval:  eventually.RetryInterval,
+		Timeout:   eventually.RetryTimeout,
+	}, func() (bool, error) {
+		// each attempt observes the latest state
+		invalidateCache(opts, a.Resource)
+		lastErr = AssertOrError(ctx, a.Resource, AssertOptions{AssertType: a.Assert}, opts)
+		return lastErr == nil, lastErr
+	})
+	if err == nil {
+		return nil
+	}
+	if !IsAssertionError(lastErr) {
+		return errors.Wrapf(err, "assert eventually: %s", k8sutil.DescribeObj(a.Resource))
+	}
+	if ctx.Err() != nil {
+		return errors.Wrapf(lastErr, "assert eventually: %s", ctx.Err())
+	}
+	return errors.Wrapf(lastErr, "assert eventually: timed out after %s", eventually.RetryTimeout)
+}
+
+// String describes the runner
+func (a *AssertEventuallyRunner) String() string {
+	if a == nil || a.Resource == nil {
+		return "assert eventually"
+	}
+	return fmt.Sprintf("assert eventually %s %s/%s", a.Assert, a.Resource.GetNamespace(), a.Resource.GetName())
+}
+
+// AssertNoDriftRunner asserts that the provided resource does not drift
+// from its desired state throughout the provided duration. The drift is
+// checked in intervals via HasDrifted e.g. to assert that a controller
+// stays stable after the desired state is applied. Unset interval is
+// derived from KindDefaults.
+type AssertNoDriftRunner struct {
+	Resource client.Object
+	Duration time.Duration
+	Interval time.Duration
+}
+
+// compile time check to assert if the structure
+// AssertNoDriftRunner implements the interface Runner
+var _ Runner = (*AssertNoDriftRunner)(nil)
+
+// Run checks the resource for drift till the duration elapses & fails
+// with the diff as soon as a drift is observed
+func (a *AssertNoDriftRunner) Run(ctx context.Context, options ...RunOption) error {
+	if a == nil || a.Resource == nil {
+		return errors.New("nil assert no drift runner")
+	}
+	if a.Duration <= 0 {
+		return errors.Errorf("%s: invalid duration %s: want greater than zero", a, a.Duration)
+	}
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	eventually, err := EventuallyOptionsForObject(a.Resource, EventuallyOptions{
+		RetryInterval: a.Interval,
+	}, opts.Scheme)
+	if err != nil {
+		return err
+	}
+	if eventually.RetryInterval == 0 {
+		eventually.RetryInterval = defaultRetryInterval
+	}
+
+	start := time.Now()
+	deadline := start.Add(a.Duration)
+	for {
+		// each attempt observes the latest state
+		invalidateCache(opts, a.Resource)
+		isDrift, drift, err := HasDrifted(ctx, a.Resource, opts)
+		if err != nil {
+			return errors.Wrapf(err, "%s", a)
+		}
+		if isDrift {
+			return errors.Errorf("%s: drifted after %s: %s", a, time.Since(start), drift)
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil
+		}
+		if remaining > eventually.RetryInterval {
+			remaining = eventually.RetryInterval
+		}
+		timer := time.NewTimer(remaining)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(ctx.Err(), "%s", a)
+		case <-timer.C:
+		}
+	}
+}
+
+// String describes the runner
+func (a *AssertNoDriftRunner) String() string {
+	if a == nil || a.Resource == nil {
+		return "assert no drift"
+	}
+	return fmt.Sprintf("assert no drift %s/%s for %s", a.Resource.GetNamespace(), a.Resource.GetName(), a.Duration)
+}
+
+// AssertGenerationObservedRunner gets the provided resource in intervals
+// till its controller has observed its latest generation i.e. till its
+// status.observedGeneration is same as its metadata.generation. Unset
+// interval &/ timeout are derived from KindDefaults.
+type AssertGenerationObservedRunner struct {
+	Resource client.Object
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// compile time check to assert if the structure
+// AssertGenerationObservedRunner implements the interface Runner
+var _ Runner = (*AssertGenerationObservedRunner)(nil)
+
+// Run polls the resource till its latest generation is observed
+func (a *AssertGenerationObservedRunner) Run(ctx context.Context, options ...RunOption) error {
+	if a == nil || a.Resource == nil {
+		return errors.New("nil assert generation observed runner")
+	}
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	eventually, err := EventuallyOptionsForObject(a.Resource, EventuallyOptions{
+		RetryInterval: a.Interval,
+		RetryTimeout:  a.Timeout,
+	}, opts.Scheme)
+	if err != nil {
+		return err
+	}
+	if eventually.RetryInterval == 0 {
+		eventually.RetryInterval = defaultRetryInterval
+	}
+	if eventually.RetryTimeout == 0 {
+		eventually.RetryTimeout = defaultRetryTimeout
+	}
+	err = util.RetryWithContext(ctx, util.RetryOptions{
+		Immediate: true,
+		Interval:  eventually.RetryInterval,
+		Timeout:   eventually.RetryTimeout,
+	}, func() (bool, error) {
+		// each attempt observes the latest state
+		invalidateCache(opts, a.Resource)
+		actual, err := Get(ctx, a.Resource, opts)
+		if err != nil {
+			return false, err
+		}
+		content, err := toUnstructuredContent(actual)
+		if err != nil {
+			return true, err
+		}
+		isObserved, diff, err := isGenerationObserved(content)
+		if err != nil {
+			return true, err
+		}
+		if !isObserved {
+			return false, errors.New(diff)
+		}
+		return true, nil
+	})
+	return errors.Wrapf(err, "%s", a)
+}
+
+// String describes the runner
+func (a *AssertGenerationObservedRunner) String() string {
+	if a == nil || a.Resource == nil {
+		return "assert generation observed"
+	}
+	return fmt.Sprintf("assert generation observed %s/%s", a.Resource.GetNamespace(), a.Resource.GetName())
+}
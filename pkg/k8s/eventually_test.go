@@ -0,0 +1,374 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEventuallyOptionsForObject(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name     string
+		obj      client.Object
+		given    EventuallyOptions
+		expected EventuallyOptions
+	}{
+		{
+			name: "should use kind defaults when options are not set",
+			obj:  &corev1.Namespace{},
+			expected: EventuallyOptions{
+				RetryInterval: 5 * time.Second,
+				RetryTimeout:  360 * time.Second,
+			},
+		},
+		{
+			name: "should retain explicit values",
+			obj:  &appsv1.Deployment{},
+			given: EventuallyOptions{
+				RetryTimeout: 10 * time.Second,
+			},
+			expected: EventuallyOptions{
+				RetryInterval: 2 * time.Second,
+				RetryTimeout:  10 * time.Second,
+			},
+		},
+		{
+			name: "should return given options when kind has no defaults",
+			obj:  &corev1.ConfigMap{},
+			given: EventuallyOptions{
+				RetryInterval: time.Second,
+			},
+			expected: EventuallyOptions{
+				RetryInterval: time.Second,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EventuallyOptionsForObject(scenario.obj, scenario.given, scheme.Scheme)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expected, got)
+		})
+	}
+}
+
+func TestHasCondition(t *testing.T) {
+	t.Parallel()
+
+	obj := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "True"},
+				map[string]interface{}{"type": "Available", "status": "False"},
+			},
+		},
+	}
+
+	var scenarios = []struct {
+		name          string
+		conditionType string
+		status        string
+		expected      bool
+	}{
+		{
+			name:          "should find condition with expected status",
+			conditionType: "Progressing",
+			status:        "True",
+			expected:      true,
+		},
+		{
+			name:          "should not find condition with different status",
+			conditionType: "Available",
+			status:        "True",
+		},
+		{
+			name:          "should not find missing condition",
+			conditionType: "Ready",
+			status:        "True",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := hasCondition(obj, scenario.conditionType, scenario.status)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expected, got)
+		})
+	}
+}
+
+func TestWaitForCondition(t *testing.T) {
+	t.Parallel()
+
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wait-for-condition",
+			Namespace: "default",
+		},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	opts := &RunOptions{
+		Client: fake.NewClientBuilder().WithObjects(deploy).Build(),
+	}
+	eventually := EventuallyOptions{
+		RetryInterval: 10 * time.Millisecond,
+		RetryTimeout:  50 * time.Millisecond,
+	}
+
+	err := WaitForCondition(context.Background(), deploy, "Available", "True", eventually, opts)
+	assert.NoError(t, err)
+
+	err = WaitForCondition(context.Background(), deploy, "Available", "False", eventually, opts)
+	assert.Error(t, err)
+}
+
+// convergingClient returns the configured data of a ConfigMap from the
+// convergeAfter number of Get calls onwards
+type convergingClient struct {
+	client.Client
+	convergeAfter int
+	data          map[string]string
+	calls         int
+}
+
+func (c *convergingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	c.calls++
+	if err := c.Client.Get(ctx, key, obj); err != nil {
+		return err
+	}
+	if cm, ok := obj.(*corev1.ConfigMap); ok && c.calls >= c.convergeAfter {
+		cm.Data = c.data
+	}
+	return nil
+}
+
+func TestAssertEventuallyRunner(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name          string
+		convergeAfter int
+		expectCalls   int
+		isErr         bool
+	}{
+		{
+			name:          "should pass once the object converges",
+			convergeAfter: 3,
+			expectCalls:   3,
+		},
+		{
+			name:          "should return the last diff on timeout",
+			convergeAfter: 1000,
+			isErr:         true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			observed := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "assert-eventually", Namespace: "default"},
+				Data:       map[string]string{"color": "red"},
+			}
+			cli := &convergingClient{
+				Client:        fake.NewClientBuilder().WithObjects(observed).Build(),
+				convergeAfter: scenario.convergeAfter,
+				data:          map[string]string{"color": "blue"},
+			}
+			expected := &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "assert-eventually", Namespace: "default"},
+				Data:       map[string]string{"color": "blue"},
+			}
+			runner := &AssertEventuallyRunner{
+				Resource: expected,
+				Assert:   AssertTypeIsEquals,
+				Interval: time.Millisecond,
+				Timeout:  50 * time.Millisecond,
+			}
+			err := runner.Run(context.Background(), &RunOptions{Client: cli})
+			if !scenario.isErr {
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expectCalls, cli.calls)
+				return
+			}
+			assert.True(t, IsAssertionError(err), "expected assertion error: %v", err)
+			assert.Contains(t, err.Error(), "timed out after 50ms")
+			assert.Contains(t, err.Error(), "blue")
+		})
+	}
+}
+
+func TestAssertNoDriftRunner(t *testing.T) {
+	t.Parallel()
+
+	desired := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default"},
+			Data:       map[string]string{"color": "red"},
+		}
+	}
+
+	var scenarios = []struct {
+		name        string
+		existing    []client.Object
+		mutateAfter time.Duration
+		duration    time.Duration
+		isError     bool
+		errContains []string
+	}{
+		{
+			name:     "should pass when the object does not drift",
+			existing: []client.Object{desired()},
+			duration: 50 * time.Millisecond,
+		},
+		{
+			name:        "should fail with the diff when the object is mutated during the window",
+			existing:    []client.Object{desired()},
+			mutateAfter: 30 * time.Millisecond,
+			duration:    time.Second,
+			isError:     true,
+			errContains: []string{"drifted after", `"blue"`},
+		},
+		{
+			name:        "should fail when the object is not found",
+			duration:    50 * time.Millisecond,
+			isError:     true,
+			errContains: []string{"not found"},
+		},
+		{
+			name:        "should fail for invalid duration",
+			existing:    []client.Object{desired()},
+			isError:     true,
+			errContains: []string{"invalid duration"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			cli := fake.NewClientBuilder().WithObjects(scenario.existing...).Build()
+			if scenario.mutateAfter > 0 {
+				// simulate an external actor that mutates the object
+				go func() {
+					time.Sleep(scenario.mutateAfter)
+					actual := &corev1.ConfigMap{}
+					if err := cli.Get(ctx, client.ObjectKeyFromObject(desired()), actual); err != nil {
+						t.Logf("mutate: get: %v", err)
+						return
+					}
+					actual.Data["color"] = "blue"
+					if err := cli.Update(ctx, actual); err != nil {
+						t.Logf("mutate: update: %v", err)
+					}
+				}()
+			}
+
+			// Note: Dry runs are not forwarded since the fake client does
+			// not support server side apply
+			r := &AssertNoDriftRunner{
+				Resource: desired(),
+				Duration: scenario.duration,
+				Interval: 10 * time.Millisecond,
+			}
+			err := r.Run(ctx, &RunOptions{Client: &recordingClient{Client: cli}})
+			if scenario.isError {
+				assert.Error(t, err)
+				for _, contains := range scenario.errContains {
+					assert.Contains(t, err.Error(), contains)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAssertGenerationObservedRunner(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name         string
+		observeAfter time.Duration
+		isError      bool
+		errContains  string
+	}{
+		{
+			name:         "should pass once the controller observes the generation",
+			observeAfter: 30 * time.Millisecond,
+		},
+		{
+			name:        "should fail with the generations when the controller lags",
+			isError:     true,
+			errContains: "want observed generation 2 got 1",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			deploy := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "reconciled", Namespace: "default", Generation: 2},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1},
+			}
+			cli := fake.NewClientBuilder().WithObjects(deploy.DeepCopy()).Build()
+			if scenario.observeAfter > 0 {
+				// simulate the controller that observes the latest generation
+				go func() {
+					time.Sleep(scenario.observeAfter)
+					actual := &appsv1.Deployment{}
+					if err := cli.Get(ctx, client.ObjectKeyFromObject(deploy), actual); err != nil {
+						t.Logf("observe: get: %v", err)
+						return
+					}
+					actual.Status.ObservedGeneration = actual.Generation
+					if err := cli.Status().Update(ctx, actual); err != nil {
+						t.Logf("observe: update: %v", err)
+					}
+				}()
+			}
+
+			r := &AssertGenerationObservedRunner{
+				Resource: deploy,
+				Interval: 10 * time.Millisecond,
+				Timeout:  200 * time.Millisecond,
+			}
+			err := r.Run(ctx, &RunOptions{Client: cli})
+			if scenario.isError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), scenario.errContains)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+	"k8s.io/client-go/util/exec"
+)
+
+// PodRef refers to a pod & optionally to one of its containers
+type PodRef struct {
+	Name      string
+	Namespace string
+
+	// Container defaults to the pod's only container if not set
+	Container string
+}
+
+// String returns a string format of the pod reference
+func (p PodRef) String() string {
+	if p.Container == "" {
+		return fmt.Sprintf("%s/%s", p.Namespace, p.Name)
+	}
+	return fmt.Sprintf("%s/%s:%s", p.Namespace, p.Name, p.Container)
+}
+
+// IsExecExitError returns true if the provided error is due to
+// the executed command exiting with a non-zero exit code
+func IsExecExitError(err error) bool {
+	var exitErr exec.CodeExitError
+	return errors.As(err, &exitErr)
+}
+
+// execFunc executes the provided command in the referred pod & returns
+// its stdout & stderr e.g. ExecInPod
+type execFunc func(ctx context.Context, pod PodRef, command []string, options ...RunOption) (stdout string, stderr string, err error)
+
+// compile time check to assert if ExecInPod is an execFunc
+var _ execFunc = ExecInPod
+
+// ExecInPod executes the provided command in the referred pod's
+// container & returns its stdout & stderr. The execution is aborted
+// once the provided context is done.
+//
+// Note: IsExecExitError can be used to check if the returned error
+// is due to the command exiting with a non-zero exit code
+func ExecInPod(ctx context.Context, pod PodRef, command []string, options ...RunOption) (stdout string, stderr string, err error) {
+	opts, err := makeRunOptionsWithBase(options...)
+	if err != nil {
+		return "", "", err
+	}
+	if len(command) == 0 {
+		return "", "", errors.New("empty command")
+	}
+	cfg, err := getRestConfig(opts)
+	if err != nil {
+		return "", "", err
+	}
+	cs, err := opts.GetOrBuildClientset()
+	if err != nil {
+		return "", "", err
+	}
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	err = streamWithContext(ctx, cfg, newExecRequest(cs, pod, command).URL(), remotecommand.StreamOptions{
+		Stdout: &stdoutBuf,
+		Stderr: &stderrBuf,
+	})
+	if err != nil {
+		return stdoutBuf.String(), stderrBuf.String(), errors.Wrapf(err, "failed to exec %q: pod %s", command, pod)
+	}
+	return stdoutBuf.String(), stderrBuf.String(), nil
+}
+
+// newExecRequest returns the request to execute the provided command in
+// the referred pod's container with its stdout & stderr attached
+func newExecRequest(cs kubernetes.Interface, pod PodRef, command []string) *rest.Request {
+	return cs.CoreV1().RESTClient().
+		Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: pod.Container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+}
+
+// streamWithContext streams the remote command at the provided url & closes
+// its connection once the provided context is done
+//
+// Note: This is similar to remotecommand's StreamWithContext which is
+// not available in the client-go version used here
+func streamWithContext(ctx context.Context, cfg *rest.Config, url *url.URL, options remotecommand.StreamOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialise round tripper")
+	}
+	executor, err := remotecommand.NewSPDYExecutorForTransports(
+		transport,
+		contextUpgrader{ctx: ctx, upgrader: upgrader},
+		http.MethodPost,
+		url,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialise executor")
+	}
+	err = executor.Stream(options)
+	if err != nil && ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "stream aborted")
+	}
+	return err
+}
+
+// contextUpgrader closes the connections it upgrades once its context
+// is done. This aborts the streams of these connections.
+type contextUpgrader struct {
+	ctx      context.Context
+	upgrader spdy.Upgrader
+}
+
+// compile time check to assert if the structure
+// contextUpgrader implements the interface spdy.Upgrader
+var _ spdy.Upgrader = contextUpgrader{}
+
+// NewConnection upgrades the provided response to a connection that is
+// closed once the context is done
+func (u contextUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.upgrader.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-u.ctx.Done():
+			_ = conn.Close()
+		case <-conn.CloseChan():
+		}
+	}()
+	return conn, nil
+}
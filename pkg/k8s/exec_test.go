@@ -0,0 +1,168 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+func TestIsExecExitError(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{
+			name:   "should be true for an exit error",
+			err:    exec.CodeExitError{Err: errors.New("command terminated with exit code 1"), Code: 1},
+			expect: true,
+		},
+		{
+			name:   "should be true for a wrapped exit error",
+			err:    errors.Wrap(exec.CodeExitError{Err: errors.New("exit code 2"), Code: 2}, "failed to exec"),
+			expect: true,
+		},
+		{
+			name: "should be false for any other error",
+			err:  errors.New("connection refused"),
+		},
+		{
+			name: "should be false for nil error",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, scenario.expect, IsExecExitError(scenario.err))
+		})
+	}
+}
+
+func TestNewExecRequest(t *testing.T) {
+	t.Parallel()
+
+	cs, err := kubernetes.NewForConfig(&rest.Config{Host: "https://cluster:6443"})
+	assert.NoError(t, err)
+
+	var scenarios = []struct {
+		name              string
+		pod               PodRef
+		command           []string
+		expectedPath      string
+		expectedContainer string
+	}{
+		{
+			name:         "should exec in the pod's only container",
+			pod:          PodRef{Name: "web", Namespace: "default"},
+			command:      []string{"nc", "-z", "10.0.0.1", "80"},
+			expectedPath: "/api/v1/namespaces/default/pods/web/exec",
+		},
+		{
+			name:              "should exec in the referred container",
+			pod:               PodRef{Name: "web", Namespace: "team-a", Container: "sidecar"},
+			command:           []string{"getent", "hosts", "db.team-a.svc.cluster.local"},
+			expectedPath:      "/api/v1/namespaces/team-a/pods/web/exec",
+			expectedContainer: "sidecar",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := newExecRequest(cs, scenario.pod, scenario.command).URL()
+			assert.Equal(t, scenario.expectedPath, got.Path)
+			assert.Equal(t, scenario.command, got.Query()["command"])
+			assert.Equal(t, scenario.expectedContainer, got.Query().Get("container"))
+			assert.Equal(t, "true", got.Query().Get("stdout"))
+			assert.Equal(t, "true", got.Query().Get("stderr"))
+			assert.Empty(t, got.Query().Get("stdin"))
+		})
+	}
+}
+
+func TestExecInPodWithEmptyCommand(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := ExecInPod(context.Background(), PodRef{Name: "web", Namespace: "default"}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "empty command")
+}
+
+func TestStreamWithContextWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cs, err := kubernetes.NewForConfig(&rest.Config{Host: "https://cluster:6443"})
+	assert.NoError(t, err)
+
+	req := newExecRequest(cs, PodRef{Name: "web", Namespace: "default"}, []string{"true"})
+	err = streamWithContext(ctx, &rest.Config{Host: "https://cluster:6443"}, req.URL(), remotecommand.StreamOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// closableConnection is a httpstream.Connection that only tracks if it
+// is closed
+type closableConnection struct {
+	httpstream.Connection
+	closed chan bool
+}
+
+func (c *closableConnection) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func (c *closableConnection) CloseChan() <-chan bool {
+	return c.closed
+}
+
+// upgraderFunc adapts a function to spdy.Upgrader
+type upgraderFunc func(resp *http.Response) (httpstream.Connection, error)
+
+func (fn upgraderFunc) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	return fn(resp)
+}
+
+func TestContextUpgrader(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn := &closableConnection{closed: make(chan bool)}
+	u := contextUpgrader{ctx: ctx, upgrader: upgraderFunc(func(*http.Response) (httpstream.Connection, error) {
+		return conn, nil
+	})}
+
+	got, err := u.NewConnection(&http.Response{})
+	assert.NoError(t, err)
+	assert.Same(t, conn, got)
+	select {
+	case <-conn.CloseChan():
+		t.Fatal("connection should not be closed before the context is done")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-conn.CloseChan():
+	case <-time.After(time.Second):
+		t.Fatal("connection should be closed once the context is done")
+	}
+}
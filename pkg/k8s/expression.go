@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/simplekube/kit/pkg/k8sutil"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// comparisonExpressionRegex matches an expression of the form
+// {<jsonpath> <operator> <value>} e.g. {.spec.replicas > 2}
+//
+// Note: The operator must be surrounded by spaces
+var comparisonExpressionRegex = regexp.MustCompile(`^\{\s*([^\s()]+)\s+(==|!=|>=|<=|>|<)\s+(.+?)\s*\}$`)
+
+// evalJSONPath returns the result of evaluating the provided JSONPath
+// template against the provided object
+func evalJSONPath(obj map[string]interface{}, template string) (string, error) {
+	jp := jsonpath.New("expression").AllowMissingKeys(true)
+	if err := jp.Parse(template); err != nil {
+		return "", errors.Wrapf(err, "parse jsonpath %q", template)
+	}
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, obj); err != nil {
+		return "", errors.Wrapf(err, "execute jsonpath %q", template)
+	}
+	return buf.String(), nil
+}
+
+// compare returns the result of comparing the provided values with the
+// provided operator. Values are compared as numbers if both of them are
+// numbers. Otherwise they are compared as strings & only equality
+// operators are supported.
+func compare(got, operator, want string) (bool, error) {
+	want = strings.Trim(want, `"'`)
+	gotNum, gotErr := strconv.ParseFloat(got, 64)
+	wantNum, wantErr := strconv.ParseFloat(want, 64)
+	if gotErr == nil && wantErr == nil {
+		switch operator {
+		case "==":
+			return gotNum == wantNum, nil
+		case "!=":
+			return gotNum != wantNum, nil
+		case ">":
+			return gotNum > wantNum, nil
+		case ">=":
+			return gotNum >= wantNum, nil
+		case "<":
+			return gotNum < wantNum, nil
+		case "<=":
+			return gotNum <= wantNum, nil
+		}
+	}
+	switch operator {
+	case "==":
+		return got == want, nil
+	case "!=":
+		return got != want, nil
+	}
+	return false, errors.Errorf("operator %q needs numbers: got %q want %q", operator, got, want)
+}
+
+// evalExpression evaluates the provided expression against the provided
+// object & returns its boolean result
+//
+// An expression is either a plain JSONPath template e.g.
+// {.status.loadBalancer.ingress} that results in true if it evaluates to a
+// non empty value other than false, or a comparison of a JSONPath with a
+// value e.g. {.spec.replicas > 2}
+func evalExpression(obj map[string]interface{}, expression string) (bool, error) {
+	if matches := comparisonExpressionRegex.FindStringSubmatch(expression); matches != nil {
+		got, err := evalJSONPath(obj, fmt.Sprintf("{%s}", matches[1]))
+		if err != nil {
+			return false, err
+		}
+		return compare(got, matches[2], matches[3])
+	}
+	got, err := evalJSONPath(obj, expression)
+	if err != nil {
+		return false, err
+	}
+	got = strings.TrimSpace(got)
+	return got != "" && got != "false", nil
+}
+
+// AssertExpression returns true if the result of evaluating the provided
+// expression against the observed state of the provided object matches
+// the expected result.
+//
+// Note: Refer to evalExpression for the supported expressions
+func AssertExpression(ctx context.Context, given client.Object, expression string, expectedResult bool, options ...RunOption) (result bool, diff string, err error) {
+	actual, err := Get(ctx, given, options...)
+	if err != nil {
+		return false, "", err
+	}
+	content, err := toUnstructuredContent(actual)
+	if err != nil {
+		return false, "", err
+	}
+	got, err := evalExpression(content, expression)
+	if err != nil {
+		return false, "", err
+	}
+	if got != expectedResult {
+		return false, fmt.Sprintf(
+			"want %t got %t: expression %s: %s", expectedResult, got, expression, k8sutil.DescribeObj(actual),
+		), nil
+	}
+	return true, "", nil
+}
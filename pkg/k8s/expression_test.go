@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalExpression(t *testing.T) {
+	t.Parallel()
+
+	obj := map[string]interface{}{
+		"kind": "Deployment",
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"paused":   false,
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+			},
+		},
+	}
+
+	var scenarios = []struct {
+		name       string
+		expression string
+		expected   bool
+		isError    bool
+	}{
+		{
+			name:       "should be true when number comparison holds",
+			expression: "{.spec.replicas > 2}",
+			expected:   true,
+		},
+		{
+			name:       "should be false when number comparison fails",
+			expression: "{.spec.replicas <= 2}",
+		},
+		{
+			name:       "should compare strings for equality",
+			expression: `{.kind == "Deployment"}`,
+			expected:   true,
+		},
+		{
+			name:       "should be true when filter matches",
+			expression: `{.status.conditions[?(@.type=="Available")].status}`,
+			expected:   true,
+		},
+		{
+			name:       "should be false when path is missing",
+			expression: "{.status.readyReplicas}",
+		},
+		{
+			name:       "should be false when path evaluates to false",
+			expression: "{.spec.paused}",
+		},
+		{
+			name:       "should fail when strings are compared for order",
+			expression: "{.kind > Deploy}",
+			isError:    true,
+		},
+		{
+			name:       "should fail when jsonpath is invalid",
+			expression: "{.spec[}",
+			isError:    true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := evalExpression(obj, scenario.expression)
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expected, got)
+			}
+		})
+	}
+}
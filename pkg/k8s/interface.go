@@ -122,6 +122,42 @@ const (
 
 	// ActionTypeUpdate defines a Kubernetes resource update operation
 	ActionTypeUpdate ActionType = "Update"
+
+	// ActionTypeApply defines a Kubernetes resource server side apply
+	// operation
+	ActionTypeApply ActionType = "Apply"
+
+	// ActionTypePatch defines a Kubernetes resource merge patch operation
+	ActionTypePatch ActionType = "Patch"
+
+	// ActionTypeList defines a Kubernetes resource list operation
+	ActionTypeList ActionType = "List"
+
+	// ActionTypeUpdateStatus defines a Kubernetes resource status
+	// subresource update operation
+	ActionTypeUpdateStatus ActionType = "UpdateStatus"
+
+	// ActionTypeDryRun defines a Kubernetes resource server side dry run
+	// apply operation
+	ActionTypeDryRun ActionType = "DryRun"
+)
+
+// ConflictStrategy defines how a server side apply handles conflicts
+// with the fields owned by other field managers
+type ConflictStrategy string
+
+const (
+	// ConflictStrategyForce takes over the ownership of the conflicting
+	// fields
+	ConflictStrategyForce ConflictStrategy = "Force"
+
+	// ConflictStrategyFail fails the apply on conflicts
+	ConflictStrategyFail ConflictStrategy = "Fail"
+
+	// ConflictStrategyReport fails the apply on conflicts with an
+	// *ApplyConflictError that lists the conflicting fields & their
+	// managers
+	ConflictStrategyReport ConflictStrategy = "Report"
 )
 
 // AssertType defines the assertion performed in the step
@@ -129,8 +165,26 @@ type AssertType string
 
 const (
 	// AssertTypeIsEquals defines Equals assertion
+	//
+	// Note: This passes if the expected state is a subset of the observed
+	// state i.e. fields absent in the expected state are not compared.
+	// Use AssertTypeIsStrictEquals to compare the whole object.
 	AssertTypeIsEquals AssertType = "Equals"
 
+	// AssertTypeIsStrictEquals defines StrictEquals assertion
+	//
+	// This passes if the observed state is exactly same as the expected
+	// state. Read only system fields of metadata & the status are not
+	// compared.
+	AssertTypeIsStrictEquals AssertType = "StrictEquals"
+
+	// AssertTypeIsSupersetOf defines SupersetOf assertion
+	//
+	// This passes if the expected state is a superset of the observed
+	// state i.e. the observed state is a subset of the expected state.
+	// Read only system fields of metadata & the status are not compared.
+	AssertTypeIsSupersetOf AssertType = "SupersetOf"
+
 	// AssertTypeIsNotEquals defines NotEquals assertion
 	AssertTypeIsNotEquals AssertType = "NotEquals"
 
@@ -0,0 +1,300 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// Job executes the provided runners one after the other in the
+// given order
+type Job struct {
+	Runners []Runner
+}
+
+// compile time check to assert if the structure
+// Job implements the interface Runner
+var _ Runner = (*Job)(nil)
+
+// StepRecord is the machine readable outcome of running a single
+// runner of a Job. It is written as a JSON line to the report sink
+// set in RunOptions.
+type StepRecord struct {
+	// Step is the index of the runner in the job
+	Step int `json:"step"`
+
+	// It describes the runner. This is the result of String() if the
+	// runner implements fmt.Stringer or else the runner's type.
+	It string `json:"it"`
+
+	// Result is either passed or failed
+	Result string `json:"result"`
+
+	// Duration is the time taken to run the runner
+	Duration string `json:"duration"`
+
+	// Error is the error returned by the runner if any
+	Error string `json:"error,omitempty"`
+}
+
+// describeRunner returns the result of String() if the runner
+// implements fmt.Stringer or else the runner's type
+func describeRunner(r Runner) string {
+	if stringer, ok := r.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", r)
+}
+
+// runStep runs the provided runner, logs its progress & writes its
+// outcome to the report sink if one is set
+func runStep(ctx context.Context, sink io.Writer, log logr.Logger, idx int, r Runner, opts ...RunOption) error {
+	start := time.Now()
+	it := describeRunner(r)
+	log.V(1).Info("running step", "step", idx, "it", it)
+	err := r.Run(ctx, opts...)
+	if err != nil {
+		log.Error(err, "step failed", "step", idx, "it", it, "duration", time.Since(start).String())
+	} else {
+		log.V(1).Info("step passed", "step", idx, "it", it, "duration", time.Since(start).String())
+	}
+	if sink == nil {
+		return err
+	}
+	var record = StepRecord{
+		Step:     idx,
+		It:       it,
+		Result:   "passed",
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		record.Result = "failed"
+		record.Error = err.Error()
+	}
+	if reportErr := json.NewEncoder(sink).Encode(record); reportErr != nil {
+		return multierror.Append(err, errors.Wrapf(reportErr, "failed to report step %d", idx)).ErrorOrNil()
+	}
+	return err
+}
+
+// stepOptions returns the report sink & the logger set in the provided
+// options. The logger defaults to one that discards the log lines.
+func stepOptions(opts ...RunOption) (io.Writer, logr.Logger, error) {
+	options, err := makeRunOptionsWithBase(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return options.ReportSink, getLogger(options), nil
+}
+
+// Run executes the runners in order & stops at the first error
+func (j *Job) Run(ctx context.Context, opts ...RunOption) error {
+	if j == nil {
+		return errors.New("nil job")
+	}
+	sink, log, err := stepOptions(opts...)
+	if err != nil {
+		return err
+	}
+	for idx, r := range j.Runners {
+		if r == nil {
+			return errors.Errorf("nil runner at index %d", idx)
+		}
+		if err := runStep(ctx, sink, log, idx, r, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunAll executes all the runners in order irrespective of their
+// failures. Errors if any are collected & returned as an aggregate.
+func (j *Job) RunAll(ctx context.Context, opts ...RunOption) error {
+	if j == nil {
+		return errors.New("nil job")
+	}
+	sink, log, err := stepOptions(opts...)
+	if err != nil {
+		return err
+	}
+	var finalError *multierror.Error
+	for idx, r := range j.Runners {
+		if r == nil {
+			finalError = multierror.Append(finalError, errors.Errorf("nil runner at index %d", idx))
+			continue
+		}
+		if err := runStep(ctx, sink, log, idx, r, opts...); err != nil {
+			finalError = multierror.Append(finalError, err)
+		}
+	}
+	return finalError.ErrorOrNil()
+}
+
+// TimedJob executes the provided runners one after the other in the
+// given order within a total timeout. The context passed to the runners
+// is cancelled once the timeout expires which in turn cancels their
+// in-flight API calls.
+type TimedJob struct {
+	Runners []Runner
+
+	// Timeout is the budget shared by all the runners
+	Timeout time.Duration
+}
+
+// compile time check to assert if the structure
+// TimedJob implements the interface Runner
+var _ Runner = (*TimedJob)(nil)
+
+// Run executes the runners in order & stops at the first error or when
+// the timeout expires. The returned error names the step that was in
+// progress when the timeout expired.
+func (j *TimedJob) Run(ctx context.Context, opts ...RunOption) error {
+	if j == nil {
+		return errors.New("nil timed job")
+	}
+	if j.Timeout <= 0 {
+		return errors.Errorf("invalid timeout %s: want greater than zero", j.Timeout)
+	}
+	sink, log, err := stepOptions(opts...)
+	if err != nil {
+		return err
+	}
+	budgetCtx, cancel := context.WithTimeout(ctx, j.Timeout)
+	defer cancel()
+
+	for idx, r := range j.Runners {
+		if r == nil {
+			return errors.Errorf("nil runner at index %d", idx)
+		}
+		err := runStep(budgetCtx, sink, log, idx, r, opts...)
+		if budgetCtx.Err() != nil && ctx.Err() == nil {
+			// a runner that ignores its context may succeed after the
+			// timeout; the budget is still considered as exceeded
+			if err == nil {
+				err = budgetCtx.Err()
+			}
+			return errors.Wrapf(
+				err,
+				"timed out after %s: step %d in progress: %s", j.Timeout, idx, describeRunner(r),
+			)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockedWriter serializes the writes to the underlying writer
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// ParallelJob executes the provided runners concurrently with at most
+// Concurrency runners running at a time. Use Job instead if the runners
+// depend on each other's order.
+type ParallelJob struct {
+	Runners []Runner
+
+	// Concurrency is the maximum number of runners that run at a time.
+	// All the runners run at once if this is not set.
+	Concurrency int
+}
+
+// compile time check to assert if the structure
+// ParallelJob implements the interface Runner
+var _ Runner = (*ParallelJob)(nil)
+
+// run executes the runners concurrently & returns their errors ordered
+// by the index of the runners. The context passed to the runners is
+// cancelled at the first error if failFast is true.
+func (j *ParallelJob) run(ctx context.Context, failFast bool, opts ...RunOption) error {
+	if j == nil {
+		return errors.New("nil parallel job")
+	}
+	sink, log, err := stepOptions(opts...)
+	if err != nil {
+		return err
+	}
+	if sink != nil {
+		sink = &lockedWriter{w: sink}
+	}
+	var limit = j.Concurrency
+	if limit <= 0 || limit > len(j.Runners) {
+		limit = len(j.Runners)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var errs = make([]error, len(j.Runners))
+	var slots = make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for idx, r := range j.Runners {
+		if r == nil {
+			errs[idx] = errors.Errorf("nil runner at index %d", idx)
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-runCtx.Done():
+		}
+		if runCtx.Err() != nil {
+			// runners that are not started are reported only if the
+			// caller's context is done
+			if ctx.Err() != nil {
+				errs[idx] = errors.Wrapf(ctx.Err(), "runner at index %d not run", idx)
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, r Runner) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := runStep(runCtx, sink, log, idx, r, opts...); err != nil {
+				errs[idx] = errors.Wrapf(err, "runner at index %d", idx)
+				if failFast {
+					cancel()
+				}
+			}
+		}(idx, r)
+	}
+	wg.Wait()
+
+	var finalError *multierror.Error
+	for _, err := range errs {
+		if err != nil {
+			finalError = multierror.Append(finalError, err)
+		}
+	}
+	return finalError.ErrorOrNil()
+}
+
+// Run executes the runners concurrently. The first error cancels the
+// context of the runners that are running & prevents the runners that
+// are yet to start. Errors are returned as an aggregate ordered by the
+// index of the runners.
+func (j *ParallelJob) Run(ctx context.Context, opts ...RunOption) error {
+	return j.run(ctx, true, opts...)
+}
+
+// RunAll executes all the runners concurrently irrespective of their
+// failures. Errors are returned as an aggregate ordered by the index of
+// the runners.
+func (j *ParallelJob) RunAll(ctx context.Context, opts ...RunOption) error {
+	return j.run(ctx, false, opts...)
+}
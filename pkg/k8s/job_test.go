@@ -0,0 +1,376 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// countingRunner counts its invocations & returns the configured error
+type countingRunner struct {
+	count int
+	err   error
+}
+
+func (r *countingRunner) Run(ctx context.Context, opts ...RunOption) error {
+	r.count++
+	return r.err
+}
+
+func TestJobRun(t *testing.T) {
+	t.Parallel()
+
+	first := &countingRunner{}
+	second := &countingRunner{err: errors.New("second failed")}
+	third := &countingRunner{}
+
+	job := &Job{Runners: []Runner{first, second, third}}
+	err := job.Run(context.Background())
+	assert.EqualError(t, err, "second failed")
+	assert.Equal(t, 1, first.count)
+	assert.Equal(t, 1, second.count)
+	assert.Equal(t, 0, third.count, "runners after the failed runner should not be run")
+}
+
+func TestJobRunAll(t *testing.T) {
+	t.Parallel()
+
+	first := &countingRunner{err: errors.New("first failed")}
+	second := &countingRunner{}
+	third := &countingRunner{err: errors.New("third failed")}
+
+	job := &Job{Runners: []Runner{first, second, third}}
+	err := job.RunAll(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "first failed")
+	assert.Contains(t, err.Error(), "third failed")
+	assert.Equal(t, 1, first.count)
+	assert.Equal(t, 1, second.count)
+	assert.Equal(t, 1, third.count)
+
+	err = (&Job{Runners: []Runner{second}}).RunAll(context.Background())
+	assert.NoError(t, err)
+}
+
+// namedRunner is a runner that describes itself
+type namedRunner struct {
+	countingRunner
+	name string
+}
+
+func (r *namedRunner) String() string {
+	return r.name
+}
+
+func TestJobRunAllWithReportSink(t *testing.T) {
+	t.Parallel()
+
+	job := &Job{Runners: []Runner{
+		&namedRunner{name: "should create namespace"},
+		&countingRunner{err: errors.New("second failed")},
+	}}
+	var sink bytes.Buffer
+	err := job.RunAll(context.Background(), &RunOptions{ReportSink: &sink})
+	assert.EqualError(t, err, "1 error occurred:\n\t* second failed\n\n")
+
+	var records []StepRecord
+	decoder := json.NewDecoder(&sink)
+	for decoder.More() {
+		var record StepRecord
+		assert.NoError(t, decoder.Decode(&record))
+		record.Duration = ""
+		records = append(records, record)
+	}
+	assert.Equal(t, []StepRecord{
+		{Step: 0, It: "should create namespace", Result: "passed"},
+		{Step: 1, It: "*k8s.countingRunner", Result: "failed", Error: "second failed"},
+	}, records)
+}
+
+// recordingLogger records the log lines that are enabled by its
+// verbosity
+type recordingLogger struct {
+	verbosity int
+	level     int
+	lines     *[]string
+}
+
+func (l recordingLogger) Enabled() bool {
+	return l.level <= l.verbosity
+}
+
+// record formats the message with the step & it values
+func (l recordingLogger) record(prefix, msg string, kv []interface{}) {
+	*l.lines = append(*l.lines, fmt.Sprintf("%s: %s %s=%v %s=%v", prefix, msg, kv[0], kv[1], kv[2], kv[3]))
+}
+
+func (l recordingLogger) Info(msg string, keysAndValues ...interface{}) {
+	if l.Enabled() {
+		l.record("info", msg, keysAndValues)
+	}
+}
+
+func (l recordingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.record("error", msg+": "+err.Error(), keysAndValues)
+}
+
+func (l recordingLogger) V(level int) logr.Logger {
+	l.level += level
+	return l
+}
+
+func (l recordingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return l
+}
+
+func (l recordingLogger) WithName(name string) logr.Logger {
+	return l
+}
+
+func TestJobRunAllWithLogger(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name      string
+		verbosity int
+		expected  []string
+	}{
+		{
+			name:      "should log only the failures by default",
+			verbosity: 0,
+			expected: []string{
+				"error: step failed: second failed step=1 it=*k8s.countingRunner",
+			},
+		},
+		{
+			name:      "should log the progress of each step at V(1)",
+			verbosity: 1,
+			expected: []string{
+				"info: running step step=0 it=should create namespace",
+				"info: step passed step=0 it=should create namespace",
+				"info: running step step=1 it=*k8s.countingRunner",
+				"error: step failed: second failed step=1 it=*k8s.countingRunner",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			job := &Job{Runners: []Runner{
+				&namedRunner{name: "should create namespace"},
+				&countingRunner{err: errors.New("second failed")},
+			}}
+			var lines []string
+			err := job.RunAll(
+				context.Background(),
+				WithLogger(recordingLogger{verbosity: scenario.verbosity, lines: &lines}),
+			)
+			assert.Error(t, err)
+			assert.Equal(t, scenario.expected, lines)
+		})
+	}
+}
+
+func TestJobRunWithoutLogger(t *testing.T) {
+	t.Parallel()
+
+	job := &Job{Runners: []Runner{&countingRunner{err: errors.New("failed")}}}
+	assert.EqualError(t, job.Run(context.Background(), &RunOptions{Logger: nil}), "failed")
+}
+
+// concurrencyRunner records the maximum number of runners running
+// at a time
+type concurrencyRunner struct {
+	running *int32
+	max     *int32
+}
+
+func (r *concurrencyRunner) Run(ctx context.Context, opts ...RunOption) error {
+	current := atomic.AddInt32(r.running, 1)
+	defer atomic.AddInt32(r.running, -1)
+	for {
+		observed := atomic.LoadInt32(r.max)
+		if current <= observed || atomic.CompareAndSwapInt32(r.max, observed, current) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+// blockingRunner blocks till its context is done
+type blockingRunner struct{}
+
+func (r *blockingRunner) Run(ctx context.Context, opts ...RunOption) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestParallelJobRunAllWithConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var running, max int32
+	var runners []Runner
+	for i := 0; i < 6; i++ {
+		runners = append(runners, &concurrencyRunner{running: &running, max: &max})
+	}
+	job := &ParallelJob{Runners: runners, Concurrency: 2}
+	err := job.RunAll(context.Background())
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, max, int32(2))
+	assert.Greater(t, max, int32(0))
+}
+
+func TestParallelJobRunAllReportsErrorsInOrder(t *testing.T) {
+	t.Parallel()
+
+	job := &ParallelJob{Runners: []Runner{
+		&countingRunner{err: errors.New("first failed")},
+		&countingRunner{},
+		&countingRunner{err: errors.New("third failed")},
+	}}
+	err := job.RunAll(context.Background())
+	assert.EqualError(
+		t,
+		err,
+		"2 errors occurred:\n\t* runner at index 0: first failed\n\t* runner at index 2: third failed\n\n",
+	)
+}
+
+func TestParallelJobRunCancelsSiblingsOnFailure(t *testing.T) {
+	t.Parallel()
+
+	job := &ParallelJob{Runners: []Runner{
+		&blockingRunner{},
+		&countingRunner{err: errors.New("second failed")},
+	}}
+	err := job.Run(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "runner at index 0: context canceled")
+	assert.Contains(t, err.Error(), "runner at index 1: second failed")
+}
+
+// sleepingRunner sleeps for the configured duration ignoring its context
+type sleepingRunner struct {
+	duration time.Duration
+}
+
+func (r *sleepingRunner) Run(ctx context.Context, opts ...RunOption) error {
+	time.Sleep(r.duration)
+	return nil
+}
+
+// blockingGetClient blocks the Get calls till their context is done
+type blockingGetClient struct {
+	client.Client
+}
+
+func (c *blockingGetClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestTimedJobRun(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name             string
+		runners          func() []Runner
+		timeout          time.Duration
+		options          []RunOption
+		isError          bool
+		errContains      []string
+		expectedLastRuns int
+	}{
+		{
+			name: "should run all the runners within the timeout",
+			runners: func() []Runner {
+				return []Runner{&countingRunner{}, &countingRunner{}}
+			},
+			timeout:          time.Second,
+			expectedLastRuns: 1,
+		},
+		{
+			name: "should cancel the step in progress & skip the remaining steps",
+			runners: func() []Runner {
+				return []Runner{&countingRunner{}, &blockingRunner{}, &countingRunner{}}
+			},
+			timeout:     50 * time.Millisecond,
+			isError:     true,
+			errContains: []string{"timed out after 50ms: step 1 in progress", "context deadline exceeded"},
+		},
+		{
+			name: "should fail when a slow step ignores its context",
+			runners: func() []Runner {
+				return []Runner{&sleepingRunner{duration: 100 * time.Millisecond}, &countingRunner{}}
+			},
+			timeout:     10 * time.Millisecond,
+			isError:     true,
+			errContains: []string{"step 0 in progress", "context deadline exceeded"},
+		},
+		{
+			name: "should cancel the in-flight api call of the step in progress",
+			runners: func() []Runner {
+				return []Runner{
+					&PrintRunner{
+						Object: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "slow", Namespace: "default"}},
+						Writer: io.Discard,
+					},
+					&countingRunner{},
+				}
+			},
+			timeout: 50 * time.Millisecond,
+			options: []RunOption{&RunOptions{
+				Client: &blockingGetClient{Client: fake.NewClientBuilder().Build()},
+			}},
+			isError:     true,
+			errContains: []string{"step 0 in progress: print default/slow", "context deadline exceeded"},
+		},
+		{
+			name: "should fail for invalid timeout",
+			runners: func() []Runner {
+				return []Runner{&countingRunner{}}
+			},
+			isError:     true,
+			errContains: []string{"invalid timeout"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			runners := scenario.runners()
+			job := &TimedJob{Runners: runners, Timeout: scenario.timeout}
+			err := job.Run(context.Background(), scenario.options...)
+			if scenario.isError {
+				assert.Error(t, err)
+				for _, contains := range scenario.errContains {
+					assert.Contains(t, err.Error(), contains)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			if last, ok := runners[len(runners)-1].(*countingRunner); ok {
+				assert.Equal(t, scenario.expectedLastRuns, last.count)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ListPredicate evaluates the listed objects. It returns true if the
+// objects are as expected or else false with a diff that describes the
+// mismatch.
+type ListPredicate func(list client.ObjectList) (result bool, diff string, err error)
+
+// AssertList lists the objects into a copy of the provided list &
+// returns the result of evaluating the provided predicate against the
+// listed objects as a whole e.g. all the pods are running or at least
+// one endpoint is ready.
+//
+// Note: RunOptions.ListOptions can be used to filter the listed objects
+func AssertList(ctx context.Context, list client.ObjectList, predicate ListPredicate, options ...RunOption) (result bool, diff string, err error) {
+	if list == nil {
+		return false, "", errors.New("nil list")
+	}
+	if predicate == nil {
+		return false, "", errors.New("nil predicate")
+	}
+	listCopy, ok := list.DeepCopyObject().(client.ObjectList)
+	if !ok {
+		return false, "", errors.Errorf("invalid list %T", list)
+	}
+	listed, err := List(ctx, listCopy, options...)
+	if err != nil {
+		return false, "", err
+	}
+	return predicate(listed)
+}
+
+// AssertListRunner lists the provided resource & evaluates the provided
+// predicate against the listed objects when run. The predicate's diff
+// is returned as an error if the predicate is not met.
+type AssertListRunner struct {
+	Resource client.ObjectList
+
+	// ListOptions filter the listed objects e.g. by namespace or labels.
+	// These are used instead of RunOptions.ListOptions if set.
+	ListOptions []client.ListOption
+
+	Predicate ListPredicate
+}
+
+// compile time check to assert if the structure
+// AssertListRunner implements the interface Runner
+var _ Runner = (*AssertListRunner)(nil)
+
+// Run lists the resource & evaluates the predicate
+func (a *AssertListRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if a == nil {
+		return errors.New("nil assert list runner")
+	}
+	options := opts
+	if len(a.ListOptions) != 0 {
+		options = append(append([]RunOption{}, opts...), &RunOptions{ListOptions: a.ListOptions})
+	}
+	result, diff, err := AssertList(ctx, a.Resource, a.Predicate, options...)
+	if err != nil {
+		return err
+	}
+	if !result {
+		return errors.Errorf("%s: %s", a, diff)
+	}
+	return nil
+}
+
+// String describes the runner
+func (a *AssertListRunner) String() string {
+	if a == nil || a.Resource == nil {
+		return "assert list"
+	}
+	return fmt.Sprintf("assert list %T", a.Resource)
+}
+
+// batchKey groups the objects that can be fetched with a single List
+type batchKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+}
+
+// batchListLimit is the maximum number of objects fetched by each of the
+// paginated lists made by GetAllBatched
+const batchListLimit = 250
+
+// listByNames lists the objects of the provided kind & namespace & returns
+// the ones with the provided names keyed by their names. A field selector
+// on the name is used if a single name is provided. Otherwise the objects
+// are listed in pages of the provided limit till all the names are found.
+//
+// Note: Listed objects are filtered by their names since field selectors
+// can not match a set of names
+func listByNames(ctx context.Context, opts *RunOptions, key batchKey, names map[string]bool, limit int64) (map[string]client.Object, error) {
+	listGVK := key.gvk.GroupVersion().WithKind(key.gvk.Kind + "List")
+	newList := func() client.ObjectList {
+		if typed, err := opts.Scheme.New(listGVK); err == nil {
+			if list, ok := typed.(client.ObjectList); ok {
+				return list
+			}
+		}
+		unstructList := &unstructured.UnstructuredList{}
+		unstructList.SetGroupVersionKind(listGVK)
+		return unstructList
+	}
+
+	var listOptions []client.ListOption
+	if key.namespace != "" {
+		listOptions = append(listOptions, client.InNamespace(key.namespace))
+	}
+	if len(names) == 1 {
+		for name := range names {
+			listOptions = append(listOptions, client.MatchingFields{"metadata.name": name})
+		}
+	} else {
+		listOptions = append(listOptions, client.Limit(limit))
+	}
+
+	var found = make(map[string]client.Object, len(names))
+	var continueToken string
+	for {
+		list := newList()
+		pageOptions := listOptions
+		if continueToken != "" {
+			pageOptions = append(append([]client.ListOption{}, listOptions...), client.Continue(continueToken))
+		}
+		if err := opts.Client.List(ctx, list, pageOptions...); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, errors.Wrapf(err, "extract list %s", listGVK)
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || !names[obj.GetName()] {
+				continue
+			}
+			found[obj.GetName()] = obj
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" || len(found) == len(names) {
+			return found, nil
+		}
+	}
+}
+
+// groupResourceFor returns the group & plural resource of the provided
+// kind as per the client's rest mapper or else as guessed from the kind
+func groupResourceFor(opts *RunOptions, gvk schema.GroupVersionKind) schema.GroupResource {
+	mapping, err := opts.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err == nil {
+		return mapping.Resource.GroupResource()
+	}
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+	return plural.GroupResource()
+}
+
+// convertToTypeOf converts the provided object to the type of the
+// provided target e.g. a typed object to an unstructured object
+func convertToTypeOf(obj, target client.Object, gvk schema.GroupVersionKind) (client.Object, error) {
+	var converted client.Object
+	if _, ok := target.(*unstructured.Unstructured); ok {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, errors.Wrap(err, "convert to unstructured")
+		}
+		converted = &unstructured.Unstructured{Object: content}
+	} else {
+		converted = target.DeepCopyObject().(client.Object)
+		if err := toTypedObject(obj, converted); err != nil {
+			return nil, err
+		}
+	}
+	converted.GetObjectKind().SetGroupVersionKind(gvk)
+	return converted, nil
+}
+
+// GetAllBatched is same as GetAll but fetches the objects of the same kind
+// & namespace with a paginated List instead of a Get per object. Objects
+// are returned in the same order as the provided objects.
+//
+// Note: Objects of a kind & namespace are fetched via Get if they can not
+// be listed e.g. due to RBAC
+func GetAllBatched(ctx context.Context, given []client.Object, options ...RunOption) ([]client.Object, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	var results = make([]client.Object, len(given))
+	var errs = make([]error, len(given))
+	var groups = map[batchKey][]int{}
+	var order []batchKey
+	for idx, obj := range given {
+		if obj == nil {
+			errs[idx] = errors.New("nil object")
+			continue
+		}
+		obj, err := setDefaultNamespace(opts, obj)
+		if err != nil {
+			errs[idx] = err
+			continue
+		}
+		gvk, err := apiutil.GVKForObject(obj, opts.Scheme)
+		if err != nil {
+			errs[idx] = errors.Wrap(err, "extract gvk")
+			continue
+		}
+		key := batchKey{gvk: gvk, namespace: obj.GetNamespace()}
+		if _, found := groups[key]; !found {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], idx)
+	}
+
+	for _, key := range order {
+		var names = map[string]bool{}
+		for _, idx := range groups[key] {
+			names[given[idx].GetName()] = true
+		}
+		found, err := listByNames(ctx, opts, key, names, batchListLimit)
+		for _, idx := range groups[key] {
+			if err != nil {
+				// fallback to get the objects one by one
+				results[idx], errs[idx] = Get(ctx, given[idx], opts)
+				continue
+			}
+			actual, ok := found[given[idx].GetName()]
+			if !ok {
+				gr := groupResourceFor(opts, key.gvk)
+				errs[idx] = errors.Wrap(apierrors.NewNotFound(gr, given[idx].GetName()), "failed to get")
+				continue
+			}
+			if reflect.TypeOf(actual) == reflect.TypeOf(given[idx]) {
+				results[idx] = actual.DeepCopyObject().(client.Object)
+				continue
+			}
+			// return the same type as the provided object
+			results[idx], errs[idx] = convertToTypeOf(actual, given[idx], key.gvk)
+		}
+	}
+
+	var kObjs []client.Object
+	var finalError error
+	for idx, obj := range given {
+		if errs[idx] != nil {
+			notifyObjectProcessed(options, obj, OperationResultNone, errs[idx])
+			finalError = multierror.Append(finalError, errs[idx])
+			continue
+		}
+		notifyObjectProcessed(options, obj, OperationResultProcessed, nil)
+		kObjs = append(kObjs, results[idx])
+	}
+	return kObjs, finalError
+}
@@ -0,0 +1,340 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// allPodsInPhase returns a predicate that is met if all the listed pods
+// are in the provided phase
+func allPodsInPhase(phase corev1.PodPhase) ListPredicate {
+	return func(list client.ObjectList) (bool, string, error) {
+		pods, ok := list.(*corev1.PodList)
+		if !ok {
+			return false, "", fmt.Errorf("want pod list got %T", list)
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != phase {
+				return false, fmt.Sprintf("want phase %s got %s: pod %s", phase, pod.Status.Phase, pod.Name), nil
+			}
+		}
+		return true, "", nil
+	}
+}
+
+func TestAssertListRunner(t *testing.T) {
+	t.Parallel()
+
+	pod := func(name, namespace string, phase corev1.PodPhase) client.Object {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	cli := fake.NewClientBuilder().WithObjects(
+		pod("web-1", "web", corev1.PodRunning),
+		pod("web-2", "web", corev1.PodRunning),
+		pod("db-1", "db", corev1.PodRunning),
+		pod("db-2", "db", corev1.PodPending),
+	).Build()
+
+	var scenarios = []struct {
+		name        string
+		listOptions []client.ListOption
+		predicate   ListPredicate
+		isError     bool
+		errContains string
+	}{
+		{
+			name:        "should pass when all the filtered pods are running",
+			listOptions: []client.ListOption{client.InNamespace("web")},
+			predicate:   allPodsInPhase(corev1.PodRunning),
+		},
+		{
+			name:        "should surface the diff when a filtered pod is not running",
+			listOptions: []client.ListOption{client.InNamespace("db")},
+			predicate:   allPodsInPhase(corev1.PodRunning),
+			isError:     true,
+			errContains: "want phase Running got Pending: pod db-2",
+		},
+		{
+			name:        "should fail when a listed pod is not running",
+			predicate:   allPodsInPhase(corev1.PodRunning),
+			isError:     true,
+			errContains: "pod db-2",
+		},
+		{
+			name:        "should fail with nil predicate",
+			isError:     true,
+			errContains: "nil predicate",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &AssertListRunner{
+				Resource:    &corev1.PodList{},
+				ListOptions: scenario.listOptions,
+				Predicate:   scenario.predicate,
+			}
+			err := r.Run(context.Background(), &RunOptions{Client: cli})
+			if scenario.isError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), scenario.errContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// callCountingClient counts the Get & List calls made against it & fails
+// the List calls if listErr is set
+type callCountingClient struct {
+	client.Client
+	gets    int
+	lists   int
+	listErr error
+}
+
+func (c *callCountingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	c.gets++
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *callCountingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	c.lists++
+	if c.listErr != nil {
+		return c.listErr
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+// configMapsForBatch returns the provided number of config maps in each
+// of the provided namespaces
+func configMapsForBatch(count int, namespaces ...string) []client.Object {
+	var objs []client.Object
+	for _, ns := range namespaces {
+		for i := 0; i < count; i++ {
+			objs = append(objs, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("batch-%d", i), Namespace: ns},
+				Data:       map[string]string{"index": fmt.Sprintf("%s-%d", ns, i)},
+			})
+		}
+	}
+	return objs
+}
+
+func TestGetAllBatched(t *testing.T) {
+	t.Parallel()
+
+	missing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "web"}}
+	unstruct := &unstructured.Unstructured{}
+	unstruct.SetAPIVersion("v1")
+	unstruct.SetKind("ConfigMap")
+	unstruct.SetName("batch-1")
+	unstruct.SetNamespace("db")
+
+	var scenarios = []struct {
+		name          string
+		listErr       error
+		expectedLists int
+		expectedGets  int
+	}{
+		{
+			name:          "should list once per kind & namespace",
+			expectedLists: 2,
+		},
+		{
+			name:          "should fallback to get when objects can not be listed",
+			listErr:       apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "", errors.New("rbac")),
+			expectedLists: 2,
+			expectedGets:  8,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := &callCountingClient{
+				Client:  fake.NewClientBuilder().WithObjects(configMapsForBatch(3, "web", "db")...).Build(),
+				listErr: scenario.listErr,
+			}
+			given := append(configMapsForBatch(3, "web", "db"), missing, unstruct)
+
+			want, wantErr := GetAll(context.Background(), given, &RunOptions{Client: cli.Client})
+			got, gotErr := GetAllBatched(context.Background(), given, &RunOptions{Client: cli})
+			assert.Error(t, wantErr)
+			assert.Error(t, gotErr)
+			assert.Contains(t, gotErr.Error(), `configmaps "missing" not found`)
+			assert.Equal(t, scenario.expectedLists, cli.lists)
+			assert.Equal(t, scenario.expectedGets, cli.gets)
+
+			assert.Len(t, got, len(given)-1, "should return all but the missing object")
+			assert.Equal(t, len(want), len(got))
+			for idx := range want {
+				assert.IsType(t, want[idx], got[idx])
+				assert.Equal(t, want[idx].GetNamespace(), got[idx].GetNamespace())
+				assert.Equal(t, want[idx].GetName(), got[idx].GetName())
+				assert.Equal(t, want[idx].GetResourceVersion(), got[idx].GetResourceVersion())
+				var wantCM, gotCM corev1.ConfigMap
+				assert.NoError(t, toTypedObject(want[idx], &wantCM))
+				assert.NoError(t, toTypedObject(got[idx], &gotCM))
+				assert.Equal(t, wantCM.Data, gotCM.Data)
+			}
+		})
+	}
+}
+
+// pagingClient records the list options & serves the lists in pages of
+// the requested limit similar to the api server
+//
+// Note: The fake client neither paginates nor filters by field selectors
+type pagingClient struct {
+	client.Client
+	listOptions []client.ListOptions
+}
+
+func (c *pagingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	var listOptions client.ListOptions
+	listOptions.ApplyOptions(opts)
+	c.listOptions = append(c.listOptions, listOptions)
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	if listOptions.Limit == 0 {
+		return nil
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	var start int
+	if listOptions.Continue != "" {
+		if start, err = strconv.Atoi(listOptions.Continue); err != nil {
+			return err
+		}
+	}
+	end := start + int(listOptions.Limit)
+	if end < len(items) {
+		list.SetContinue(strconv.Itoa(end))
+	} else {
+		end = len(items)
+	}
+	return meta.SetList(list, items[start:end])
+}
+
+func TestGetAllBatchedWithSingleName(t *testing.T) {
+	t.Parallel()
+
+	cli := &pagingClient{Client: fake.NewClientBuilder().WithObjects(configMapsForBatch(3, "web")...).Build()}
+	got, err := GetAllBatched(context.Background(), configMapsForBatch(1, "web"), &RunOptions{Client: cli})
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, "batch-0", got[0].GetName())
+
+	assert.Len(t, cli.listOptions, 1, "should list once")
+	assert.Equal(t, "web", cli.listOptions[0].Namespace)
+	assert.Equal(t, "metadata.name=batch-0", cli.listOptions[0].FieldSelector.String())
+	assert.Zero(t, cli.listOptions[0].Limit, "should not paginate")
+}
+
+func TestListByNames(t *testing.T) {
+	t.Parallel()
+
+	key := batchKey{gvk: corev1.SchemeGroupVersion.WithKind("ConfigMap"), namespace: "web"}
+
+	var scenarios = []struct {
+		name              string
+		names             []string
+		expectedContinues []string
+	}{
+		{
+			name:              "should list the pages till all the names are found",
+			names:             []string{"batch-0", "batch-4"},
+			expectedContinues: []string{"", "2", "4"},
+		},
+		{
+			name:              "should stop listing once all the names are found",
+			names:             []string{"batch-0", "batch-1"},
+			expectedContinues: []string{""},
+		},
+		{
+			name:              "should list all the pages when a name is missing",
+			names:             []string{"batch-0", "missing"},
+			expectedContinues: []string{"", "2", "4"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := &pagingClient{Client: fake.NewClientBuilder().WithObjects(configMapsForBatch(5, "web")...).Build()}
+			opts := &RunOptions{Client: cli, Scheme: scheme.Scheme}
+			var names = map[string]bool{}
+			for _, name := range scenario.names {
+				names[name] = true
+			}
+			found, err := listByNames(context.Background(), opts, key, names, 2)
+			assert.NoError(t, err)
+			for _, name := range scenario.names {
+				if name == "missing" {
+					assert.NotContains(t, found, name)
+					continue
+				}
+				assert.Contains(t, found, name)
+			}
+
+			var continues []string
+			for _, listOptions := range cli.listOptions {
+				assert.Equal(t, int64(2), listOptions.Limit)
+				assert.Nil(t, listOptions.FieldSelector)
+				continues = append(continues, listOptions.Continue)
+			}
+			assert.Equal(t, scenario.expectedContinues, continues)
+		})
+	}
+}
+
+func BenchmarkGetAll(b *testing.B) {
+	objs := configMapsForBatch(50, "web", "db")
+	opts := &RunOptions{Client: fake.NewClientBuilder().WithObjects(configMapsForBatch(50, "web", "db")...).Build()}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetAll(context.Background(), objs, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetAllBatched(b *testing.B) {
+	objs := configMapsForBatch(50, "web", "db")
+	opts := &RunOptions{Client: fake.NewClientBuilder().WithObjects(configMapsForBatch(50, "web", "db")...).Build()}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetAllBatched(context.Background(), objs, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
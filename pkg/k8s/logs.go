@@ -0,0 +1,149 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// findLine returns the first line read from the provided reader that
+// matches the provided regular expression
+func findLine(reader io.Reader, re *regexp.Regexp) (line string, found bool, err error) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if re.MatchString(scanner.Text()) {
+			return scanner.Text(), true, nil
+		}
+	}
+	return "", false, scanner.Err()
+}
+
+// AwaitLogLine streams the logs of the referred pod's container & returns
+// the first line that matches the provided regular expression. It fails if
+// no such line is logged within the provided timeout or before the
+// context is cancelled.
+//
+// Note: A zero timeout implies waiting till the context is done
+func AwaitLogLine(ctx context.Context, pod PodRef, pattern string, timeout time.Duration, options ...RunOption) (string, error) {
+	opts, err := makeRunOptionsWithBase(options...)
+	if err != nil {
+		return "", err
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid pattern %q", pattern)
+	}
+	cs, err := opts.GetOrBuildClientset()
+	if err != nil {
+		return "", err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	stream, err := cs.CoreV1().
+		Pods(pod.Namespace).
+		GetLogs(pod.Name, &corev1.PodLogOptions{
+			Container: pod.Container,
+			Follow:    true,
+		}).
+		Stream(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to stream logs: pod %s", pod)
+	}
+	defer stream.Close()
+
+	line, found, err := findLine(stream, re)
+	if found {
+		return line, nil
+	}
+	if ctx.Err() != nil {
+		return "", errors.Wrapf(ctx.Err(), "log line matching %q not found: pod %s", pattern, pod)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read logs: pod %s", pod)
+	}
+	return "", errors.Errorf("log line matching %q not found before logs ended: pod %s", pattern, pod)
+}
+
+// toPodLogOptions returns the pod log options for the provided
+// container as per the provided run options
+func toPodLogOptions(container string, opts *RunOptions) *corev1.PodLogOptions {
+	logOpts := &corev1.PodLogOptions{
+		Container: container,
+		TailLines: opts.LogTailLines,
+	}
+	if opts.LogPrevious != nil {
+		logOpts.Previous = *opts.LogPrevious
+	}
+	return logOpts
+}
+
+// GetLogs returns the logs of the provided pod's container. The
+// container may be left empty if the pod has a single container.
+//
+// Note: RunOptions.LogTailLines & RunOptions.LogPrevious can be used
+// to limit the logs & to get the logs of a restarted container
+func GetLogs(ctx context.Context, podName, namespace, container string, options ...RunOption) (string, error) {
+	opts, err := makeRunOptionsWithBase(options...)
+	if err != nil {
+		return "", err
+	}
+	cs, err := opts.GetOrBuildClientset()
+	if err != nil {
+		return "", err
+	}
+	pod := PodRef{Name: podName, Namespace: namespace, Container: container}
+	stream, err := cs.CoreV1().
+		Pods(namespace).
+		GetLogs(podName, toPodLogOptions(container, opts)).
+		Stream(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get logs: pod %s", pod)
+	}
+	defer stream.Close()
+
+	logs, err := io.ReadAll(stream)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read logs: pod %s", pod)
+	}
+	return string(logs), nil
+}
+
+// PodLogsRunner stores the logs of the provided pod's container into
+// the provided string when run
+type PodLogsRunner struct {
+	PodName   string
+	Namespace string
+	Container string
+
+	// Into receives the logs
+	Into *string
+}
+
+// compile time check to assert if the structure
+// PodLogsRunner implements the interface Runner
+var _ Runner = (*PodLogsRunner)(nil)
+
+// Run gets the logs
+func (p *PodLogsRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if p == nil {
+		return errors.New("nil pod logs runner")
+	}
+	if p.Into == nil {
+		return errors.New("nil destination for logs")
+	}
+	logs, err := GetLogs(ctx, p.PodName, p.Namespace, p.Container, opts...)
+	if err != nil {
+		return err
+	}
+	*p.Into = logs
+	return nil
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/simplekube/kit/pkg/pointer"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestFindLine(t *testing.T) {
+	t.Parallel()
+
+	logs := "starting\nloading config\nServer started on :8080\nready\n"
+
+	var scenarios = []struct {
+		name     string
+		pattern  string
+		expected string
+		isFound  bool
+	}{
+		{
+			name:     "should return the first matching line",
+			pattern:  "Server started",
+			expected: "Server started on :8080",
+			isFound:  true,
+		},
+		{
+			name:     "should match regular expressions",
+			pattern:  "^read[y]$",
+			expected: "ready",
+			isFound:  true,
+		},
+		{
+			name:    "should not find when no line matches",
+			pattern: "shutdown",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, found, err := findLine(strings.NewReader(logs), regexp.MustCompile(scenario.pattern))
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isFound, found)
+			assert.Equal(t, scenario.expected, got)
+		})
+	}
+}
+
+func TestToPodLogOptions(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name     string
+		opts     *RunOptions
+		expected *corev1.PodLogOptions
+	}{
+		{
+			name:     "should get all logs of the current container by default",
+			opts:     &RunOptions{},
+			expected: &corev1.PodLogOptions{Container: "app"},
+		},
+		{
+			name: "should get tail of previous container logs",
+			opts: &RunOptions{
+				LogTailLines: pointer.Int64(10),
+				LogPrevious:  pointer.Bool(true),
+			},
+			expected: &corev1.PodLogOptions{
+				Container: "app",
+				TailLines: pointer.Int64(10),
+				Previous:  true,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := toPodLogOptions("app", scenario.opts)
+			assert.Equal(t, scenario.expected, got)
+		})
+	}
+}
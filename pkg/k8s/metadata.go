@@ -1,8 +1,13 @@
 package k8s
 
 import (
+	"encoding/json"
+	"strings"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // credit: https://github.com/AmitKumarDas/metac/tree/master/controller/common
@@ -56,3 +61,135 @@ func overrideField(dest, src *unstructured.Unstructured, fieldPath ...string) er
 	}
 	return nil
 }
+
+// ParseFieldPath splits the provided dotted field path into its fields
+//
+// Fields that contain dots e.g. annotation keys can be enclosed within
+// square brackets with optional quotes. For example:
+// - spec.template.spec.schedulerName
+// - metadata.annotations[kubectl.kubernetes.io/last-applied-configuration]
+// - metadata.labels["app.kubernetes.io/name"]
+func ParseFieldPath(path string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	for idx := 0; idx < len(path); idx++ {
+		switch ch := path[idx]; ch {
+		case '.':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		case '[':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+			end := strings.IndexByte(path[idx:], ']')
+			if end < 0 {
+				return nil, errors.Errorf("missing ']' in field path %q", path)
+			}
+			key := strings.Trim(path[idx+1:idx+end], `"'`)
+			if key == "" {
+				return nil, errors.Errorf("empty key in field path %q", path)
+			}
+			fields = append(fields, key)
+			idx += end
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	if len(fields) == 0 {
+		return nil, errors.Errorf("invalid field path %q", path)
+	}
+	return fields, nil
+}
+
+// NormalizeOptions defines the fields that get removed while
+// normalizing an object
+type NormalizeOptions struct {
+	// DropStatus when true removes the status of the object
+	DropStatus bool
+
+	// DropManagedFields when true removes metadata.managedFields
+	DropManagedFields bool
+
+	// DropPaths is the list of dotted field paths that get removed
+	// e.g. metadata.annotations[deployment.kubernetes.io/revision]
+	DropPaths []string
+}
+
+// Normalize returns a deterministic representation of the provided
+// object by removing the read-only, system-populated fields of its
+// metadata. Status, managed fields & other fields are removed based
+// on the provided options.
+func Normalize(obj client.Object, opts NormalizeOptions) (*unstructured.Unstructured, error) {
+	if obj == nil {
+		return nil, errors.New("nil object")
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
+	if err != nil {
+		return nil, errors.Wrap(err, "convert to unstructured")
+	}
+	normalized := &unstructured.Unstructured{Object: content}
+
+	for _, fieldName := range objectMetaSystemFields {
+		if fieldName == "managedFields" && !opts.DropManagedFields {
+			continue
+		}
+		unstructured.RemoveNestedField(normalized.Object, "metadata", fieldName)
+	}
+	if opts.DropStatus {
+		unstructured.RemoveNestedField(normalized.Object, "status")
+	}
+	for _, path := range opts.DropPaths {
+		fields, err := ParseFieldPath(path)
+		if err != nil {
+			return nil, err
+		}
+		unstructured.RemoveNestedField(normalized.Object, fields...)
+	}
+	return normalized, nil
+}
+
+// FieldOwners returns the names of the field managers that own the
+// provided field path e.g. spec.replicas as per the object's
+// metadata.managedFields.
+//
+// Note: A manager owns a path if it owns the field or any of its
+// sub fields
+func FieldOwners(obj client.Object, fieldPath string) ([]string, error) {
+	if obj == nil {
+		return nil, errors.New("nil object")
+	}
+	fields, err := ParseFieldPath(fieldPath)
+	if err != nil {
+		return nil, err
+	}
+	var owners []string
+	for _, entry := range obj.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		var fieldSet map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fieldSet); err != nil {
+			return nil, errors.Wrapf(err, "invalid managed fields of manager %q", entry.Manager)
+		}
+		var node = fieldSet
+		var found = true
+		for _, field := range fields {
+			child, ok := node["f:"+field].(map[string]interface{})
+			if !ok {
+				found = false
+				break
+			}
+			node = child
+		}
+		if found {
+			owners = append(owners, entry.Manager)
+		}
+	}
+	return owners, nil
+}
@@ -0,0 +1,216 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name     string
+		path     string
+		expected []string
+		isError  bool
+	}{
+		{
+			name:     "should split a dotted path",
+			path:     "spec.template.spec.schedulerName",
+			expected: []string{"spec", "template", "spec", "schedulerName"},
+		},
+		{
+			name:     "should treat bracketed key as a single field",
+			path:     "metadata.annotations[kubectl.kubernetes.io/last-applied-configuration]",
+			expected: []string{"metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration"},
+		},
+		{
+			name:     "should trim quotes from bracketed key",
+			path:     `metadata.labels["app.kubernetes.io/name"]`,
+			expected: []string{"metadata", "labels", "app.kubernetes.io/name"},
+		},
+		{
+			name:    "should fail when bracket is not closed",
+			path:    "metadata.labels[app",
+			isError: true,
+		},
+		{
+			name:    "should fail when path is empty",
+			path:    "",
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseFieldPath(scenario.path)
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	given := &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Namespace",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "normalize",
+			UID:               "1234-1234",
+			ResourceVersion:   "101",
+			CreationTimestamp: metav1.Now(),
+			Annotations: map[string]string{
+				"app.kubernetes.io/revision": "1",
+				"keep":                       "me",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "test"},
+			},
+		},
+		Status: corev1.NamespaceStatus{
+			Phase: corev1.NamespaceActive,
+		},
+	}
+
+	var scenarios = []struct {
+		name     string
+		opts     NormalizeOptions
+		expected map[string]interface{}
+	}{
+		{
+			name: "should remove system fields only",
+			expected: map[string]interface{}{
+				"kind":       "Namespace",
+				"apiVersion": "v1",
+				"metadata": map[string]interface{}{
+					"name": "normalize",
+					"annotations": map[string]interface{}{
+						"app.kubernetes.io/revision": "1",
+						"keep":                       "me",
+					},
+					"managedFields": []interface{}{
+						map[string]interface{}{"manager": "test"},
+					},
+				},
+				"spec": map[string]interface{}{},
+				"status": map[string]interface{}{
+					"phase": "Active",
+				},
+			},
+		},
+		{
+			name: "should remove status, managed fields & provided paths",
+			opts: NormalizeOptions{
+				DropStatus:        true,
+				DropManagedFields: true,
+				DropPaths:         []string{"metadata.annotations[app.kubernetes.io/revision]", "spec"},
+			},
+			expected: map[string]interface{}{
+				"kind":       "Namespace",
+				"apiVersion": "v1",
+				"metadata": map[string]interface{}{
+					"name": "normalize",
+					"annotations": map[string]interface{}{
+						"keep": "me",
+					},
+				},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Normalize(given, scenario.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expected, got.Object)
+		})
+	}
+}
+
+func TestFieldOwners(t *testing.T) {
+	t.Parallel()
+
+	given := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "field-owners",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager: "kubectl",
+					FieldsV1: &metav1.FieldsV1{
+						Raw: []byte(`{"f:data":{"f:color":{}},"f:metadata":{"f:labels":{"f:app":{}}}}`),
+					},
+				},
+				{
+					Manager: "controller",
+					FieldsV1: &metav1.FieldsV1{
+						Raw: []byte(`{"f:data":{"f:size":{}},"f:metadata":{"f:labels":{".":{},"f:app":{}}}}`),
+					},
+				},
+			},
+		},
+	}
+
+	var scenarios = []struct {
+		name     string
+		path     string
+		expected []string
+		isError  bool
+	}{
+		{
+			name:     "should return the only owner of a field",
+			path:     "data.color",
+			expected: []string{"kubectl"},
+		},
+		{
+			name:     "should return all the owners of a field",
+			path:     "metadata.labels[app]",
+			expected: []string{"kubectl", "controller"},
+		},
+		{
+			name:     "should return owners of sub fields",
+			path:     "data",
+			expected: []string{"kubectl", "controller"},
+		},
+		{
+			name: "should return no owners when field is not managed",
+			path: "data.weight",
+		},
+		{
+			name:    "should fail when path is invalid",
+			path:    "data[color",
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := FieldOwners(given, scenario.path)
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expected, got)
+			}
+		})
+	}
+}
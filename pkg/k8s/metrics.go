@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// MetricsRecorder records the latency & the outcome of the API calls
+// made by the operations of this package
+//
+// Note: The metrics/prometheus package records these as prometheus
+// metrics
+type MetricsRecorder interface {
+	// RecordOperation is invoked after each API call with the action,
+	// the kind of the object, the time taken & the error if any
+	RecordOperation(action ActionType, kind string, duration time.Duration, err error)
+}
+
+// MetricsRecorderFunc adapts a function to MetricsRecorder
+type MetricsRecorderFunc func(action ActionType, kind string, duration time.Duration, err error)
+
+// compile time check to assert if MetricsRecorderFunc
+// implements the interface MetricsRecorder
+var _ MetricsRecorder = MetricsRecorderFunc(nil)
+
+// RecordOperation invokes the function with the provided arguments
+func (fn MetricsRecorderFunc) RecordOperation(action ActionType, kind string, duration time.Duration, err error) {
+	fn(action, kind, duration, err)
+}
+
+// recordOperation passes the outcome of the API call that started at
+// the provided time to the metrics recorder if one is set
+//
+// Note: The provided object can either be an object or a list
+func recordOperation(opts *RunOptions, action ActionType, obj runtime.Object, start time.Time, err error) {
+	if opts == nil || opts.MetricsRecorder == nil {
+		return
+	}
+	var gvk schema.GroupVersionKind
+	var gvkErr error
+	if o, ok := obj.(client.Object); ok {
+		gvk, gvkErr = gvkForObject(o, opts.Scheme)
+	} else {
+		// lists are recorded against the kind of their items
+		gvk, gvkErr = apiutil.GVKForObject(obj, opts.Scheme)
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	kind := gvk.Kind
+	if gvkErr != nil {
+		kind = fmt.Sprintf("%T", obj)
+	}
+	opts.MetricsRecorder.RecordOperation(action, kind, time.Since(start), err)
+}
@@ -0,0 +1,99 @@
+// Package prometheus records the API calls made by the operations of the
+// k8s package as prometheus metrics
+package prometheus
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/simplekube/kit/pkg/k8s"
+)
+
+const (
+	// metricsNamespace is the namespace of the prometheus metrics
+	metricsNamespace = "simplekube"
+
+	// resultSuccess & resultError are the values of the result label of
+	// the prometheus metrics
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+// MetricsRecorder records the API calls made by the operations as
+// prometheus metrics i.e. the simplekube_operations_total counter labeled
+// by action, kind & result along with the
+// simplekube_operation_duration_seconds histogram labeled by action &
+// kind
+type MetricsRecorder struct {
+	total    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// compile time check to assert if the structure
+// MetricsRecorder implements the interface k8s.MetricsRecorder
+var _ k8s.MetricsRecorder = (*MetricsRecorder)(nil)
+
+// NewMetricsRecorder returns a new instance of MetricsRecorder with its
+// metrics registered in the provided registerer e.g.
+// prometheus.DefaultRegisterer
+//
+// Note: Metrics that are already registered in the registerer are reused.
+// Hence recorders created against the same registerer share their metrics.
+func NewMetricsRecorder(registerer prometheus.Registerer) (*MetricsRecorder, error) {
+	if registerer == nil {
+		return nil, errors.New("nil registerer")
+	}
+	total, err := registerOrReuse(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "operations_total",
+		Help:      "Number of API calls made by the operations by action, kind & result.",
+	}, []string{"action", "kind", "result"}))
+	if err != nil {
+		return nil, err
+	}
+	duration, err := registerOrReuse(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of the API calls made by the operations by action & kind.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"action", "kind"}))
+	if err != nil {
+		return nil, err
+	}
+	return &MetricsRecorder{
+		total:    total,
+		duration: duration,
+	}, nil
+}
+
+// registerOrReuse registers the provided collector in the provided
+// registerer or else returns the collector that is already registered
+func registerOrReuse[C prometheus.Collector](registerer prometheus.Registerer, collector C) (C, error) {
+	err := registerer.Register(collector)
+	if err == nil {
+		return collector, nil
+	}
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		if existing, ok := alreadyRegistered.ExistingCollector.(C); ok {
+			return existing, nil
+		}
+	}
+	return collector, errors.Wrap(err, "failed to register metrics")
+}
+
+// RecordOperation increments the operations counter & observes the
+// duration of the operation
+func (r *MetricsRecorder) RecordOperation(action k8s.ActionType, kind string, duration time.Duration, err error) {
+	if r == nil {
+		return
+	}
+	result := resultSuccess
+	if err != nil {
+		result = resultError
+	}
+	r.total.WithLabelValues(string(action), kind, result).Inc()
+	r.duration.WithLabelValues(string(action), kind).Observe(duration.Seconds())
+}
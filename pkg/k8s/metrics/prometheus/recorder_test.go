@@ -0,0 +1,67 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/simplekube/kit/pkg/k8s"
+)
+
+func TestMetricsRecorder(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	recorder, err := NewMetricsRecorder(registry)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	options := []k8s.RunOption{
+		k8s.WithClient(fake.NewClientBuilder().Build()),
+		k8s.WithMetricsRecorder(recorder),
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "default"}}
+	_, err = k8s.Create(ctx, cm, options...)
+	assert.NoError(t, err)
+	_, err = k8s.Get(ctx, cm, options...)
+	assert.NoError(t, err)
+	_, err = k8s.Get(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "default"}}, options...)
+	assert.Error(t, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(recorder.total.WithLabelValues("Create", "ConfigMap", "success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(recorder.total.WithLabelValues("Get", "ConfigMap", "success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(recorder.total.WithLabelValues("Get", "ConfigMap", "error")))
+	assert.Equal(t, 2, testutil.CollectAndCount(recorder.duration))
+
+	// recorders share the metrics registered in the same registerer
+	another, err := NewMetricsRecorder(registry)
+	assert.NoError(t, err)
+	assert.Same(t, recorder.total, another.total)
+	assert.Same(t, recorder.duration, another.duration)
+
+	_, err = NewMetricsRecorder(nil)
+	assert.Error(t, err)
+}
+
+// failingRegisterer fails to register any collector
+type failingRegisterer struct {
+	prometheus.Registerer
+}
+
+func (r failingRegisterer) Register(prometheus.Collector) error {
+	return errors.New("registry is closed")
+}
+
+func TestNewMetricsRecorderWithRegisterError(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMetricsRecorder(failingRegisterer{})
+	assert.Error(t, err)
+}
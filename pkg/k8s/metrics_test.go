@@ -0,0 +1,245 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// operationRecord is the outcome of an operation as seen by a
+// MetricsRecorder
+type operationRecord struct {
+	action ActionType
+	kind   string
+	failed bool
+}
+
+// recordingMetrics records the operations passed to it
+type recordingMetrics struct {
+	records []operationRecord
+}
+
+func (m *recordingMetrics) RecordOperation(action ActionType, kind string, duration time.Duration, err error) {
+	m.records = append(m.records, operationRecord{action: action, kind: kind, failed: err != nil})
+}
+
+func TestMetricsRecorder(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "metrics",
+			Namespace: "default",
+		},
+	}
+	var scenarios = []struct {
+		name     string
+		run      func(ctx context.Context, options ...RunOption)
+		expected []operationRecord
+	}{
+		{
+			name: "should record a successful create & get",
+			run: func(ctx context.Context, options ...RunOption) {
+				_, _ = Create(ctx, cm, options...)
+				_, _ = Get(ctx, cm, options...)
+			},
+			expected: []operationRecord{
+				{action: ActionTypeCreate, kind: "ConfigMap"},
+				{action: ActionTypeGet, kind: "ConfigMap"},
+			},
+		},
+		{
+			name: "should record a failed get",
+			run: func(ctx context.Context, options ...RunOption) {
+				_, _ = Get(ctx, cm, options...)
+			},
+			expected: []operationRecord{
+				{action: ActionTypeGet, kind: "ConfigMap", failed: true},
+			},
+		},
+		{
+			name: "should record an upsert followed by a delete",
+			run: func(ctx context.Context, options ...RunOption) {
+				_, _ = Upsert(ctx, cm, options...)
+				_ = Delete(ctx, cm, options...)
+			},
+			expected: []operationRecord{
+				{action: ActionTypeCreateOrMerge, kind: "ConfigMap"},
+				{action: ActionTypeDelete, kind: "ConfigMap"},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			metrics := &recordingMetrics{}
+			scenario.run(
+				context.Background(),
+				WithClient(fake.NewClientBuilder().Build()),
+				WithMetricsRecorder(metrics),
+			)
+			assert.Equal(t, scenario.expected, metrics.records)
+		})
+	}
+}
+
+func TestMetricsRecorderFunc(t *testing.T) {
+	t.Parallel()
+
+	var got []ActionType
+	recorder := MetricsRecorderFunc(func(action ActionType, kind string, duration time.Duration, err error) {
+		got = append(got, action)
+	})
+	_, err := Create(
+		context.Background(),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "metrics-func", Namespace: "default"}},
+		&RunOptions{Client: fake.NewClientBuilder().Build(), MetricsRecorder: recorder},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []ActionType{ActionTypeCreate}, got)
+}
+
+func TestMetricsRecordedForEachOperation(t *testing.T) {
+	t.Parallel()
+
+	cm := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default"},
+			Data:       map[string]string{"color": "red"},
+		}
+	}
+
+	// Note: The outcome of each operation is ignored since only its
+	// metrics are verified e.g. server side apply is not supported by
+	// the fake client
+	var scenarios = []struct {
+		name     string
+		run      func(ctx context.Context, options ...RunOption)
+		expected ActionType
+	}{
+		{
+			name:     "get",
+			run:      func(ctx context.Context, options ...RunOption) { _, _ = Get(ctx, cm(), options...) },
+			expected: ActionTypeGet,
+		},
+		{
+			name:     "get status",
+			run:      func(ctx context.Context, options ...RunOption) { _, _ = GetStatus(ctx, cm(), options...) },
+			expected: ActionTypeGet,
+		},
+		{
+			name: "list",
+			run: func(ctx context.Context, options ...RunOption) {
+				_, _ = List(ctx, &corev1.ConfigMapList{}, options...)
+			},
+			expected: ActionTypeList,
+		},
+		{
+			name:     "list for gvk",
+			run:      func(ctx context.Context, options ...RunOption) { _, _ = ListForGVK(ctx, "v1/ConfigMap", options...) },
+			expected: ActionTypeList,
+		},
+		{
+			name: "create",
+			run: func(ctx context.Context, options ...RunOption) {
+				obj := cm()
+				obj.Name = "metrics-create"
+				_, _ = Create(ctx, obj, options...)
+			},
+			expected: ActionTypeCreate,
+		},
+		{
+			name: "update",
+			run: func(ctx context.Context, options ...RunOption) {
+				actual, err := Get(ctx, cm(), options...)
+				if err == nil {
+					_, _ = Update(ctx, actual, options...)
+				}
+			},
+			expected: ActionTypeUpdate,
+		},
+		{
+			name: "update status",
+			run: func(ctx context.Context, options ...RunOption) {
+				actual, err := Get(ctx, cm(), options...)
+				if err == nil {
+					_, _ = UpdateStatus(ctx, actual, options...)
+				}
+			},
+			expected: ActionTypeUpdateStatus,
+		},
+		{
+			name:     "merge patch",
+			run:      func(ctx context.Context, options ...RunOption) { _, _ = MergePatch(ctx, cm(), options...) },
+			expected: ActionTypePatch,
+		},
+		{
+			name:     "upsert",
+			run:      func(ctx context.Context, options ...RunOption) { _, _ = Upsert(ctx, cm(), options...) },
+			expected: ActionTypeCreateOrMerge,
+		},
+		{
+			name:     "apply",
+			run:      func(ctx context.Context, options ...RunOption) { _, _ = Apply(ctx, cm(), options...) },
+			expected: ActionTypeApply,
+		},
+		{
+			name:     "server side upsert",
+			run:      func(ctx context.Context, options ...RunOption) { _, _ = ServerSideUpsert(ctx, cm(), options...) },
+			expected: ActionTypeApply,
+		},
+		{
+			name:     "dry run",
+			run:      func(ctx context.Context, options ...RunOption) { _, _ = DryRun(ctx, cm(), options...) },
+			expected: ActionTypeDryRun,
+		},
+		{
+			name:     "delete",
+			run:      func(ctx context.Context, options ...RunOption) { _ = Delete(ctx, cm(), options...) },
+			expected: ActionTypeDelete,
+		},
+		{
+			name:     "force delete",
+			run:      func(ctx context.Context, options ...RunOption) { _ = ForceDelete(ctx, cm(), options...) },
+			expected: ActionTypeDelete,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(cmGVK, meta.RESTScopeNamespace)
+			cli := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(cm()).Build()
+			srv := statusServer(cli, cmGVK)
+			defer srv.Close()
+
+			metrics := &recordingMetrics{}
+			scenario.run(
+				context.Background(),
+				&RunOptions{Client: cli, RestConfig: &rest.Config{Host: srv.URL}},
+				WithMetricsRecorder(metrics),
+			)
+			var actions []ActionType
+			for _, record := range metrics.records {
+				assert.Equal(t, "ConfigMap", record.kind)
+				actions = append(actions, record.action)
+			}
+			assert.Contains(t, actions, scenario.expected)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldExpectation describes a field that is expected to be set by a
+// mutation e.g. by a mutating admission webhook
+type FieldExpectation struct {
+	// JSONPath refers to the field e.g. {.metadata.labels.team} or
+	// {.spec.containers[?(@.name=="sidecar")].image}
+	JSONPath string
+
+	// Value is the expected value of the field. Any non empty value is
+	// accepted if this is not set.
+	Value string
+}
+
+// checkMutations verifies if each of the provided expectations is met
+// by the observed object & is not already met by the submitted object.
+// The unmet expectations are returned as the diff.
+func checkMutations(submitted, observed map[string]interface{}, expectations []FieldExpectation) (bool, string, error) {
+	var unmet []string
+	for _, expect := range expectations {
+		got, err := evalJSONPath(observed, expect.JSONPath)
+		if err != nil {
+			return false, "", err
+		}
+		original, err := evalJSONPath(submitted, expect.JSONPath)
+		if err != nil {
+			return false, "", err
+		}
+		switch {
+		case got == "":
+			unmet = append(unmet, fmt.Sprintf("%s: not set", expect.JSONPath))
+		case expect.Value != "" && got != expect.Value:
+			unmet = append(unmet, fmt.Sprintf("%s: want %q got %q", expect.JSONPath, expect.Value, got))
+		case got == original:
+			unmet = append(unmet, fmt.Sprintf("%s: not mutated: %q", expect.JSONPath, got))
+		}
+	}
+	if len(unmet) != 0 {
+		return false, strings.Join(unmet, ", "), nil
+	}
+	return true, "", nil
+}
+
+// AssertMutated creates the provided object & returns true if the
+// created object has the expected fields that were absent from or
+// different in the provided object e.g. a sidecar container injected by
+// a mutating admission webhook.
+//
+// Note: The created object is not deleted
+func AssertMutated(ctx context.Context, given client.Object, expectedMutations []FieldExpectation, options ...RunOption) (result bool, diff string, err error) {
+	if len(expectedMutations) == 0 {
+		return false, "", errors.New("no expected mutations")
+	}
+	submitted, err := toUnstructuredContent(given)
+	if err != nil {
+		return false, "", err
+	}
+	created, err := Create(ctx, given, options...)
+	if err != nil {
+		return false, "", err
+	}
+	observed, err := toUnstructuredContent(created)
+	if err != nil {
+		return false, "", err
+	}
+	return checkMutations(submitted, observed, expectedMutations)
+}
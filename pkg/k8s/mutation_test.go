@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMutations(t *testing.T) {
+	t.Parallel()
+
+	submitted := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "web"},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "web", "image": "web:v1"},
+			},
+		},
+	}
+	observed := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "web", "team": "platform"},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "web", "image": "web:v1"},
+				map[string]interface{}{"name": "sidecar", "image": "proxy:v2"},
+			},
+		},
+	}
+
+	var scenarios = []struct {
+		name         string
+		expectations []FieldExpectation
+		isMutated    bool
+		diff         string
+	}{
+		{
+			name: "should verify injected label & sidecar",
+			expectations: []FieldExpectation{
+				{JSONPath: "{.metadata.labels.team}", Value: "platform"},
+				{JSONPath: `{.spec.containers[?(@.name=="sidecar")].image}`},
+			},
+			isMutated: true,
+		},
+		{
+			name: "should report field that is not set",
+			expectations: []FieldExpectation{
+				{JSONPath: "{.metadata.labels.owner}"},
+			},
+			diff: "{.metadata.labels.owner}: not set",
+		},
+		{
+			name: "should report field with unexpected value",
+			expectations: []FieldExpectation{
+				{JSONPath: "{.metadata.labels.team}", Value: "apps"},
+			},
+			diff: `{.metadata.labels.team}: want "apps" got "platform"`,
+		},
+		{
+			name: "should report field that was submitted as is",
+			expectations: []FieldExpectation{
+				{JSONPath: "{.metadata.labels.app}"},
+			},
+			diff: `{.metadata.labels.app}: not mutated: "web"`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, diff, err := checkMutations(submitted, observed, scenario.expectations)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isMutated, got)
+			assert.Equal(t, scenario.diff, diff)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/simplekube/kit/pkg/util"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// namespaceGroupKind is the group & kind of a Namespace
+var namespaceGroupKind = schema.GroupKind{Kind: "Namespace"}
+
+// ensureNamespace creates the provided namespace if it is absent &
+// waits till the namespace is active. The labels & annotations of the
+// provided namespace are added to the namespace if it already exists.
+// The created namespace is registered for garbage collection in
+// RunOptions.GCRegistrar if register is true.
+func ensureNamespace(ctx context.Context, given *corev1.Namespace, register bool, options ...RunOption) (*corev1.Namespace, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	if given == nil || given.GetName() == "" {
+		return nil, errors.New("nil or unnamed namespace")
+	}
+	if register && opts.GCRegistrar == nil {
+		return nil, errors.Errorf("register namespace %q for teardown: nil gc registrar", given.GetName())
+	}
+
+	var observed = &corev1.Namespace{}
+	err = opts.Client.Get(ctx, client.ObjectKey{Name: given.GetName()}, observed)
+	switch {
+	case apierrors.IsNotFound(err):
+		created := given.DeepCopy()
+		if err := opts.Client.Create(ctx, created); err != nil && !apierrors.IsAlreadyExists(err) {
+			return nil, errors.Wrapf(err, "failed to create namespace %q", given.GetName())
+		}
+		invalidateCache(opts, created)
+		if register {
+			if err := registerForGarbageCollection(opts, created); err != nil {
+				return nil, err
+			}
+		}
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to get namespace %q", given.GetName())
+	default:
+		if len(given.GetLabels()) != 0 {
+			if _, err := SetLabels(ctx, observed, given.GetLabels(), opts); err != nil {
+				return nil, err
+			}
+		}
+		if len(given.GetAnnotations()) != 0 {
+			if _, err := SetAnnotations(ctx, observed, given.GetAnnotations(), opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return waitForNamespaceActive(ctx, opts, given.GetName())
+}
+
+// waitForNamespaceActive polls the referred namespace till its phase is
+// Active. The retry interval & timeout are derived from KindDefaults.
+func waitForNamespaceActive(ctx context.Context, opts *RunOptions, name string) (*corev1.Namespace, error) {
+	var ns = &corev1.Namespace{}
+	eventually, err := EventuallyOptionsForObject(ns, EventuallyOptions{}, opts.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	if eventually.RetryInterval == 0 {
+		eventually.RetryInterval = defaultRetryInterval
+	}
+	if eventually.RetryTimeout == 0 {
+		eventually.RetryTimeout = defaultRetryTimeout
+	}
+	err = util.RetryWithContext(ctx, util.RetryOptions{
+		Immediate: true,
+		Interval:  eventually.RetryInterval,
+		Timeout:   eventually.RetryTimeout,
+	}, func() (bool, error) {
+		if err := opts.Client.Get(ctx, client.ObjectKey{Name: name}, ns); err != nil {
+			return false, err
+		}
+		if ns.Status.Phase != corev1.NamespaceActive {
+			return false, errors.Errorf("want phase %s got %q", corev1.NamespaceActive, ns.Status.Phase)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "wait for namespace %q to be active", name)
+	}
+	return ns, nil
+}
+
+// finalizeNamespace removes the spec.finalizers e.g. kubernetes of the
+// referred namespace via its finalize subresource. A namespace that is
+// not found is ignored.
+//
+// Note: The spec.finalizers of a namespace can not be removed via an
+// update of the namespace
+func finalizeNamespace(ctx context.Context, opts *RunOptions, name string) error {
+	cs, err := opts.GetOrBuildClientset()
+	if err != nil {
+		return err
+	}
+	ns, err := cs.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get namespace %q", name)
+	}
+	if len(ns.Spec.Finalizers) == 0 {
+		return nil
+	}
+	ns.Spec.Finalizers = nil
+	_, err = cs.CoreV1().Namespaces().Finalize(ctx, ns, metav1.UpdateOptions{})
+	invalidateCache(opts, ns)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to finalize namespace %q", name)
+	}
+	return nil
+}
+
+// EnsureNamespace creates the namespace with the provided name if it is
+// absent & waits till the namespace is active.
+//
+// Note: The created namespace is registered for garbage collection if
+// RunOptions.GCRegistrar is set
+func EnsureNamespace(ctx context.Context, name string, options ...RunOption) (*corev1.Namespace, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	return ensureNamespace(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}, opts.GCRegistrar != nil, opts)
+}
+
+// EnsureNamespaceRunner creates the provided namespace if it is absent &
+// waits till the namespace is active when run
+type EnsureNamespaceRunner struct {
+	Name string
+
+	// Labels & Annotations are set against the namespace. These are
+	// added to the namespace if it already exists.
+	Labels      map[string]string
+	Annotations map[string]string
+
+	// AutoRegisterForTeardown when true registers the created namespace
+	// for garbage collection in RunOptions.GCRegistrar. The run fails if
+	// the registrar is not set.
+	//
+	// Note: A namespace that already exists is not registered
+	AutoRegisterForTeardown bool
+}
+
+// compile time check to assert if the structure
+// EnsureNamespaceRunner implements the interface Runner
+var _ Runner = (*EnsureNamespaceRunner)(nil)
+
+// Run ensures the namespace is present & active
+func (e *EnsureNamespaceRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if e == nil {
+		return errors.New("nil ensure namespace runner")
+	}
+	_, err := ensureNamespace(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        e.Name,
+			Labels:      e.Labels,
+			Annotations: e.Annotations,
+		},
+	}, e.AutoRegisterForTeardown, opts...)
+	return err
+}
+
+// String describes the runner
+func (e *EnsureNamespaceRunner) String() string {
+	if e == nil {
+		return "ensure namespace"
+	}
+	return fmt.Sprintf("ensure namespace %s", e.Name)
+}
@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// activatingClient marks the created namespaces as active similar to
+// the namespace controller
+//
+// Note: The fake client does not run any controllers
+type activatingClient struct {
+	client.Client
+}
+
+func (c *activatingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if ns, ok := obj.(*corev1.Namespace); ok {
+		ns.Status.Phase = corev1.NamespaceActive
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestEnsureNamespaceRunner(t *testing.T) {
+	t.Parallel()
+
+	existing := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Labels: map[string]string{"team": "storage"}},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+	}
+
+	var scenarios = []struct {
+		name           string
+		runner         *EnsureNamespaceRunner
+		registrar      *BaseRegistrar
+		isError        bool
+		expectedLabels map[string]string
+		expectedKeys   []Key
+	}{
+		{
+			name:           "should create a fresh namespace & wait till it is active",
+			runner:         &EnsureNamespaceRunner{Name: "fresh", Labels: map[string]string{"tier": "backend"}},
+			expectedLabels: map[string]string{"tier": "backend"},
+		},
+		{
+			name: "should register the fresh namespace for teardown",
+			runner: &EnsureNamespaceRunner{
+				Name:                    "fresh",
+				AutoRegisterForTeardown: true,
+			},
+			registrar: NewGarbageCollector(),
+			expectedKeys: []Key{
+				Key(k8sutil.ObjKey(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "fresh"}})),
+			},
+		},
+		{
+			name: "should fail to register for teardown without a registrar",
+			runner: &EnsureNamespaceRunner{
+				Name:                    "fresh",
+				AutoRegisterForTeardown: true,
+			},
+			isError: true,
+		},
+		{
+			name:           "should add the labels to the existing namespace",
+			runner:         &EnsureNamespaceRunner{Name: "existing", Labels: map[string]string{"tier": "backend"}},
+			expectedLabels: map[string]string{"team": "storage", "tier": "backend"},
+		},
+		{
+			name: "should not register the existing namespace for teardown",
+			runner: &EnsureNamespaceRunner{
+				Name:                    "existing",
+				AutoRegisterForTeardown: true,
+			},
+			registrar:      NewGarbageCollector(),
+			expectedLabels: map[string]string{"team": "storage"},
+			expectedKeys:   []Key{},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := &activatingClient{Client: fake.NewClientBuilder().WithObjects(existing.DeepCopy()).Build()}
+			opts := &RunOptions{Client: cli}
+			if scenario.registrar != nil {
+				opts.GCRegistrar = scenario.registrar
+			}
+			err := scenario.runner.Run(context.Background(), opts)
+			if scenario.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var got corev1.Namespace
+			assert.NoError(t, cli.Get(context.Background(), client.ObjectKey{Name: scenario.runner.Name}, &got))
+			assert.Equal(t, corev1.NamespaceActive, got.Status.Phase)
+			assert.Equal(t, scenario.expectedLabels, got.GetLabels())
+			if scenario.registrar != nil {
+				assert.Equal(t, scenario.expectedKeys, scenario.registrar.GetKeys())
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file exposes assertions to verify network connectivity from
+// within the cluster. These assertions execute probes in existing pods
+// & hence expect the probe binaries e.g. nc to be present in the pod's
+// container image.
+
+// AssertNetworkReachable returns true if the reachability of the
+// destination pod's port from the source pod matches the provided
+// expectation e.g. to verify if a NetworkPolicy allows or blocks the
+// traffic.
+//
+// Note: Reachability is probed via `nc -z` executed in the source pod
+func AssertNetworkReachable(ctx context.Context, from, to PodRef, port int, expectReachable bool, options ...RunOption) (result bool, diff string, err error) {
+	return assertNetworkReachable(ctx, ExecInPod, from, to, port, expectReachable, options...)
+}
+
+// reachabilityCommand returns the command that exits with a non-zero
+// exit code if the provided ip's port is not reachable
+func reachabilityCommand(ip string, port int) []string {
+	return []string{"nc", "-z", "-w", "2", ip, strconv.Itoa(port)}
+}
+
+// assertNetworkReachable is same as AssertNetworkReachable but probes
+// via the provided exec
+func assertNetworkReachable(ctx context.Context, execInPod execFunc, from, to PodRef, port int, expectReachable bool, options ...RunOption) (result bool, diff string, err error) {
+	dest, err := Get(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      to.Name,
+			Namespace: to.Namespace,
+		},
+	}, options...)
+	if err != nil {
+		return false, "", err
+	}
+	destIP := dest.(*corev1.Pod).Status.PodIP
+	if destIP == "" {
+		return false, "", errors.Errorf("ip is not assigned to pod %s", to)
+	}
+
+	_, _, err = execInPod(ctx, from, reachabilityCommand(destIP, port), options...)
+	if err != nil && !IsExecExitError(err) {
+		return false, "", err
+	}
+	isReachable := err == nil
+	if isReachable != expectReachable {
+		return false, fmt.Sprintf(
+			"want reachable=%t got reachable=%t: from %s to %s:%d",
+			expectReachable, isReachable, from, to, port,
+		), nil
+	}
+	return true, "", nil
+}
+
+// AssertNetworkReachabilityRunner verifies the reachability of the
+// destination pod's port from the source pod when run
+type AssertNetworkReachabilityRunner struct {
+	From PodRef
+	To   PodRef
+	Port int
+
+	// ExpectReachable when false expects the port to be unreachable
+	// e.g. due to a NetworkPolicy
+	ExpectReachable bool
+}
+
+// compile time check to assert if the structure
+// AssertNetworkReachabilityRunner implements the interface Runner
+var _ Runner = (*AssertNetworkReachabilityRunner)(nil)
+
+// Run probes the port & compares its reachability with the expectation
+func (a *AssertNetworkReachabilityRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if a == nil {
+		return errors.New("nil assert network reachability runner")
+	}
+	result, diff, err := AssertNetworkReachable(ctx, a.From, a.To, a.Port, a.ExpectReachable, opts...)
+	if err != nil {
+		return errors.Wrapf(err, "%s", a)
+	}
+	if !result {
+		return errors.Errorf("%s: %s", a, diff)
+	}
+	return nil
+}
+
+// String describes the runner
+func (a *AssertNetworkReachabilityRunner) String() string {
+	if a == nil {
+		return "assert network reachability"
+	}
+	return fmt.Sprintf("assert network reachability from %s to %s:%d", a.From, a.To, a.Port)
+}
+
+// serviceFQDN returns the fully qualified domain name of the provided
+// service
+func serviceFQDN(name, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace)
+}
+
+// dnsCommands returns the commands that resolve the provided domain name
+// in the order they are tried
+func dnsCommands(fqdn string) [][]string {
+	return [][]string{
+		{"getent", "hosts", fqdn},
+		{"nslookup", fqdn},
+	}
+}
+
+// AssertDNSResolves returns true if the fully qualified domain name of
+// the provided service i.e. <name>.<namespace>.svc.cluster.local gets
+// resolved from within the provided pod.
+//
+// Note: Resolution is probed via `getent hosts` & falls back to
+// `nslookup` if the former fails or is not available in the pod
+func AssertDNSResolves(ctx context.Context, from PodRef, serviceName, serviceNamespace string, options ...RunOption) (result bool, diff string, err error) {
+	return assertDNSResolves(ctx, ExecInPod, from, serviceName, serviceNamespace, options...)
+}
+
+// assertDNSResolves is same as AssertDNSResolves but probes via the
+// provided exec
+func assertDNSResolves(ctx context.Context, execInPod execFunc, from PodRef, serviceName, serviceNamespace string, options ...RunOption) (result bool, diff string, err error) {
+	fqdn := serviceFQDN(serviceName, serviceNamespace)
+	var stderr string
+	for _, command := range dnsCommands(fqdn) {
+		_, stderr, err = execInPod(ctx, from, command, options...)
+		if err == nil {
+			return true, "", nil
+		}
+		if !IsExecExitError(err) {
+			return false, "", err
+		}
+	}
+	return false, fmt.Sprintf("failed to resolve %s from %s: %s", fqdn, from, stderr), nil
+}
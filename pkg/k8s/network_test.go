@@ -0,0 +1,256 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// exitError is the error of a command that exits with a non-zero exit
+// code as returned by ExecInPod
+var exitError = errors.Wrap(
+	exec.CodeExitError{Err: errors.New("command terminated with exit code 1"), Code: 1},
+	"failed to exec",
+)
+
+// stubExec returns an execFunc that records the executed commands &
+// returns the error set against the command's binary
+func stubExec(errs map[string]error, stderr string, executed *[][]string) execFunc {
+	return func(ctx context.Context, pod PodRef, command []string, options ...RunOption) (string, string, error) {
+		*executed = append(*executed, command)
+		if err := errs[command[0]]; err != nil {
+			return "", stderr, err
+		}
+		return "", "", nil
+	}
+}
+
+func TestAssertNetworkReachable(t *testing.T) {
+	t.Parallel()
+
+	pod := func(name, ip string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     corev1.PodStatus{PodIP: ip},
+		}
+	}
+	cli := fake.NewClientBuilder().WithObjects(pod("db", "10.0.0.7"), pod("pending", "")).Build()
+	from := PodRef{Name: "web", Namespace: "default"}
+
+	var scenarios = []struct {
+		name            string
+		to              string
+		execErr         error
+		expectReachable bool
+		expectResult    bool
+		expectCommand   []string
+		diffContains    string
+		errContains     string
+	}{
+		{
+			name:            "should pass when the port is reachable as expected",
+			to:              "db",
+			expectReachable: true,
+			expectResult:    true,
+			expectCommand:   []string{"nc", "-z", "-w", "2", "10.0.0.7", "5432"},
+		},
+		{
+			name:          "should pass when the port is unreachable as expected",
+			to:            "db",
+			execErr:       exitError,
+			expectResult:  true,
+			expectCommand: []string{"nc", "-z", "-w", "2", "10.0.0.7", "5432"},
+		},
+		{
+			name:            "should fail when the port is unreachable but expected to be reachable",
+			to:              "db",
+			execErr:         exitError,
+			expectReachable: true,
+			expectCommand:   []string{"nc", "-z", "-w", "2", "10.0.0.7", "5432"},
+			diffContains:    "want reachable=true got reachable=false",
+		},
+		{
+			name:          "should fail when the port is reachable but expected to be unreachable",
+			to:            "db",
+			expectCommand: []string{"nc", "-z", "-w", "2", "10.0.0.7", "5432"},
+			diffContains:  "want reachable=false got reachable=true",
+		},
+		{
+			name:          "should error when the probe fails to execute",
+			to:            "db",
+			execErr:       errors.New("connection refused"),
+			expectCommand: []string{"nc", "-z", "-w", "2", "10.0.0.7", "5432"},
+			errContains:   "connection refused",
+		},
+		{
+			name:        "should error when the destination pod has no ip",
+			to:          "pending",
+			errContains: "ip is not assigned to pod default/pending",
+		},
+		{
+			name:        "should error when the destination pod is not found",
+			to:          "missing",
+			errContains: "failed to get",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			var executed [][]string
+			execInPod := stubExec(map[string]error{"nc": scenario.execErr}, "", &executed)
+			to := PodRef{Name: scenario.to, Namespace: "default"}
+			result, diff, err := assertNetworkReachable(
+				context.Background(), execInPod, from, to, 5432, scenario.expectReachable, &RunOptions{Client: cli},
+			)
+			if scenario.errContains != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), scenario.errContains)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, scenario.expectResult, result)
+			if scenario.diffContains != "" {
+				assert.Contains(t, diff, scenario.diffContains)
+			} else {
+				assert.Empty(t, diff)
+			}
+			if scenario.expectCommand != nil {
+				assert.Equal(t, [][]string{scenario.expectCommand}, executed)
+			} else {
+				assert.Empty(t, executed, "should not probe")
+			}
+		})
+	}
+}
+
+func TestAssertNetworkReachabilityRunner(t *testing.T) {
+	t.Parallel()
+
+	var nilRunner *AssertNetworkReachabilityRunner
+	assert.Error(t, nilRunner.Run(context.Background()))
+	assert.Equal(t, "assert network reachability", nilRunner.String())
+
+	r := &AssertNetworkReachabilityRunner{
+		From: PodRef{Name: "web", Namespace: "default"},
+		To:   PodRef{Name: "missing", Namespace: "default"},
+		Port: 5432,
+	}
+	assert.Equal(t, "assert network reachability from default/web to default/missing:5432", r.String())
+	err := r.Run(context.Background(), &RunOptions{Client: fake.NewClientBuilder().Build()})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), r.String())
+}
+
+func TestServiceFQDN(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name      string
+		service   string
+		namespace string
+		expected  string
+	}{
+		{
+			name:      "should build the fqdn of the service",
+			service:   "db",
+			namespace: "team-a",
+			expected:  "db.team-a.svc.cluster.local",
+		},
+		{
+			name:      "should build the fqdn of the api server service",
+			service:   "kubernetes",
+			namespace: "default",
+			expected:  "kubernetes.default.svc.cluster.local",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, scenario.expected, serviceFQDN(scenario.service, scenario.namespace))
+		})
+	}
+}
+
+func TestAssertDNSResolves(t *testing.T) {
+	t.Parallel()
+
+	const fqdn = "db.team-a.svc.cluster.local"
+	getent := []string{"getent", "hosts", fqdn}
+	nslookup := []string{"nslookup", fqdn}
+	from := PodRef{Name: "web", Namespace: "default"}
+
+	var scenarios = []struct {
+		name           string
+		errs           map[string]error
+		expectResult   bool
+		expectCommands [][]string
+		diffContains   string
+		errContains    string
+	}{
+		{
+			name:           "should resolve via getent",
+			expectResult:   true,
+			expectCommands: [][]string{getent},
+		},
+		{
+			name:           "should fall back to nslookup when getent fails",
+			errs:           map[string]error{"getent": exitError},
+			expectResult:   true,
+			expectCommands: [][]string{getent, nslookup},
+		},
+		{
+			name:           "should fail when neither resolves",
+			errs:           map[string]error{"getent": exitError, "nslookup": exitError},
+			expectCommands: [][]string{getent, nslookup},
+			diffContains:   "failed to resolve db.team-a.svc.cluster.local from default/web: NXDOMAIN",
+		},
+		{
+			name:           "should error without fallback when getent fails to execute",
+			errs:           map[string]error{"getent": errors.New("connection refused")},
+			expectCommands: [][]string{getent},
+			errContains:    "connection refused",
+		},
+		{
+			name:           "should error when nslookup fails to execute",
+			errs:           map[string]error{"getent": exitError, "nslookup": errors.New("pod not found")},
+			expectCommands: [][]string{getent, nslookup},
+			errContains:    "pod not found",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			var executed [][]string
+			execInPod := stubExec(scenario.errs, "NXDOMAIN", &executed)
+			result, diff, err := assertDNSResolves(context.Background(), execInPod, from, "db", "team-a")
+			if scenario.errContains != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), scenario.errContains)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, scenario.expectResult, result)
+			if scenario.diffContains != "" {
+				assert.Equal(t, scenario.diffContains, diff)
+			} else {
+				assert.Empty(t, diff)
+			}
+			assert.Equal(t, scenario.expectCommands, executed)
+		})
+	}
+}
@@ -109,8 +109,8 @@ func DeleteNullInUnstructuredMap(m map[string]interface{}) (map[string]interface
 //
 // Note: This supports Kubernetes compatible unstructured types only
 func DeleteNullInUnstructuredSlice(m []interface{}) ([]interface{}, error) {
-	filteredSlice := make([]interface{}, len(m))
-	for idx, val := range m {
+	filteredSlice := make([]interface{}, 0, len(m))
+	for _, val := range m {
 		if val == nil {
 			continue
 		}
@@ -123,15 +123,15 @@ func DeleteNullInUnstructuredSlice(m []interface{}) ([]interface{}, error) {
 			if err != nil {
 				return nil, err
 			}
-			filteredSlice[idx] = filteredSubSlice
+			filteredSlice = append(filteredSlice, filteredSubSlice)
 		case string, float64, bool, int64, nil:
-			filteredSlice[idx] = val
+			filteredSlice = append(filteredSlice, val)
 		case map[string]interface{}:
 			filteredMap, err := DeleteNullInUnstructuredMap(typedVal)
 			if err != nil {
 				return nil, err
 			}
-			filteredSlice[idx] = filteredMap
+			filteredSlice = append(filteredSlice, filteredMap)
 		}
 	}
 	return filteredSlice, nil
@@ -132,14 +132,14 @@ func TestDeleteNullInUnstructuredMap(t *testing.T) {
 			},
 		},
 		{
-			name: "field with []interface{nil} is preserved",
+			name: "field with []interface{nil} is preserved as an empty slice",
 			given: map[string]interface{}{
 				"hi":         "there",
 				"i-am-empty": []interface{}{nil},
 			},
 			expect: map[string]interface{}{
 				"hi":         "there",
-				"i-am-empty": []interface{}{nil},
+				"i-am-empty": []interface{}{},
 			},
 		},
 		{
@@ -256,3 +256,56 @@ func TestDeleteNullInUnstructuredMap(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteNullInUnstructuredSlice(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		name   string
+		given  []interface{}
+		expect []interface{}
+		isErr  bool
+	}{
+		{
+			name:   "nil items are removed without leaving gaps",
+			given:  []interface{}{nil, "a", nil},
+			expect: []interface{}{"a"},
+		},
+		{
+			name:   "slice with only nil items becomes empty",
+			given:  []interface{}{nil, nil},
+			expect: []interface{}{},
+		},
+		{
+			name: "nil items are removed from nested slices & maps",
+			given: []interface{}{
+				[]interface{}{nil, int64(1)},
+				nil,
+				map[string]interface{}{"k": "v", "empty": nil},
+			},
+			expect: []interface{}{
+				[]interface{}{int64(1)},
+				map[string]interface{}{"k": "v"},
+			},
+		},
+		{
+			name:  "item with int value is unsupported",
+			given: []interface{}{nil, 10},
+			isErr: true,
+		},
+	}
+	for _, test := range tests {
+		test := test // pin it
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DeleteNullInUnstructuredSlice(test.given)
+			if !test.isErr {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expect, got)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/simplekube/kit/pkg/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// mutateObjectMeta fetches the provided object from the cluster, mutates
+// the fetched object & upserts it. The upsert is skipped if the mutation
+// does not result in any changes.
+//
+// Note: Finalizers are set to null during the upsert if the mutation
+// removes all of them since an empty list has no effect on the merge
+func mutateObjectMeta(ctx context.Context, obj client.Object, mutate func(actual client.Object) bool, options ...RunOption) (client.Object, error) {
+	if obj == nil {
+		return nil, errors.New("nil object")
+	}
+	actual, err := Get(ctx, obj, options...)
+	if err != nil {
+		return nil, err
+	}
+	hadFinalizers := len(actual.GetFinalizers()) != 0
+	if !mutate(actual) {
+		return actual, nil
+	}
+	upsertOptions := append([]RunOption{}, options...)
+	if hadFinalizers && len(actual.GetFinalizers()) == 0 {
+		upsertOptions = append(upsertOptions, &RunOptions{SetFinalizersToNullDuringUpsert: pointer.Bool(true)})
+	}
+	return Upsert(ctx, actual, upsertOptions...)
+}
+
+// SetLabels adds the provided labels to the provided object in the
+// cluster. Existing labels with the same keys are overwritten while the
+// other labels are retained.
+func SetLabels(ctx context.Context, obj client.Object, labels map[string]string, options ...RunOption) (client.Object, error) {
+	return mutateObjectMeta(ctx, obj, func(actual client.Object) bool {
+		merged, changed := mergeStringMap(actual.GetLabels(), labels)
+		actual.SetLabels(merged)
+		return changed
+	}, options...)
+}
+
+// SetAnnotations adds the provided annotations to the provided object in
+// the cluster. Existing annotations with the same keys are overwritten
+// while the other annotations are retained.
+func SetAnnotations(ctx context.Context, obj client.Object, annotations map[string]string, options ...RunOption) (client.Object, error) {
+	return mutateObjectMeta(ctx, obj, func(actual client.Object) bool {
+		merged, changed := mergeStringMap(actual.GetAnnotations(), annotations)
+		actual.SetAnnotations(merged)
+		return changed
+	}, options...)
+}
+
+// RemoveFinalizer removes the provided finalizer from the provided object
+// in the cluster. The other finalizers of the object are retained.
+//
+// Note: Use ForceDelete to remove all the finalizers & delete the object
+func RemoveFinalizer(ctx context.Context, obj client.Object, finalizer string, options ...RunOption) (client.Object, error) {
+	if finalizer == "" {
+		return nil, errors.New("empty finalizer")
+	}
+	return mutateObjectMeta(ctx, obj, func(actual client.Object) bool {
+		var retained []string
+		for _, f := range actual.GetFinalizers() {
+			if f != finalizer {
+				retained = append(retained, f)
+			}
+		}
+		if len(retained) == len(actual.GetFinalizers()) {
+			return false
+		}
+		actual.SetFinalizers(retained)
+		return true
+	}, options...)
+}
+
+// mergeStringMap returns the result of merging the provided updates into
+// a copy of the provided map. It also returns true if the result differs
+// from the provided map.
+func mergeStringMap(given, updates map[string]string) (map[string]string, bool) {
+	var merged = make(map[string]string, len(given)+len(updates))
+	for k, v := range given {
+		merged[k] = v
+	}
+	var changed bool
+	for k, v := range updates {
+		if old, found := merged[k]; !found || old != v {
+			changed = true
+		}
+		merged[k] = v
+	}
+	return merged, changed
+}
+
+// SetLabelsRunner adds the provided labels to the provided object when
+// run
+type SetLabelsRunner struct {
+	Object client.Object
+	Labels map[string]string
+}
+
+// compile time check to assert if the structure
+// SetLabelsRunner implements the interface Runner
+var _ Runner = (*SetLabelsRunner)(nil)
+
+// Run adds the labels to the object
+func (s *SetLabelsRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if s == nil {
+		return errors.New("nil set labels runner")
+	}
+	_, err := SetLabels(ctx, s.Object, s.Labels, opts...)
+	return err
+}
+
+// String describes the runner
+func (s *SetLabelsRunner) String() string {
+	if s == nil || s.Object == nil {
+		return "set labels"
+	}
+	return fmt.Sprintf("set labels of %s/%s", s.Object.GetNamespace(), s.Object.GetName())
+}
+
+// SetAnnotationsRunner adds the provided annotations to the provided
+// object when run
+type SetAnnotationsRunner struct {
+	Object      client.Object
+	Annotations map[string]string
+}
+
+// compile time check to assert if the structure
+// SetAnnotationsRunner implements the interface Runner
+var _ Runner = (*SetAnnotationsRunner)(nil)
+
+// Run adds the annotations to the object
+func (s *SetAnnotationsRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if s == nil {
+		return errors.New("nil set annotations runner")
+	}
+	_, err := SetAnnotations(ctx, s.Object, s.Annotations, opts...)
+	return err
+}
+
+// String describes the runner
+func (s *SetAnnotationsRunner) String() string {
+	if s == nil || s.Object == nil {
+		return "set annotations"
+	}
+	return fmt.Sprintf("set annotations of %s/%s", s.Object.GetNamespace(), s.Object.GetName())
+}
+
+// RemoveFinalizerRunner removes the provided finalizer from the provided
+// object when run e.g. on behalf of the controller that owns the
+// finalizer
+type RemoveFinalizerRunner struct {
+	Object    client.Object
+	Finalizer string
+}
+
+// compile time check to assert if the structure
+// RemoveFinalizerRunner implements the interface Runner
+var _ Runner = (*RemoveFinalizerRunner)(nil)
+
+// Run removes the finalizer from the object
+func (r *RemoveFinalizerRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if r == nil {
+		return errors.New("nil remove finalizer runner")
+	}
+	_, err := RemoveFinalizer(ctx, r.Object, r.Finalizer, opts...)
+	return err
+}
+
+// String describes the runner
+func (r *RemoveFinalizerRunner) String() string {
+	if r == nil || r.Object == nil {
+		return "remove finalizer"
+	}
+	return fmt.Sprintf("remove finalizer %q of %s/%s", r.Finalizer, r.Object.GetNamespace(), r.Object.GetName())
+}
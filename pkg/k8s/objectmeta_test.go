@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRemoveFinalizerRunner(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "remove-finalizer",
+			Namespace:  "default",
+			Finalizers: []string{"protect.io/storage", "protect.io/compute"},
+		},
+	}
+
+	var scenarios = []struct {
+		name               string
+		finalizers         []string
+		expectedFinalizers []string
+	}{
+		{
+			name:               "should retain the finalizers when none is removed",
+			expectedFinalizers: []string{"protect.io/network", "protect.io/storage", "protect.io/compute"},
+		},
+		{
+			name:               "should remove the added finalizer only",
+			finalizers:         []string{"protect.io/network"},
+			expectedFinalizers: cm.Finalizers,
+		},
+		{
+			name:               "should remove the finalizer & retain the others",
+			finalizers:         []string{"protect.io/network", "protect.io/storage"},
+			expectedFinalizers: []string{"protect.io/compute"},
+		},
+		{
+			name:       "should remove all the finalizers",
+			finalizers: []string{"protect.io/network", "protect.io/storage", "protect.io/compute"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build()
+			opts := &RunOptions{Client: cli}
+
+			// add a finalizer as its controller would
+			_, err := Upsert(context.Background(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       cm.Name,
+					Namespace:  cm.Namespace,
+					Finalizers: append([]string{"protect.io/network"}, cm.Finalizers...),
+				},
+			}, opts)
+			assert.NoError(t, err)
+
+			for _, finalizer := range scenario.finalizers {
+				r := &RemoveFinalizerRunner{Object: cm, Finalizer: finalizer}
+				assert.NoError(t, r.Run(context.Background(), opts))
+			}
+
+			got, err := Get(context.Background(), cm, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectedFinalizers, got.GetFinalizers())
+		})
+	}
+}
+
+func TestSetLabelsAndAnnotations(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "set-labels",
+			Namespace:   "default",
+			Labels:      map[string]string{"tier": "backend", "team": "storage"},
+			Annotations: map[string]string{"owner": "alice"},
+		},
+	}
+	cli := fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build()
+	opts := &RunOptions{Client: cli}
+
+	job := &Job{Runners: []Runner{
+		&SetLabelsRunner{Object: cm, Labels: map[string]string{"tier": "frontend"}},
+		&SetAnnotationsRunner{Object: cm, Annotations: map[string]string{"reviewer": "bob"}},
+	}}
+	assert.NoError(t, job.Run(context.Background(), opts))
+
+	got, err := Get(context.Background(), cm, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"tier": "frontend", "team": "storage"}, got.GetLabels())
+	assert.Equal(t, map[string]string{"owner": "alice", "reviewer": "bob"}, got.GetAnnotations())
+}
@@ -2,27 +2,45 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/simplekube/kit/pkg/apply"
 	"github.com/simplekube/kit/pkg/k8sutil"
 	"github.com/simplekube/kit/pkg/pointer"
+	"github.com/simplekube/kit/pkg/util"
 
-	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
+// gvkForObject returns the group version kind of the provided object.
+// The embedded group version kind of an unstructured instance is used
+// as-is & hence supports custom resources that are not registered in the
+// scheme.
+func gvkForObject(object client.Object, rscheme *runtime.Scheme) (schema.GroupVersionKind, error) {
+	if u, ok := object.(*unstructured.Unstructured); ok && u.GetKind() != "" && u.GetAPIVersion() != "" {
+		return u.GroupVersionKind(), nil
+	}
+	return apiutil.GVKForObject(object, rscheme)
+}
+
 func GetKindVersionForObject(object client.Object, rscheme *runtime.Scheme) (kind string, version string, err error) {
 	gvk, err := apiutil.GVKForObject(object, rscheme)
 	if err != nil {
@@ -36,10 +54,12 @@ func GetKindVersionForObject(object client.Object, rscheme *runtime.Scheme) (kin
 //
 // Note: This can be overridden if specific options are provided
 // during the function invocation
-var _baseRunOptions *RunOptions = &RunOptions{}
-var _baseRunOptionsOnce sync.Once
+var _baseRunOptions *RunOptions = &RunOptions{clientsets: &clientsetCache{}}
 var _isBaseRunOptionsRegistered bool
 
+// _baseRunOptionsMu guards the base run options & its registration flag
+var _baseRunOptionsMu sync.RWMutex
+
 // RegisterBaseRunOptions is used to set default or common
 // run options once instead of specifying them repeatedly
 // across each function invocations
@@ -47,26 +67,51 @@ func RegisterBaseRunOptions(options *RunOptions) error {
 	if options == nil {
 		return errors.New("nil base run options")
 	}
+	_baseRunOptionsMu.Lock()
+	defer _baseRunOptionsMu.Unlock()
 	if _isBaseRunOptionsRegistered {
 		return errors.New("base run options already registered")
 	}
-	_baseRunOptionsOnce.Do(func() {
-		_baseRunOptions = options
-		_isBaseRunOptionsRegistered = true
-	})
+	if options.clientsets == nil {
+		options.clientsets = &clientsetCache{}
+	}
+	_baseRunOptions = options
+	_isBaseRunOptionsRegistered = true
 	return nil
 }
 
+// UnregisterBaseRunOptions resets the base run options so that these
+// can be registered again
+//
+// Note: This is meant to be used by tests that need different base
+// run options e.g. a different client per test suite
+func UnregisterBaseRunOptions() {
+	_baseRunOptionsMu.Lock()
+	defer _baseRunOptionsMu.Unlock()
+	_baseRunOptions = &RunOptions{clientsets: &clientsetCache{}}
+	_isBaseRunOptionsRegistered = false
+}
+
+// getBaseRunOptions returns the base run options
+func getBaseRunOptions() *RunOptions {
+	_baseRunOptionsMu.RLock()
+	defer _baseRunOptionsMu.RUnlock()
+	return _baseRunOptions
+}
+
 func makeRunOptionsWithBase(options ...RunOption) (*RunOptions, error) {
-	var opts = []RunOption{_baseRunOptions}
+	var opts = []RunOption{getBaseRunOptions()}
 	return FromRunOptions(append(opts, options...)...)
 }
 
 func maybeSetRunOptionsWithDefaults(options *RunOptions) error {
 	// ensure Kubernetes client is set
 	if options.Client == nil {
-		config := config.GetConfigOrDie()
-		c, err := client.New(config, client.Options{})
+		cfg, err := getClientConfig(options)
+		if err != nil {
+			return err
+		}
+		c, err := client.New(cfg, client.Options{})
 		if err != nil {
 			return errors.Wrap(err, "failed to initialise client")
 		}
@@ -106,11 +151,37 @@ func makeRunOptions(options ...RunOption) (*RunOptions, error) {
 
 type InvokeFn func(ctx context.Context, object client.Object, options ...RunOption) (client.Object, error)
 
-func InvokeOperationForAllObjects(ctx context.Context, operation InvokeFn, objects []client.Object, options ...RunOption) ([]client.Object, error) {
+// invokeVerboseFn is an InvokeFn that reports the operation result as well
+type invokeVerboseFn func(ctx context.Context, object client.Object, options ...RunOption) (client.Object, OperationResult, error)
+
+// toInvokeVerboseFn adapts the provided function to report
+// OperationResultProcessed on success
+func toInvokeVerboseFn(operation InvokeFn) invokeVerboseFn {
+	return func(ctx context.Context, object client.Object, options ...RunOption) (client.Object, OperationResult, error) {
+		got, err := operation(ctx, object, options...)
+		if err != nil {
+			return nil, OperationResultNone, err
+		}
+		return got, OperationResultProcessed, nil
+	}
+}
+
+// notifyObjectProcessed invokes the OnObjectProcessed callback if
+// it is set in the provided options
+func notifyObjectProcessed(options []RunOption, obj client.Object, result OperationResult, err error) {
+	opts, optsErr := makeRunOptionsWithBase(options...)
+	if optsErr != nil || opts.OnObjectProcessed == nil {
+		return
+	}
+	opts.OnObjectProcessed(obj, result, err)
+}
+
+func invokeOperationForAllObjects(ctx context.Context, operation invokeVerboseFn, objects []client.Object, options ...RunOption) ([]client.Object, error) {
 	var kObjs []client.Object
 	var finalError error
 	for _, obj := range objects {
-		got, err := operation(ctx, obj, options...)
+		got, result, err := operation(ctx, obj, options...)
+		notifyObjectProcessed(options, obj, result, err)
 		if err != nil {
 			finalError = multierror.Append(finalError, err)
 			continue
@@ -120,6 +191,73 @@ func InvokeOperationForAllObjects(ctx context.Context, operation InvokeFn, objec
 	return kObjs, finalError
 }
 
+func InvokeOperationForAllObjects(ctx context.Context, operation InvokeFn, objects []client.Object, options ...RunOption) ([]client.Object, error) {
+	return invokeOperationForAllObjects(ctx, toInvokeVerboseFn(operation), objects, options...)
+}
+
+// OperationOutcome is the result of invoking an operation against
+// a single object
+type OperationOutcome struct {
+	// Input is the object that was provided to the operation
+	Input client.Object
+
+	// Output is the object returned by the operation
+	Output client.Object
+
+	// Err is the error returned by the operation if any
+	Err error
+}
+
+// InvokeOperationForAllObjectsWithOutcomes executes the passed function
+// against the provided objects. Unlike InvokeOperationForAllObjects, one
+// outcome is returned per provided object in the same order as the
+// provided objects.
+func InvokeOperationForAllObjectsWithOutcomes(ctx context.Context, operation InvokeFn, objects []client.Object, options ...RunOption) ([]OperationOutcome, error) {
+	var outcomes = make([]OperationOutcome, 0, len(objects))
+	var finalError error
+	for _, obj := range objects {
+		got, err := operation(ctx, obj, options...)
+		if err != nil {
+			finalError = multierror.Append(finalError, err)
+			notifyObjectProcessed(options, obj, OperationResultNone, err)
+		} else {
+			notifyObjectProcessed(options, obj, OperationResultProcessed, nil)
+		}
+		outcomes = append(outcomes, OperationOutcome{
+			Input:  obj,
+			Output: got,
+			Err:    err,
+		})
+	}
+	return outcomes, finalError
+}
+
+// OperationForActionType returns the operation that performs the
+// provided action
+func OperationForActionType(action ActionType) (InvokeFn, error) {
+	switch action {
+	case ActionTypeCreate:
+		return Create, nil
+	case ActionTypeCreateOrMerge:
+		return Upsert, nil
+	case ActionTypeGet:
+		return Get, nil
+	case ActionTypeDelete:
+		return DeleteWrapper, nil
+	case ActionTypeUpdate:
+		return Update, nil
+	case ActionTypeApply:
+		return Apply, nil
+	case ActionTypePatch:
+		return MergePatch, nil
+	case ActionTypeUpdateStatus:
+		return UpdateStatus, nil
+	case ActionTypeDryRun:
+		return DryRun, nil
+	}
+	return nil, errors.Errorf("unsupported action %q", action)
+}
+
 // InvokeOperationForAllYAMLs executes the passed function against
 // the provided file paths
 func InvokeOperationForAllYAMLs(ctx context.Context, operation InvokeFn, filePaths []string, options ...RunOption) ([]client.Object, error) {
@@ -127,9 +265,49 @@ func InvokeOperationForAllYAMLs(ctx context.Context, operation InvokeFn, filePat
 	if err != nil {
 		return nil, err
 	}
+	cObjs, err := toClientObjects(objs, filePaths)
+	if err != nil {
+		return nil, err
+	}
+	return InvokeOperationForAllObjects(ctx, operation, cObjs, options...)
+}
+
+// InvokeOperationForArchive executes the passed function against the
+// objects found in the provided .tar, .tar.gz or .tgz archive
+func InvokeOperationForArchive(ctx context.Context, operation InvokeFn, archivePath string, options ...RunOption) ([]client.Object, error) {
+	objs, err := k8sutil.BuildObjectsFromArchive(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	cObjs, err := toClientObjects(objs, archivePath)
+	if err != nil {
+		return nil, err
+	}
+	return InvokeOperationForAllObjects(ctx, operation, cObjs, options...)
+}
 
+// InvokeOperationForManifestString executes the passed function against
+// the objects found in the provided YAML or JSON manifest string
+func InvokeOperationForManifestString(ctx context.Context, operation InvokeFn, manifest string, options ...RunOption) ([]client.Object, error) {
+	objs, err := k8sutil.BuildObjectsFromString(manifest)
+	if err != nil {
+		return nil, err
+	}
+	cObjs, err := toClientObjects(objs, "manifest string")
+	if err != nil {
+		return nil, err
+	}
+	return InvokeOperationForAllObjects(ctx, operation, cObjs, options...)
+}
+
+// toClientObjects returns the provided unstructured instances as
+// client.Object instances after discarding nil instances
+//
+// Note: source refers to the origin of the provided instances e.g.
+// file paths & is used to build the error message
+func toClientObjects(objs []*unstructured.Unstructured, source interface{}) ([]client.Object, error) {
 	if len(objs) == 0 {
-		return nil, errors.Errorf("no unstructured objects found: %q", filePaths)
+		return nil, errors.Errorf("no unstructured objects found: %q", source)
 	}
 
 	var cObjs = make([]client.Object, 0, len(objs))
@@ -139,9 +317,9 @@ func InvokeOperationForAllYAMLs(ctx context.Context, operation InvokeFn, filePat
 		}
 	}
 	if len(cObjs) == 0 {
-		return nil, errors.Errorf("no kubernetes objects found: %q", filePaths)
+		return nil, errors.Errorf("no kubernetes objects found: %q", source)
 	}
-	return InvokeOperationForAllObjects(ctx, operation, cObjs, options...)
+	return cObjs, nil
 }
 
 // InvokeOperationForYAML executes the passed function against
@@ -165,11 +343,28 @@ func Get(ctx context.Context, given client.Object, options ...RunOption) (client
 	if given == nil {
 		return nil, errors.New("nil object")
 	}
+	given, err = setDefaultNamespace(opts, given)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Cache != nil {
+		if cached, found := opts.Cache.get(given); found {
+			return cached, nil
+		}
+	}
 	actual, _ := given.DeepCopyObject().(client.Object)
-	err = opts.Client.Get(ctx, client.ObjectKeyFromObject(given), actual)
+	err = retryOnTransientError(ctx, opts.RetryPolicy, nil, func(_ error) error {
+		start := time.Now()
+		err := opts.Client.Get(ctx, client.ObjectKeyFromObject(given), actual)
+		recordOperation(opts, ActionTypeGet, given, start, err)
+		return err
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get")
 	}
+	if opts.Cache != nil {
+		opts.Cache.set(actual)
+	}
 	return actual, nil
 }
 
@@ -185,6 +380,109 @@ func GetForYAML(ctx context.Context, filePath string, options ...RunOption) (cli
 	return InvokeOperationForYAML(ctx, Get, filePath, options...)
 }
 
+// List lists the objects into the provided list & returns it
+//
+// Note: RunOptions.ListOptions can be used to filter the listed objects
+func List(ctx context.Context, list client.ObjectList, options ...RunOption) (client.ObjectList, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	if val := reflect.ValueOf(list); !val.IsValid() || (val.Kind() == reflect.Ptr && val.IsNil()) {
+		return nil, errors.New("nil list")
+	}
+	start := time.Now()
+	err = opts.Client.List(ctx, list, opts.ListOptions...)
+	recordOperation(opts, ActionTypeList, list, start, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list")
+	}
+	return list, nil
+}
+
+// ListTyped lists the objects into the provided list & returns it as
+// the same type
+//
+// Note: RunOptions.ListOptions can be used to filter the listed objects
+func ListTyped[L client.ObjectList](ctx context.Context, list L, options ...RunOption) (L, error) {
+	if _, err := List(ctx, list, options...); err != nil {
+		return list, err
+	}
+	return list, nil
+}
+
+// ParseGVK parses the provided string of the form group/version/Kind
+// e.g. apps/v1/Deployment or version/Kind e.g. v1/ConfigMap for the
+// core group
+func ParseGVK(gvk string) (schema.GroupVersionKind, error) {
+	idx := strings.LastIndex(gvk, "/")
+	if idx <= 0 || idx == len(gvk)-1 {
+		return schema.GroupVersionKind{}, errors.Errorf("invalid gvk %q: want group/version/Kind", gvk)
+	}
+	gv, err := schema.ParseGroupVersion(gvk[:idx])
+	if err != nil {
+		return schema.GroupVersionKind{}, errors.Wrapf(err, "invalid gvk %q", gvk)
+	}
+	return gv.WithKind(gvk[idx+1:]), nil
+}
+
+// ListForGVK lists the objects of the provided group/version/Kind e.g.
+// example.io/v1/Widget
+//
+// Note: RunOptions.ListOptions can be used to filter the listed objects
+func ListForGVK(ctx context.Context, gvk string, options ...RunOption) (*unstructured.UnstructuredList, error) {
+	parsed, err := ParseGVK(gvk)
+	if err != nil {
+		return nil, err
+	}
+	list := &unstructured.UnstructuredList{}
+	// Note: The list kind is used by the client to find the resource
+	list.SetGroupVersionKind(parsed.GroupVersion().WithKind(parsed.Kind + "List"))
+	return ListTyped(ctx, list, options...)
+}
+
+// setDefaultNamespace returns a copy of the provided object with its
+// namespace set to RunOptions.DefaultNamespace if the object is namespace
+// scoped & its namespace is not set. The provided object is returned
+// as-is otherwise.
+func setDefaultNamespace(opts *RunOptions, given client.Object) (client.Object, error) {
+	if opts.DefaultNamespace == "" || given.GetNamespace() != "" {
+		return given, nil
+	}
+	gvk, err := gvkForObject(given, opts.Scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, "extract gvk")
+	}
+	mapping, err := opts.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get rest mapping: %s", gvk)
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return given, nil
+	}
+	defaulted, _ := given.DeepCopyObject().(client.Object)
+	defaulted.SetNamespace(opts.DefaultNamespace)
+	return defaulted, nil
+}
+
+// ensureNamespaceExists returns error if RequireNamespaceExists option
+// is set & the namespace of the provided object is not found
+//
+// Note: Cluster scoped objects are not verified
+func ensureNamespaceExists(ctx context.Context, opts *RunOptions, given client.Object) error {
+	if opts.RequireNamespaceExists == nil || !*opts.RequireNamespaceExists {
+		return nil
+	}
+	if given.GetNamespace() == "" {
+		return nil
+	}
+	err := opts.Client.Get(ctx, client.ObjectKey{Name: given.GetNamespace()}, &corev1.Namespace{})
+	if apierrors.IsNotFound(err) {
+		return errors.Errorf("namespace %q not found: %s", given.GetNamespace(), k8sutil.DescribeObj(given))
+	}
+	return errors.Wrapf(err, "failed to get namespace %q", given.GetNamespace())
+}
+
 func Create(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
 	opts, err := makeRunOptions(options...)
 	if err != nil {
@@ -193,11 +491,27 @@ func Create(ctx context.Context, given client.Object, options ...RunOption) (cli
 	if given == nil {
 		return nil, errors.New("nil object")
 	}
+	given, err = setDefaultNamespace(opts, given)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureNamespaceExists(ctx, opts, given); err != nil {
+		return nil, err
+	}
 	actual, _ := given.DeepCopyObject().(client.Object)
-	err = opts.Client.Create(ctx, actual)
+	err = retryOnTransientError(ctx, opts.RetryPolicy, nil, func(_ error) error {
+		start := time.Now()
+		err := opts.Client.Create(ctx, actual)
+		recordOperation(opts, ActionTypeCreate, given, start, err)
+		return err
+	})
+	invalidateCache(opts, given)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create")
 	}
+	if err := registerForGarbageCollection(opts, actual); err != nil {
+		return actual, err
+	}
 	return actual, nil
 }
 
@@ -213,6 +527,10 @@ func CreateForYAML(ctx context.Context, filePath string, options ...RunOption) (
 	return InvokeOperationForYAML(ctx, Create, filePath, options...)
 }
 
+func CreateForManifestString(ctx context.Context, manifest string, options ...RunOption) ([]client.Object, error) {
+	return InvokeOperationForManifestString(ctx, Create, manifest, options...)
+}
+
 func Update(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
 	opts, err := makeRunOptions(options...)
 	if err != nil {
@@ -221,14 +539,46 @@ func Update(ctx context.Context, given client.Object, options ...RunOption) (cli
 	if given == nil {
 		return nil, errors.New("nil object")
 	}
+	given, err = setDefaultNamespace(opts, given)
+	if err != nil {
+		return nil, err
+	}
 	actual, _ := given.DeepCopyObject().(client.Object)
-	err = opts.Client.Update(ctx, actual)
+	err = retryOnTransientError(ctx, opts.RetryPolicy, isUpdateRetryable(opts), func(lastErr error) error {
+		if apierrors.IsConflict(errors.Cause(lastErr)) {
+			// re-apply the mutation to the latest state
+			latest, _ := given.DeepCopyObject().(client.Object)
+			if err := opts.Client.Get(ctx, client.ObjectKeyFromObject(given), latest); err != nil {
+				return errors.Wrap(err, "get latest")
+			}
+			if err := opts.RetryPolicy.MutateOnConflict(latest); err != nil {
+				return errors.Wrap(err, "mutate on conflict")
+			}
+			actual = latest
+		}
+		start := time.Now()
+		err := opts.Client.Update(ctx, actual)
+		recordOperation(opts, ActionTypeUpdate, given, start, err)
+		return err
+	})
+	invalidateCache(opts, given)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to update")
 	}
 	return actual, nil
 }
 
+// isUpdateRetryable returns the errors of Update that are retryable.
+// Conflicts are retryable only if the mutation can be re-applied.
+func isUpdateRetryable(opts *RunOptions) func(error) bool {
+	return func(err error) bool {
+		if apierrors.IsConflict(errors.Cause(err)) {
+			return opts.RetryPolicy != nil && opts.RetryPolicy.MutateOnConflict != nil
+		}
+		return isTransientError(err)
+	}
+}
+
 func UpdateAll(ctx context.Context, given []client.Object, options ...RunOption) ([]client.Object, error) {
 	return InvokeOperationForAllObjects(ctx, Update, given, options...)
 }
@@ -241,6 +591,156 @@ func UpdateForYAML(ctx context.Context, filePath string, options ...RunOption) (
 	return InvokeOperationForYAML(ctx, Update, filePath, options...)
 }
 
+// UpdateStatus updates the status subresource of the provided object &
+// returns the object as updated by the server
+func UpdateStatus(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	if given == nil {
+		return nil, errors.New("nil object")
+	}
+	given, err = setDefaultNamespace(opts, given)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := given.DeepCopyObject().(client.Object)
+	start := time.Now()
+	err = opts.Client.Status().Update(ctx, actual)
+	recordOperation(opts, ActionTypeUpdateStatus, given, start, err)
+	invalidateCache(opts, given)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update status")
+	}
+	return actual, nil
+}
+
+// GetStatus fetches the status subresource of the provided object into
+// the provided object & returns it e.g. to observe the status written by
+// a controller
+//
+// Note: The rest config is used since the controller-runtime client does
+// not get subresources
+func GetStatus(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	if given == nil {
+		return nil, errors.New("nil object")
+	}
+	key, err := setDefaultNamespace(opts, given)
+	if err != nil {
+		return nil, err
+	}
+	gvk, err := gvkForObject(given, opts.Scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, "extract gvk")
+	}
+	mapping, err := opts.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get rest mapping: %s", gvk)
+	}
+	cfg, err := getClientConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	_, isUnstructured := given.(*unstructured.Unstructured)
+	rc, err := apiutil.RESTClientForGVK(gvk, isUnstructured, cfg, serializer.NewCodecFactory(opts.Scheme))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to initialise rest client: %s", gvk)
+	}
+	err = retryOnTransientError(ctx, opts.RetryPolicy, nil, func(_ error) error {
+		start := time.Now()
+		err := rc.Get().
+			NamespaceIfScoped(key.GetNamespace(), mapping.Scope.Name() == meta.RESTScopeNameNamespace).
+			Resource(mapping.Resource.Resource).
+			Name(key.GetName()).
+			SubResource("status").
+			Do(ctx).
+			Into(given)
+		recordOperation(opts, ActionTypeGet, given, start, err)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get status")
+	}
+	return given, nil
+}
+
+// Patch patches the provided object with the provided patch & returns
+// the patched object as updated by the server
+func Patch(ctx context.Context, given client.Object, patch client.Patch, options ...RunOption) (client.Object, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	if given == nil {
+		return nil, errors.New("nil object")
+	}
+	given, err = setDefaultNamespace(opts, given)
+	if err != nil {
+		return nil, err
+	}
+	if patch == nil {
+		return nil, errors.New("nil patch")
+	}
+	actual, _ := given.DeepCopyObject().(client.Object)
+	err = retryOnTransientError(ctx, opts.RetryPolicy, nil, func(_ error) error {
+		start := time.Now()
+		err := opts.Client.Patch(ctx, actual, patch)
+		recordOperation(opts, ActionTypePatch, given, start, err)
+		return err
+	})
+	invalidateCache(opts, given)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to patch")
+	}
+	return actual, nil
+}
+
+// MergePatch patches the object using the provided object itself as
+// a JSON merge patch
+func MergePatch(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
+	return Patch(ctx, given, client.Merge, options...)
+}
+
+// StrategicMergePatch patches the object using the provided object
+// itself as a strategic merge patch
+//
+// Note: Strategic merge patch is not supported for custom resources
+func StrategicMergePatch(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
+	if given == nil {
+		return nil, errors.New("nil object")
+	}
+	data, err := json.Marshal(given)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal strategic merge patch")
+	}
+	return Patch(ctx, given, client.RawPatch(types.StrategicMergePatchType, data), options...)
+}
+
+// JSONPatch returns an operation that patches the object with the
+// provided JSON patch e.g. [{"op": "remove", "path": "/data/key"}]
+func JSONPatch(patch []byte) InvokeFn {
+	return func(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
+		return Patch(ctx, given, client.RawPatch(types.JSONPatchType, patch), options...)
+	}
+}
+
+func MergePatchAll(ctx context.Context, given []client.Object, options ...RunOption) ([]client.Object, error) {
+	return InvokeOperationForAllObjects(ctx, MergePatch, given, options...)
+}
+
+func MergePatchForAllYAMLs(ctx context.Context, filePaths []string, options ...RunOption) ([]client.Object, error) {
+	return InvokeOperationForAllYAMLs(ctx, MergePatch, filePaths, options...)
+}
+
+func MergePatchForYAML(ctx context.Context, filePath string, options ...RunOption) (kObj client.Object, err error) {
+	return InvokeOperationForYAML(ctx, MergePatch, filePath, options...)
+}
+
 // OperationResult is the action result of a CreateOrUpdate call
 //
 // credit: https://github.com/kubernetes-sigs/controller-runtime/tree/master/pkg/controller/controllerutil
@@ -258,6 +758,10 @@ const (
 
 	// OperationResultUpdatedResourceAndStatus implies an existing resource as well as its status got updated
 	OperationResultUpdatedResourceAndStatus OperationResult = "updated-resource-and-status"
+
+	// OperationResultProcessed implies that the operation succeeded without
+	// reporting any specific result
+	OperationResultProcessed OperationResult = "processed"
 )
 
 func upsertVerbose(
@@ -267,16 +771,18 @@ func upsertVerbose(
 	desired client.Object,
 	acceptNullValues bool,
 	setFinalizersToNull bool,
-) (client.Object, OperationResult, error) {
+	preserveFields []string,
+	redact redactor,
+) (client.Object, OperationResult, string, error) {
 	if cli == nil {
-		return nil, OperationResultNone, errors.New("nil client")
+		return nil, OperationResultNone, "", errors.New("nil client")
 	}
 	if desired == nil {
-		return nil, OperationResultNone, errors.New("nil desired object")
+		return nil, OperationResultNone, "", errors.New("nil desired object")
 	}
-	gvk, err := apiutil.GVKForObject(desired, scheme)
+	gvk, err := gvkForObject(desired, scheme)
 	if err != nil {
-		return nil, OperationResultNone, errors.Wrap(err, "extract gvk")
+		return nil, OperationResultNone, "", errors.Wrap(err, "extract gvk")
 	}
 
 	// build the observed instance
@@ -290,19 +796,19 @@ func upsertVerbose(
 
 	if err := cli.Get(ctx, client.ObjectKeyFromObject(desired), observedObj); err != nil {
 		if !apierrors.IsNotFound(err) {
-			return nil, OperationResultNone, err
+			return nil, OperationResultNone, "", err
 		}
 		var created = desired.DeepCopyObject().(client.Object)
 		if err := cli.Create(ctx, created); err != nil {
-			return nil, OperationResultNone, err
+			return nil, OperationResultNone, "", err
 		}
-		return created, OperationResultCreated, nil
+		return created, OperationResultCreated, "", nil
 	}
 
 	observedUnstruct := observedObj.Object
 	desiredUnstruct, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired.DeepCopyObject())
 	if err != nil {
-		return nil, OperationResultNone, errors.Wrap(err, "convert desired to unstructured")
+		return nil, OperationResultNone, "", errors.Wrap(err, "convert desired to unstructured")
 	}
 
 	if !acceptNullValues {
@@ -316,7 +822,7 @@ func upsertVerbose(
 		// update calls
 		desiredUnstruct, err = DeleteNullInUnstructuredMap(desiredUnstruct)
 		if err != nil {
-			return nil, OperationResultNone, err
+			return nil, OperationResultNone, "", err
 		}
 	}
 
@@ -325,7 +831,7 @@ func upsertVerbose(
 	// against the cluster
 	mergedUnstruct, err := ThreeWayLocalMergeWithTwoObjects(observedUnstruct, desiredUnstruct)
 	if err != nil {
-		return nil, OperationResultNone, err
+		return nil, OperationResultNone, "", err
 	}
 
 	// Convert generic maps to Kubernetes Unstructured instances. This is
@@ -333,7 +839,7 @@ func upsertVerbose(
 	var mergedObj = &unstructured.Unstructured{}
 	err = runtime.DefaultUnstructuredConverter.FromUnstructured(mergedUnstruct, mergedObj)
 	if err != nil {
-		return nil, OperationResultNone, errors.Wrap(err, "create merged object from unstructured")
+		return nil, OperationResultNone, "", errors.Wrap(err, "create merged object from unstructured")
 	}
 
 	if setFinalizersToNull {
@@ -342,6 +848,32 @@ func upsertVerbose(
 		mergedObj.SetFinalizers(nil)
 	}
 
+	if acceptNullValues && desired.GetFinalizers() != nil && len(desired.GetFinalizers()) == 0 {
+		// An explicit empty list of finalizers clears the observed ones
+		//
+		// Note: This is done since empty finalizers are dropped while
+		// converting the desired object to unstructured & hence have no
+		// effect on the merge
+		//
+		// Note: Nil finalizers can not be told apart from unset finalizers
+		// & hence are left as-is
+		mergedObj.SetFinalizers(nil)
+	}
+
+	// Retain the observed values of fields that are owned by controllers
+	// e.g. spec.clusterIP of a Service. Not doing so may revert these
+	// fields & result in un-necessary update calls.
+	for _, path := range preserveFields {
+		fields, err := ParseFieldPath(path)
+		if err != nil {
+			return nil, OperationResultNone, "", err
+		}
+		err = overrideField(mergedObj, observedObj, fields...)
+		if err != nil {
+			return nil, OperationResultNone, "", err
+		}
+	}
+
 	// Handle metadata system fields i.e. read-only fields by setting
 	// them in the merged object from the observed object. Syncing
 	// read only fields enables comparing observed & merged object without
@@ -359,23 +891,27 @@ func upsertVerbose(
 	if equality.Semantic.DeepEqual(observedObj, mergedObj) {
 		// Update is ignored if there are no changes between desired & observed
 		// states
-		return nil, OperationResultNone, nil
+		return nil, OperationResultNone, "", nil
 	}
 
+	// diff between the observed & the merged states that is about to be
+	// applied against the cluster
+	diff := redact.diff(observedObj.Object, mergedObj.Object, desired)
+
 	// make a copy to update the status of this resource separately
 	var mergedStatusObj = mergedObj.DeepCopy()
 	// 1/ update resource
 	err = cli.Update(ctx, mergedObj)
 	if err != nil {
-		return nil, OperationResultNone, errors.Wrap(err, "update resource")
+		return nil, OperationResultNone, diff, errors.Wrap(err, "update resource")
 	}
 
 	hasStatus, err := IsStatusSubResourceSet(desiredUnstruct)
 	if err != nil {
-		return nil, OperationResultUpdatedResourceOnly, errors.Wrap(err, "is status set")
+		return nil, OperationResultUpdatedResourceOnly, diff, errors.Wrap(err, "is status set")
 	}
 	if !hasStatus {
-		return mergedObj, OperationResultUpdatedResourceOnly, nil
+		return mergedObj, OperationResultUpdatedResourceOnly, diff, nil
 	}
 
 	// update resource version before proceeding with status update
@@ -383,104 +919,576 @@ func upsertVerbose(
 	// 2/ update resource status
 	err = cli.Status().Update(ctx, mergedStatusObj)
 	if err != nil {
-		return nil, OperationResultUpdatedResourceOnly, errors.Wrap(err, "update status")
+		return nil, OperationResultUpdatedResourceOnly, diff, errors.Wrap(err, "update status")
 	}
 
 	// get the updated object from the cluster
 	err = cli.Get(ctx, client.ObjectKeyFromObject(desired), mergedObj)
 	if err != nil {
-		return nil, OperationResultUpdatedResourceAndStatus, errors.Wrap(err, "fetch updated resource")
+		return nil, OperationResultUpdatedResourceAndStatus, diff, errors.Wrap(err, "fetch updated resource")
+	}
+	return mergedObj, OperationResultUpdatedResourceAndStatus, diff, nil
+}
+
+// UpsertVerboseWithDiff creates the given resource if it does not exist
+// in the cluster or else merges it into the observed resource. The diff
+// between the observed & the merged states is returned when the
+// resource gets updated. The diff is empty otherwise.
+//
+// Note: The result & the diff are logged if a logger is set in the
+// options
+func UpsertVerboseWithDiff(ctx context.Context, given client.Object, options ...RunOption) (client.Object, OperationResult, string, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, OperationResultNone, "", err
+	}
+	if given != nil {
+		given, err = setDefaultNamespace(opts, given)
+		if err != nil {
+			return nil, OperationResultNone, "", err
+		}
+		if err := ensureNamespaceExists(ctx, opts, given); err != nil {
+			return nil, OperationResultNone, "", err
+		}
+	}
+	start := time.Now()
+	actual, result, diff, err := upsertVerbose(ctx, opts.Client, opts.Scheme, given, *opts.AcceptNullFieldValuesDuringUpsert, *opts.SetFinalizersToNullDuringUpsert, opts.PreserveObservedFieldsDuringUpsert, newRedactor(opts))
+	if given != nil {
+		recordOperation(opts, ActionTypeCreateOrMerge, given, start, err)
+		invalidateCache(opts, given)
+		if err == nil {
+			getLogger(opts).V(1).Info("upserted", "key", k8sutil.ObjKey(given), "result", result, "diff", diff)
+		}
+	}
+	if err == nil && result == OperationResultCreated {
+		if err := registerForGarbageCollection(opts, actual); err != nil {
+			return actual, result, diff, err
+		}
+	}
+	return actual, result, diff, err
+}
+
+func UpsertVerbose(ctx context.Context, given client.Object, options ...RunOption) (client.Object, OperationResult, error) {
+	actual, result, _, err := UpsertVerboseWithDiff(ctx, given, options...)
+	return actual, result, err
+}
+
+func Upsert(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
+	actual, _, err := UpsertVerbose(ctx, given, options...)
+	return actual, err
+}
+
+func UpsertAll(ctx context.Context, given []client.Object, options ...RunOption) ([]client.Object, error) {
+	return invokeOperationForAllObjects(ctx, UpsertVerbose, given, options...)
+}
+
+func UpsertForAllYAMLs(ctx context.Context, filePaths []string, options ...RunOption) ([]client.Object, error) {
+	return InvokeOperationForAllYAMLs(ctx, Upsert, filePaths, options...)
+}
+
+func UpsertForYAML(ctx context.Context, filePath string, options ...RunOption) (kObj client.Object, err error) {
+	return InvokeOperationForYAML(ctx, Upsert, filePath, options...)
+}
+
+func Delete(ctx context.Context, given client.Object, options ...RunOption) error {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	if given == nil {
+		return errors.New("nil object")
+	}
+	given, err = setDefaultNamespace(opts, given)
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientError(ctx, opts.RetryPolicy, nil, func(_ error) error {
+		start := time.Now()
+		err := opts.Client.Delete(ctx, given, opts.DeleteOptions...)
+		recordOperation(opts, ActionTypeDelete, given, start, err)
+		return err
+	})
+	invalidateCache(opts, given)
+	if err != nil && opts.DiagnoseDeleteFailures != nil && *opts.DiagnoseDeleteFailures {
+		blockers, diagErr := DescribeDeletionBlockers(ctx, given, opts)
+		if diagErr != nil {
+			return errors.Wrapf(err, "diagnose: %s", diagErr)
+		}
+		return errors.Wrap(err, blockers)
+	}
+	return err
+}
+
+// ForceDelete removes the finalizers of the provided object & then
+// deletes it immediately i.e. with a zero grace period. An object that
+// is not found is considered as deleted.
+//
+// Note: This is meant to cleanup objects that are stuck in terminating
+// state & hence bypasses the controllers that own these finalizers
+func ForceDelete(ctx context.Context, given client.Object, options ...RunOption) error {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	if given == nil {
+		return errors.New("nil object")
+	}
+	actual, err := Get(ctx, given, opts)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return err
+	}
+	if err = removeAllFinalizers(ctx, opts, actual); err != nil {
+		return err
+	}
+	// Note: Delete options set by the caller override the zero grace period
+	deleteOpts := append([]client.DeleteOption{client.GracePeriodSeconds(0)}, opts.DeleteOptions...)
+	start := time.Now()
+	err = opts.Client.Delete(ctx, actual, deleteOpts...)
+	recordOperation(opts, ActionTypeDelete, actual, start, err)
+	invalidateCache(opts, actual)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete")
+	}
+	return nil
+}
+
+// removeAllFinalizers removes the finalizers of the provided object that
+// was observed from the cluster. An object that is not found is ignored.
+//
+// Note: An object that is terminating is deleted by the cluster once its
+// finalizers are removed
+func removeAllFinalizers(ctx context.Context, opts *RunOptions, actual client.Object) error {
+	if len(actual.GetFinalizers()) == 0 {
+		return nil
+	}
+	// observed state is used as the desired state to avoid
+	// updating any field other than the finalizers
+	_, err := Upsert(ctx, actual, opts, &RunOptions{SetFinalizersToNullDuringUpsert: pointer.Bool(true)})
+	if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+		return errors.Wrap(err, "failed to remove finalizers")
+	}
+	return nil
+}
+
+// DescribeDeletionBlockers returns a string format of the fields of the
+// provided object that may block its deletion i.e. its deletion timestamp,
+// finalizers & owner references
+func DescribeDeletionBlockers(ctx context.Context, given client.Object, options ...RunOption) (string, error) {
+	actual, err := Get(ctx, given, options...)
+	if err != nil {
+		return "", err
+	}
+	var owners = make([]string, 0, len(actual.GetOwnerReferences()))
+	for _, ref := range actual.GetOwnerReferences() {
+		owners = append(owners, fmt.Sprintf("%s/%s", ref.Kind, ref.Name))
+	}
+	var deletionTimestamp string
+	if actual.GetDeletionTimestamp() != nil {
+		deletionTimestamp = actual.GetDeletionTimestamp().String()
+	}
+	return fmt.Sprintf(
+		"%s: deletionTimestamp=%q: finalizers=%q: ownerReferences=%q",
+		k8sutil.DescribeObj(actual), deletionTimestamp, actual.GetFinalizers(), owners,
+	), nil
+}
+
+// DeleteWrapper invokes delete operation & ensures its signature
+// matches with other invocations like Get, Create, Update, Apply & DryRun.
+func DeleteWrapper(ctx context.Context, given client.Object, options ...RunOption) (_ client.Object, err error) {
+	return nil, Delete(ctx, given, options...)
+}
+
+func DeleteAll(ctx context.Context, given []client.Object, options ...RunOption) error {
+	_, err := InvokeOperationForAllObjects(ctx, DeleteWrapper, given, options...)
+	return err
+}
+
+// DeleteOutcome is the result of deleting a single object
+type DeleteOutcome struct {
+	// Key identifies the object that was deleted
+	Key string
+
+	// Deleted is true if the object was deleted by this invocation. It
+	// is false if the object was already gone or failed to be deleted.
+	Deleted bool
+
+	// Err is the error returned by the delete operation if any
+	Err error
+}
+
+// TeardownReport lists the outcomes of deleting a set of objects in
+// the order of execution
+type TeardownReport struct {
+	Outcomes []DeleteOutcome
+}
+
+// DeleteAllWithReport deletes the provided objects in the provided order
+// & reports the outcome of each deletion. Objects that are not found are
+// reported as neither deleted nor failed. The returned error combines the
+// errors of all the failed deletions.
+func DeleteAllWithReport(ctx context.Context, given []client.Object, options ...RunOption) (*TeardownReport, error) {
+	var report = &TeardownReport{
+		Outcomes: make([]DeleteOutcome, 0, len(given)),
+	}
+	var finalError error
+	for _, obj := range given {
+		err := Delete(ctx, obj, options...)
+		if err != nil && apierrors.IsNotFound(errors.Cause(err)) {
+			notifyObjectProcessed(options, obj, OperationResultNone, nil)
+			report.Outcomes = append(report.Outcomes, DeleteOutcome{Key: k8sutil.DescribeObj(obj)})
+			continue
+		}
+		if err != nil {
+			finalError = multierror.Append(finalError, err)
+			notifyObjectProcessed(options, obj, OperationResultNone, err)
+		} else {
+			notifyObjectProcessed(options, obj, OperationResultProcessed, nil)
+		}
+		report.Outcomes = append(report.Outcomes, DeleteOutcome{
+			Key:     k8sutil.DescribeObj(obj),
+			Deleted: err == nil,
+			Err:     err,
+		})
+	}
+	return report, finalError
+}
+
+func DeleteForAllYAMLs(ctx context.Context, filePaths []string, options ...RunOption) error {
+	_, err := InvokeOperationForAllYAMLs(ctx, DeleteWrapper, filePaths, options...)
+	return err
+}
+
+func DeleteForYAML(ctx context.Context, filePath string, options ...RunOption) error {
+	_, err := InvokeOperationForYAML(ctx, DeleteWrapper, filePath, options...)
+	return err
+}
+
+// waitForAbsence polls the provided object till it is not found. Retry
+// interval & retry timeout are derived from KindDefaults.
+func waitForAbsence(ctx context.Context, opts *RunOptions, given client.Object) error {
+	return waitForAbsenceWithin(ctx, opts, given, EventuallyOptions{})
+}
+
+// waitForAbsenceWithin is same as waitForAbsence but uses the provided
+// retry interval & retry timeout if set
+func waitForAbsenceWithin(ctx context.Context, opts *RunOptions, given client.Object, within EventuallyOptions) error {
+	eventually, err := EventuallyOptionsForObject(given, within, opts.Scheme)
+	if err != nil {
+		return err
+	}
+	if eventually.RetryInterval == 0 {
+		eventually.RetryInterval = defaultRetryInterval
+	}
+	if eventually.RetryTimeout == 0 {
+		eventually.RetryTimeout = defaultRetryTimeout
+	}
+	return util.RetryWithContext(ctx, util.RetryOptions{
+		Immediate: true,
+		Interval:  eventually.RetryInterval,
+		Timeout:   eventually.RetryTimeout,
+	}, func() (bool, error) {
+		// each attempt observes the latest state
+		invalidateCache(opts, given)
+		_, err := Get(ctx, given, opts)
+		if err == nil {
+			return false, errors.Errorf("still present: %s", k8sutil.DescribeObj(given))
+		}
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// deleteAndWaitForAbsence deletes the provided object & waits till it is
+// not found. An object that is stuck e.g. due to finalizers whose owners
+// are not running is force deleted after the wait times out.
+func deleteAndWaitForAbsence(ctx context.Context, opts *RunOptions, given client.Object) error {
+	err := Delete(ctx, given, opts)
+	if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+		return err
+	}
+	if err = waitForAbsence(ctx, opts, given); err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+	if err = ForceDelete(ctx, given, opts); err != nil {
+		return errors.Wrap(err, "force delete")
+	}
+	return waitForAbsence(ctx, opts, given)
+}
+
+// DeleteDirectorySorted deletes the objects found in the manifests of
+// the provided directory one after the other in the reverse of their
+// dependency order e.g. custom resources before their CRDs & namespaced
+// objects before their namespace. Each object is waited upon till it is
+// not found before the next one is deleted. Deletion stops at the first
+// error.
+//
+// Note: This is the counterpart of ApplyDirectorySorted
+func DeleteDirectorySorted(ctx context.Context, dir string, options ...RunOption) error {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	objs, err := k8sutil.BuildSortableObjectsFromYMLs([]string{dir})
+	if err != nil {
+		return err
+	}
+	cObjs, err := toClientObjects(objs, dir)
+	if err != nil {
+		return err
+	}
+	for idx := len(cObjs) - 1; idx >= 0; idx-- {
+		obj := cObjs[idx]
+		err := deleteAndWaitForAbsence(ctx, opts, obj)
+		if err != nil {
+			notifyObjectProcessed(options, obj, OperationResultNone, err)
+			return errors.Wrapf(err, "delete %s", k8sutil.DescribeObj(obj))
+		}
+		notifyObjectProcessed(options, obj, OperationResultProcessed, nil)
+	}
+	return nil
+}
+
+// defaultFieldOwner is the field manager used by server side apply
+// based operations when RunOptions.FieldOwner is not set
+const defaultFieldOwner = "k8s-toolkit-operation"
+
+// fieldOwnerOrDefault returns the field owner set in the provided
+// options or else the default field owner
+func fieldOwnerOrDefault(opts *RunOptions) string {
+	if opts.FieldOwner != "" {
+		return opts.FieldOwner
 	}
-	return mergedObj, OperationResultUpdatedResourceAndStatus, nil
+	return defaultFieldOwner
 }
 
-func UpsertVerbose(ctx context.Context, given client.Object, options ...RunOption) (client.Object, OperationResult, error) {
+func Apply(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
 	opts, err := makeRunOptions(options...)
 	if err != nil {
-		return nil, OperationResultNone, err
+		return nil, err
+	}
+	if given == nil {
+		return nil, errors.New("nil object")
+	}
+	given, err = setDefaultNamespace(opts, given)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureNamespaceExists(ctx, opts, given); err != nil {
+		return nil, err
+	}
+	patchOpts := []client.PatchOption{
+		client.FieldOwner(fieldOwnerOrDefault(opts)),
+	}
+	var isForced = opts.ConflictStrategy == "" || opts.ConflictStrategy == ConflictStrategyForce
+	if isForced {
+		patchOpts = append(patchOpts, client.ForceOwnership)
 	}
-	return upsertVerbose(ctx, opts.Client, opts.Scheme, given, *opts.AcceptNullFieldValuesDuringUpsert, *opts.SetFinalizersToNullDuringUpsert)
+	actual, _ := given.DeepCopyObject().(client.Object)
+	err = retryOnTransientError(ctx, opts.RetryPolicy, isApplyRetryable(isForced), func(_ error) error {
+		start := time.Now()
+		err := opts.Client.Patch(ctx, actual, client.Apply, patchOpts...)
+		recordOperation(opts, ActionTypeApply, given, start, err)
+		return err
+	})
+	invalidateCache(opts, given)
+	if err != nil {
+		if opts.ConflictStrategy == ConflictStrategyReport && apierrors.IsConflict(err) {
+			return nil, &ApplyConflictError{
+				Key:       client.ObjectKeyFromObject(given),
+				Conflicts: parseApplyConflicts(err),
+			}
+		}
+		return nil, errors.Wrap(err, "failed to apply")
+	}
+	return actual, nil
 }
 
-func Upsert(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
-	actual, _, err := UpsertVerbose(ctx, given, options...)
-	return actual, err
+// isApplyRetryable returns the errors that are retried by Apply. Conflicts
+// are not retried unless the ownership of the fields is forced since
+// these are due to other field managers & would conflict again.
+func isApplyRetryable(isForced bool) func(error) bool {
+	if isForced {
+		return nil
+	}
+	return func(err error) bool {
+		return !apierrors.IsConflict(errors.Cause(err)) && isTransientError(err)
+	}
 }
 
-func UpsertAll(ctx context.Context, given []client.Object, options ...RunOption) ([]client.Object, error) {
-	return InvokeOperationForAllObjects(ctx, Upsert, given, options...)
+func ApplyAll(ctx context.Context, given []client.Object, options ...RunOption) ([]client.Object, error) {
+	return InvokeOperationForAllObjects(ctx, Apply, given, options...)
 }
 
-func UpsertForAllYAMLs(ctx context.Context, filePaths []string, options ...RunOption) ([]client.Object, error) {
-	return InvokeOperationForAllYAMLs(ctx, Upsert, filePaths, options...)
+// ApplyAllWithOutcomes applies the provided objects & returns one outcome
+// per provided object in the same order as the provided objects
+//
+// Note: Provided objects are not mutated
+func ApplyAllWithOutcomes(ctx context.Context, given []client.Object, options ...RunOption) ([]OperationOutcome, error) {
+	return InvokeOperationForAllObjectsWithOutcomes(ctx, Apply, given, options...)
 }
 
-func UpsertForYAML(ctx context.Context, filePath string, options ...RunOption) (kObj client.Object, err error) {
-	return InvokeOperationForYAML(ctx, Upsert, filePath, options...)
+func ApplyAllYAMLs(ctx context.Context, filePaths []string, options ...RunOption) ([]client.Object, error) {
+	return InvokeOperationForAllYAMLs(ctx, Apply, filePaths, options...)
 }
 
-func Delete(ctx context.Context, given client.Object, options ...RunOption) error {
-	opts, err := makeRunOptions(options...)
-	if err != nil {
-		return err
-	}
-	if given == nil {
-		return errors.New("nil object")
-	}
-	return opts.Client.Delete(ctx, given)
+func ApplyYAML(ctx context.Context, filePath string, options ...RunOption) (kObj client.Object, err error) {
+	return InvokeOperationForYAML(ctx, Apply, filePath, options...)
 }
 
-// DeleteWrapper invokes delete operation & ensures its signature
-// matches with other invocations like Get, Create, Update, Apply & DryRun.
-func DeleteWrapper(ctx context.Context, given client.Object, options ...RunOption) (_ client.Object, err error) {
-	return nil, Delete(ctx, given, options...)
+func ApplyArchive(ctx context.Context, archivePath string, options ...RunOption) ([]client.Object, error) {
+	return InvokeOperationForArchive(ctx, Apply, archivePath, options...)
 }
 
-func DeleteAll(ctx context.Context, given []client.Object, options ...RunOption) error {
-	_, err := InvokeOperationForAllObjects(ctx, DeleteWrapper, given, options...)
-	return err
+func ApplyForManifestString(ctx context.Context, manifest string, options ...RunOption) ([]client.Object, error) {
+	return InvokeOperationForManifestString(ctx, Apply, manifest, options...)
 }
 
-func DeleteForAllYAMLs(ctx context.Context, filePaths []string, options ...RunOption) error {
-	_, err := InvokeOperationForAllYAMLs(ctx, DeleteWrapper, filePaths, options...)
-	return err
+// isCustomResourceDefinition returns true if the provided object is a CRD
+func isCustomResourceDefinition(obj client.Object) bool {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	return gvk.Group == "apiextensions.k8s.io" && gvk.Kind == "CustomResourceDefinition"
 }
 
-func DeleteForYAML(ctx context.Context, filePath string, options ...RunOption) error {
-	_, err := InvokeOperationForYAML(ctx, DeleteWrapper, filePath, options...)
-	return err
+// ApplyDirectorySorted applies the objects found in the manifests of the
+// provided directory one after the other in their dependency order e.g.
+// CRDs & namespaces before the objects that need them. Each CRD is waited
+// upon till it is established so that its custom resources can be applied
+// right after. Application stops at the first error.
+//
+// Note: Objects are sorted via k8sutil.SortableUnstructureds
+func ApplyDirectorySorted(ctx context.Context, dir string, options ...RunOption) ([]client.Object, error) {
+	objs, err := k8sutil.BuildSortableObjectsFromYMLs([]string{dir})
+	if err != nil {
+		return nil, err
+	}
+	cObjs, err := toClientObjects(objs, dir)
+	if err != nil {
+		return nil, err
+	}
+	var applied = make([]client.Object, 0, len(cObjs))
+	for _, obj := range cObjs {
+		actual, err := Apply(ctx, obj, options...)
+		if err != nil {
+			notifyObjectProcessed(options, obj, OperationResultNone, err)
+			return applied, errors.Wrapf(err, "apply %s", k8sutil.DescribeObj(obj))
+		}
+		notifyObjectProcessed(options, obj, OperationResultProcessed, nil)
+		applied = append(applied, actual)
+		if !isCustomResourceDefinition(obj) {
+			continue
+		}
+		err = WaitForCondition(ctx, actual, "Established", "True", EventuallyOptions{}, options...)
+		if err != nil {
+			return applied, errors.Wrapf(err, "wait for established %s", k8sutil.DescribeObj(obj))
+		}
+	}
+	return applied, nil
 }
 
-func Apply(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
+// ServerSideUpsertVerbose creates or updates the provided object via a
+// server side apply with force ownership & reports whether the object got
+// created, updated or was left unchanged. Unlike UpsertVerbose the API
+// server tracks the fields owned by each field manager.
+//
+// Note: The result is derived by comparing the resource version observed
+// before the apply with the one returned by the apply
+func ServerSideUpsertVerbose(ctx context.Context, given client.Object, options ...RunOption) (client.Object, OperationResult, error) {
 	opts, err := makeRunOptions(options...)
 	if err != nil {
-		return nil, err
+		return nil, OperationResultNone, err
 	}
 	if given == nil {
-		return nil, errors.New("nil object")
+		return nil, OperationResultNone, errors.New("nil object")
 	}
-	patchOpts := []client.PatchOption{
-		client.ForceOwnership,
-		client.FieldOwner("k8s-toolkit-operation"),
+	given, err = setDefaultNamespace(opts, given)
+	if err != nil {
+		return nil, OperationResultNone, err
 	}
-	actual, _ := given.DeepCopyObject().(client.Object)
-	err = opts.Client.Patch(ctx, actual, client.Apply, patchOpts...)
+	gvk, err := gvkForObject(given, opts.Scheme)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to apply")
+		return nil, OperationResultNone, errors.Wrap(err, "extract gvk")
+	}
+	observed := &unstructured.Unstructured{}
+	observed.SetGroupVersionKind(gvk)
+	var previousVersion string
+	start := time.Now()
+	err = opts.Client.Get(ctx, client.ObjectKeyFromObject(given), observed)
+	recordOperation(opts, ActionTypeGet, given, start, err)
+	if err == nil {
+		previousVersion = observed.GetResourceVersion()
+	} else if !apierrors.IsNotFound(err) {
+		return nil, OperationResultNone, errors.Wrap(err, "failed to get")
+	}
+
+	actual, err := Apply(ctx, given, opts)
+	if err != nil {
+		return nil, OperationResultNone, err
+	}
+	switch previousVersion {
+	case "":
+		if err := registerForGarbageCollection(opts, actual); err != nil {
+			return actual, OperationResultCreated, err
+		}
+		return actual, OperationResultCreated, nil
+	case actual.GetResourceVersion():
+		return actual, OperationResultNone, nil
+	default:
+		return actual, OperationResultUpdatedResourceOnly, nil
 	}
-	return actual, nil
 }
 
-func ApplyAll(ctx context.Context, given []client.Object, options ...RunOption) ([]client.Object, error) {
-	return InvokeOperationForAllObjects(ctx, Apply, given, options...)
+func ServerSideUpsert(ctx context.Context, given client.Object, options ...RunOption) (client.Object, error) {
+	actual, _, err := ServerSideUpsertVerbose(ctx, given, options...)
+	return actual, err
 }
 
-func ApplyAllYAMLs(ctx context.Context, filePaths []string, options ...RunOption) ([]client.Object, error) {
-	return InvokeOperationForAllYAMLs(ctx, Apply, filePaths, options...)
+func ServerSideUpsertAll(ctx context.Context, given []client.Object, options ...RunOption) ([]client.Object, error) {
+	return invokeOperationForAllObjects(ctx, ServerSideUpsertVerbose, given, options...)
 }
 
-func ApplyYAML(ctx context.Context, filePath string, options ...RunOption) (kObj client.Object, err error) {
-	return InvokeOperationForYAML(ctx, Apply, filePath, options...)
+// ApplyWithPrevious applies the provided object & returns the state of
+// the object as observed before the apply. A nil previous object implies
+// the object did not exist before the apply. The previous state can be
+// passed to Restore to undo this apply.
+func ApplyWithPrevious(ctx context.Context, given client.Object, options ...RunOption) (applied client.Object, previous client.Object, err error) {
+	previous, err = Get(ctx, given, options...)
+	if err != nil {
+		if !apierrors.IsNotFound(errors.Cause(err)) {
+			return nil, nil, err
+		}
+		previous = nil
+	}
+	applied, err = Apply(ctx, given, options...)
+	if err != nil {
+		return nil, previous, err
+	}
+	return applied, previous, nil
+}
+
+// Restore reverts the provided applied object to the provided previous
+// state. The applied object is deleted if there was no previous state.
+// Otherwise the previous state is upserted.
+//
+// Note: Restore is best effort since fields that were added by the apply
+// & are absent in the previous state are retained
+func Restore(ctx context.Context, applied, previous client.Object, options ...RunOption) error {
+	if previous == nil {
+		err := Delete(ctx, applied, options...)
+		if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+			return errors.Wrap(err, "failed to restore")
+		}
+		return nil
+	}
+	_, err := Upsert(ctx, previous, options...)
+	return errors.Wrap(err, "failed to restore")
 }
 
 // DryRun executes a ServerSideApply DryRun invocation
@@ -494,6 +1502,10 @@ func DryRun(ctx context.Context, given client.Object, options ...RunOption) (cli
 	if given == nil {
 		return nil, errors.New("nil object")
 	}
+	given, err = setDefaultNamespace(opts, given)
+	if err != nil {
+		return nil, err
+	}
 	kind, version, err := GetKindVersionForObject(given, opts.Scheme)
 	if err != nil {
 		return nil, err
@@ -514,9 +1526,11 @@ func DryRun(ctx context.Context, given client.Object, options ...RunOption) (cli
 	patchOpts := []client.PatchOption{
 		client.DryRunAll,
 		client.ForceOwnership,
-		client.FieldOwner("k8s-toolkit-ops"),
+		client.FieldOwner(fieldOwnerOrDefault(opts)),
 	}
+	start := time.Now()
 	err = opts.Client.Patch(ctx, dryRunObj, client.Apply, patchOpts...)
+	recordOperation(opts, ActionTypeDryRun, given, start, err)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to dry run")
 	}
@@ -549,23 +1563,107 @@ func DryRunYAML(ctx context.Context, filePath string, options ...RunOption) (kOb
 // - Object states comparison is a server side implementation i.e. Kubernetes
 // APIs are invoked to determine the comparison result
 func HasDrifted(ctx context.Context, given client.Object, options ...RunOption) (isDrift bool, drift string, err error) {
-	observedObj, err := Get(ctx, given)
+	observedObj, err := Get(ctx, given, options...)
+	if err != nil {
+		return false, "", err
+	}
+	return hasDriftedFrom(ctx, observedObj, given, options...)
+}
+
+// hasDriftedFrom returns true if the provided observed object differs
+// from the result of a dry run of the given object. The difference is
+// returned as the drift.
+func hasDriftedFrom(ctx context.Context, observedObj, given client.Object, options ...RunOption) (isDrift bool, drift string, err error) {
+	driftedObj, err := DryRun(ctx, given, options...)
 	if err != nil {
 		return false, "", err
 	}
 
-	driftedObj, err := DryRun(ctx, given)
+	opts, err := makeRunOptionsWithBase(options...)
+	if err != nil {
+		return false, "", err
+	}
+	observedComparable, driftedComparable, err := ToComparableObjects(observedObj, driftedObj, opts.IgnorePaths...)
 	if err != nil {
 		return false, "", err
 	}
+	isEqual := equality.Semantic.DeepEqual(observedComparable, driftedComparable)
+	return !isEqual, newRedactor(opts).diff(observedComparable, driftedComparable, observedObj, given), nil
+}
+
+// DriftPatch returns the JSON merge patch that reconciles the object
+// observed in the cluster with the given object. An empty patch i.e. `{}`
+// is returned if there is no drift.
+//
+// Note:
+// - Patch is computed from the observed state to the merged state as
+// derived by ToComparableObjects
+// - Returned patch can be applied manually e.g. via kubectl patch --type=merge
+func DriftPatch(ctx context.Context, given client.Object, options ...RunOption) ([]byte, error) {
+	observed, err := Get(ctx, given, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := makeRunOptionsWithBase(options...)
+	if err != nil {
+		return nil, err
+	}
+	observedObj, mergedObj, err := ToComparableObjects(observed, given, opts.IgnorePaths...)
+	if err != nil {
+		return nil, err
+	}
 
-	isEqual, diff, err := IsEqualWithDiffOutput(observedObj, driftedObj)
-	return !isEqual, diff, err
+	patch, err := client.MergeFrom(observedObj).Data(mergedObj)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute drift patch")
+	}
+	return patch, nil
 }
 
 type AssertOptions struct {
 	AssertType     AssertType
 	CustomAssertFn func(actual, expected client.Object) (result bool, diff string, err error)
+
+	// ComparePaths when set scopes the IsEquals & IsNotEquals assertions
+	// to the provided dotted field paths e.g. status.readyReplicas
+	ComparePaths []string
+
+	// NormalizeDefaults when true runs the expected object through the
+	// defaulters of the scheme before the comparison.
+	//
+	// Note: The client-go scheme does not register any defaulters. These
+	// need to be registered via the scheme's AddTypeDefaultingFunc.
+	NormalizeDefaults bool
+}
+
+// withDefaults returns a copy of the provided object with the defaults
+// of the provided scheme applied. An unstructured object is defaulted
+// via its typed counterpart if its kind is registered in the scheme.
+func withDefaults(rscheme *runtime.Scheme, obj client.Object) (client.Object, error) {
+	copied, _ := obj.DeepCopyObject().(client.Object)
+	un, isUnstructured := copied.(*unstructured.Unstructured)
+	if !isUnstructured {
+		rscheme.Default(copied)
+		return copied, nil
+	}
+	gvk := un.GroupVersionKind()
+	typed, err := rscheme.New(gvk)
+	if err != nil {
+		// defaults are not known for the kinds missing in the scheme
+		return copied, nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(un.Object, typed); err != nil {
+		return nil, errors.Wrap(err, "convert to typed")
+	}
+	rscheme.Default(typed)
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typed)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert to unstructured")
+	}
+	un.SetUnstructuredContent(content)
+	un.SetGroupVersionKind(gvk)
+	return un, nil
 }
 
 // Assert returns true if assertion matches the expectation
@@ -574,16 +1672,50 @@ type AssertOptions struct {
 // desired states
 func Assert(ctx context.Context, expected client.Object, assertOptions AssertOptions, options ...RunOption) (result bool, diff string, err error) {
 	actual, err := Get(ctx, expected, options...)
+	isFoundCheck := assertOptions.AssertType == AssertTypeIsFound || assertOptions.AssertType == AssertTypeIsNotFound
+	if isFoundCheck && apierrors.IsNotFound(errors.Cause(err)) {
+		// not found is an assertion outcome & not an error
+		actual, err = nil, nil
+	}
 	if err != nil {
 		return
 	}
 
+	if assertOptions.NormalizeDefaults && expected != nil {
+		opts, err := makeRunOptions(options...)
+		if err != nil {
+			return false, "", err
+		}
+		expected, err = withDefaults(opts.Scheme, expected)
+		if err != nil {
+			return false, "", errors.Wrap(err, "normalize defaults")
+		}
+	}
+
+	opts, err := makeRunOptionsWithBase(options...)
+	if err != nil {
+		return false, "", err
+	}
+	redact := newRedactor(opts)
+	isEqualWithDiffOutputFn := func(observed, desired client.Object) (bool, string, error) {
+		return isEqualWithDiffOutput(observed, desired, redact)
+	}
+	if len(assertOptions.ComparePaths) > 0 {
+		isEqualWithDiffOutputFn = func(observed, desired client.Object) (bool, string, error) {
+			return isEqualAtPaths(observed, desired, redact, assertOptions.ComparePaths...)
+		}
+	}
+
 	switch assertOptions.AssertType {
 	case AssertTypeIsEquals:
-		result, diff, err = IsEqualWithDiffOutput(actual, expected)
+		result, diff, err = isEqualWithDiffOutputFn(actual, expected)
 	case AssertTypeIsNotEquals:
-		result, diff, err = IsEqualWithDiffOutput(actual, expected)
+		result, diff, err = isEqualWithDiffOutputFn(actual, expected)
 		result = !result // invert assert result
+	case AssertTypeIsStrictEquals:
+		result, diff, err = isStrictEqualWithDiffOutput(actual, expected, redact)
+	case AssertTypeIsSupersetOf:
+		result, diff, err = isSupersetOfWithDiffOutput(actual, expected, redact)
 	case AssertTypeIsNotFound:
 		if actual == nil {
 			result = true // assert succeeded
@@ -608,6 +1740,59 @@ func Assert(ctx context.Context, expected client.Object, assertOptions AssertOpt
 	return result, diff, err
 }
 
+// AssertionError is returned when the observed state of an object does
+// not match the expectation of an assertion as opposed to an API error
+type AssertionError struct {
+	AssertType AssertType
+	Key        client.ObjectKey
+	Diff       string
+
+	// verbose when true embeds the diff in the error message
+	verbose bool
+}
+
+// Error implements the error interface
+func (e *AssertionError) Error() string {
+	if !e.verbose || e.Diff == "" {
+		return fmt.Sprintf("assert failed: %s: %s", e.AssertType, e.Key)
+	}
+	return fmt.Sprintf("assert failed: %s: %s: %s", e.AssertType, e.Key, e.Diff)
+}
+
+// IsAssertionError returns true if the provided error is due to an
+// assertion mismatch
+func IsAssertionError(err error) bool {
+	var assertErr *AssertionError
+	return errors.As(err, &assertErr)
+}
+
+// AssertOrError is same as Assert but returns an *AssertionError if the
+// assertion does not match the expectation
+//
+// Note: IsAssertionError can be used to check if the returned error is
+// due to an assertion mismatch
+// Note: RunOptions.VerboseDiff can be set to embed the diff in the
+// error message
+func AssertOrError(ctx context.Context, expected client.Object, assertOptions AssertOptions, options ...RunOption) error {
+	opts, err := makeRunOptionsWithBase(options...)
+	if err != nil {
+		return err
+	}
+	result, diff, err := Assert(ctx, expected, assertOptions, options...)
+	if err != nil {
+		return err
+	}
+	if !result {
+		return &AssertionError{
+			AssertType: assertOptions.AssertType,
+			Key:        client.ObjectKeyFromObject(expected),
+			Diff:       diff,
+			verbose:    opts.VerboseDiff != nil && *opts.VerboseDiff,
+		}
+	}
+	return nil
+}
+
 func AssertEquals(ctx context.Context, expected client.Object, options ...RunOption) (result bool, diff string, err error) {
 	return Assert(ctx, expected, AssertOptions{AssertType: AssertTypeIsEquals}, options...)
 }
@@ -708,6 +1893,21 @@ func ThreeWayLocalMergeWithTwoObjects(observed, desired map[string]interface{})
 	return ThreeWayLocalMerge(observed, runtime.DeepCopyJSON(desired), desired)
 }
 
+// DeepMerge represents a two-way client side merge of the overlay into
+// the base. Maps are merged recursively, lists of maps are merged on the
+// basis of their detected merge key & the overlay wins in case of
+// conflicts. Fields present in base but absent in overlay are retained.
+//
+// Note: Neither base nor overlay is modified
+func DeepMerge(base, overlay map[string]interface{}) (map[string]interface{}, error) {
+	if base == nil {
+		return runtime.DeepCopyJSON(overlay), nil
+	}
+	// Absence of last applied state ensures none of the base fields
+	// get removed
+	return ThreeWayLocalMerge(base, nil, overlay)
+}
+
 // ToComparableObjects merges the provided desired state with the
 // provided observed state to form a merged state. As the function name
 // suggests, this is useful before running DeepEqual check.
@@ -720,7 +1920,9 @@ func ThreeWayLocalMergeWithTwoObjects(observed, desired map[string]interface{})
 // a subset of the observed state.
 // - Merged state takes care of Kubernetes read only system fields by copying
 // them from the observed state into the merged state
-func ToComparableObjects(observed, desired client.Object) (observedObj, mergedObj *unstructured.Unstructured, err error) {
+// - Fields at the provided ignore paths e.g. metadata.annotations["foo"]
+// are removed from both the observed & merged states
+func ToComparableObjects(observed, desired client.Object, ignorePaths ...string) (observedObj, mergedObj *unstructured.Unstructured, err error) {
 	if observed == nil {
 		return nil, nil, errors.New("nil observed")
 	}
@@ -751,6 +1953,15 @@ func ToComparableObjects(observed, desired client.Object) (observedObj, mergedOb
 		return nil, nil, err
 	}
 
+	for _, path := range ignorePaths {
+		fields, err := ParseFieldPath(path)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid ignore path")
+		}
+		unstructured.RemoveNestedField(observedUnstruct, fields...)
+		unstructured.RemoveNestedField(mergedUnstruct, fields...)
+	}
+
 	// var mergedObj, observedObj unstructured.Unstructured
 	observedObj = &unstructured.Unstructured{}
 	mergedObj = &unstructured.Unstructured{}
@@ -806,13 +2017,20 @@ func IsEqualWithMergeOutput(observed, desired client.Object) (bool, *unstructure
 // - Comparison is purely a client side implementation i.e. Kubernetes APIs
 // are not involved in the process
 // - Diff response is formatted as -observed +merged
+// - Values of data & stringData of Secrets are redacted in the diff
 func IsEqualWithDiffOutput(observed, desired client.Object) (bool, string, error) {
+	return isEqualWithDiffOutput(observed, desired, defaultRedactor)
+}
+
+// isEqualWithDiffOutput is same as IsEqualWithDiffOutput but redacts the
+// diff with the provided redactor
+func isEqualWithDiffOutput(observed, desired client.Object, r redactor) (bool, string, error) {
 	observedObj, mergedObj, err := ToComparableObjects(observed, desired)
 	if err != nil {
 		return false, "", err
 	}
 
-	return equality.Semantic.DeepEqual(observedObj, mergedObj), cmp.Diff(observedObj, mergedObj), nil
+	return equality.Semantic.DeepEqual(observedObj, mergedObj), r.diff(observedObj, mergedObj, observed, desired), nil
 }
 
 // IsEqual matches any Kubernetes resource for equality. A match is found
@@ -833,6 +2051,59 @@ func IsEqual(observed, desired client.Object) (bool, error) {
 	return isEqual, nil
 }
 
+// IsEqualAtPaths matches the values found at the provided dotted field
+// paths e.g. spec.replicas of the observed & desired objects. All other
+// fields are ignored. A path that is not found in either of the objects
+// is considered as a match.
+//
+// Note: Diff response is formatted as -observed +desired & is keyed by
+// the provided paths. Values of data & stringData of Secrets are redacted.
+func IsEqualAtPaths(observed, desired client.Object, paths ...string) (bool, string, error) {
+	return isEqualAtPaths(observed, desired, defaultRedactor, paths...)
+}
+
+// isEqualAtPaths is same as IsEqualAtPaths but redacts the diff with the
+// provided redactor
+func isEqualAtPaths(observed, desired client.Object, r redactor, paths ...string) (bool, string, error) {
+	if observed == nil {
+		return false, "", errors.New("nil observed")
+	}
+	if desired == nil {
+		return false, "", errors.New("nil desired")
+	}
+	if len(paths) == 0 {
+		return false, "", errors.New("no field paths provided")
+	}
+	observedContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(observed.DeepCopyObject())
+	if err != nil {
+		return false, "", errors.Wrap(err, "convert observed to unstructured")
+	}
+	desiredContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired.DeepCopyObject())
+	if err != nil {
+		return false, "", errors.Wrap(err, "convert desired to unstructured")
+	}
+
+	var observedValues = make(map[string]interface{}, len(paths))
+	var desiredValues = make(map[string]interface{}, len(paths))
+	for _, path := range paths {
+		fields, err := ParseFieldPath(path)
+		if err != nil {
+			return false, "", err
+		}
+		if value, found, err := unstructured.NestedFieldCopy(observedContent, fields...); err != nil {
+			return false, "", errors.Wrapf(err, "observed: read %q", path)
+		} else if found {
+			observedValues[path] = value
+		}
+		if value, found, err := unstructured.NestedFieldCopy(desiredContent, fields...); err != nil {
+			return false, "", errors.Wrapf(err, "desired: read %q", path)
+		} else if found {
+			desiredValues[path] = value
+		}
+	}
+	return equality.Semantic.DeepEqual(observedValues, desiredValues), r.diffAtPaths(observedValues, desiredValues, observed, desired), nil
+}
+
 // IsEqualOrDie executes IsEqual with an additional task of suspending
 // the observed thread in case of any runtime error
 func IsEqualOrDie(observed, desired client.Object) bool {
@@ -842,3 +2113,98 @@ func IsEqualOrDie(observed, desired client.Object) bool {
 	}
 	return b
 }
+
+// toStrictComparable returns the unstructured content of the provided
+// object without null entries, type meta, status & read only system
+// fields of metadata
+func toStrictComparable(obj client.Object) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
+	if err != nil {
+		return nil, errors.Wrap(err, "convert to unstructured")
+	}
+	content, err = DeleteNullInUnstructuredMap(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "remove null")
+	}
+	for _, fieldName := range objectMetaSystemFields {
+		unstructured.RemoveNestedField(content, "metadata", fieldName)
+	}
+	delete(content, "apiVersion")
+	delete(content, "kind")
+	delete(content, "status")
+	return content, nil
+}
+
+// IsStrictEqualWithDiffOutput returns true if the observed object is
+// exactly same as the desired object. Unlike IsEqual, fields that are
+// absent in the desired object but present in the observed object result
+// in a mismatch.
+//
+// Note:
+// - Type meta, status & read only system fields of metadata are not compared
+// - Fields defaulted by Kubernetes are present in the observed object
+// - Diff response is formatted as -observed +desired
+// - Values of data & stringData of Secrets are redacted in the diff
+func IsStrictEqualWithDiffOutput(observed, desired client.Object) (bool, string, error) {
+	return isStrictEqualWithDiffOutput(observed, desired, defaultRedactor)
+}
+
+// isStrictEqualWithDiffOutput is same as IsStrictEqualWithDiffOutput but
+// redacts the diff with the provided redactor
+func isStrictEqualWithDiffOutput(observed, desired client.Object, r redactor) (bool, string, error) {
+	if observed == nil {
+		return false, "", errors.New("nil observed")
+	}
+	if desired == nil {
+		return false, "", errors.New("nil desired")
+	}
+	observedContent, err := toStrictComparable(observed)
+	if err != nil {
+		return false, "", errors.Wrap(err, "observed")
+	}
+	desiredContent, err := toStrictComparable(desired)
+	if err != nil {
+		return false, "", errors.Wrap(err, "desired")
+	}
+	return equality.Semantic.DeepEqual(observedContent, desiredContent), r.diff(observedContent, desiredContent, observed, desired), nil
+}
+
+// IsSupersetOfWithDiffOutput returns true if the desired object is a
+// superset of the observed object i.e. every field of the observed
+// object is present in the desired object with the same value. This is
+// the reverse of IsEqual.
+//
+// Note:
+// - Type meta, status & read only system fields of metadata are not compared
+// - Lists without a merge key are compared as a whole
+// - Diff response is formatted as -desired +merged
+// - Values of data & stringData of Secrets are redacted in the diff
+func IsSupersetOfWithDiffOutput(observed, desired client.Object) (bool, string, error) {
+	return isSupersetOfWithDiffOutput(observed, desired, defaultRedactor)
+}
+
+// isSupersetOfWithDiffOutput is same as IsSupersetOfWithDiffOutput but
+// redacts the diff with the provided redactor
+func isSupersetOfWithDiffOutput(observed, desired client.Object, r redactor) (bool, string, error) {
+	if observed == nil {
+		return false, "", errors.New("nil observed")
+	}
+	if desired == nil {
+		return false, "", errors.New("nil desired")
+	}
+	observedContent, err := toStrictComparable(observed)
+	if err != nil {
+		return false, "", errors.Wrap(err, "observed")
+	}
+	desiredContent, err := toStrictComparable(desired)
+	if err != nil {
+		return false, "", errors.Wrap(err, "desired")
+	}
+	// merging the observed into the desired changes the desired only
+	// if the observed has fields that are absent in the desired
+	mergedContent, err := DeepMerge(desiredContent, observedContent)
+	if err != nil {
+		return false, "", err
+	}
+	return equality.Semantic.DeepEqual(desiredContent, mergedContent), r.diff(desiredContent, mergedContent, observed, desired), nil
+}
@@ -6,10 +6,13 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/simplekube/kit/pkg/pointer"
+
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -300,3 +303,287 @@ func TestApplyWithDriftChecks(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyAllWithOutcomes(t *testing.T) {
+	t.Parallel()
+
+	var cmName = fmt.Sprintf("test-apply-outcomes-%d", rand.Int31())
+	var given = []client.Object{
+		&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cmName + "-1",
+				Namespace: "default",
+			},
+		},
+		&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cmName + "-2",
+				Namespace: "none", // namespace does not exist
+			},
+		},
+		&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cmName + "-3",
+				Namespace: "default",
+			},
+		},
+	}
+
+	outcomes, err := ApplyAllWithOutcomes(context.Background(), given)
+	assert.Error(t, err)
+	assert.Len(t, outcomes, len(given))
+	for idx, outcome := range outcomes {
+		assert.Same(t, given[idx], outcome.Input)
+	}
+	assert.NoError(t, outcomes[0].Err)
+	assert.Equal(t, cmName+"-1", outcomes[0].Output.GetName())
+	assert.Error(t, outcomes[1].Err)
+	assert.Nil(t, outcomes[1].Output)
+	assert.NoError(t, outcomes[2].Err)
+	assert.Equal(t, cmName+"-3", outcomes[2].Output.GetName())
+}
+
+func TestApplyWithPreviousAndRestore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("test-apply-previous-%d", rand.Int31()),
+			Namespace: "default",
+		},
+		Data: map[string]string{"version": "v1"},
+	}
+
+	// first apply has no previous state
+	applied, previous, err := ApplyWithPrevious(ctx, cm)
+	assert.NoError(t, err)
+	assert.Nil(t, previous)
+
+	updated := cm.DeepCopy()
+	updated.Data = map[string]string{"version": "v2"}
+	_, previous, err = ApplyWithPrevious(ctx, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", previous.(*corev1.ConfigMap).Data["version"])
+
+	// restore to the first apply
+	err = Restore(ctx, updated, previous)
+	assert.NoError(t, err)
+	got, err := Get(ctx, cm)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", got.(*corev1.ConfigMap).Data["version"])
+
+	// restore to the state before the first apply
+	err = Restore(ctx, applied, nil)
+	assert.NoError(t, err)
+	result, _, err := AssertIsNotFound(ctx, cm)
+	assert.NoError(t, err)
+	assert.True(t, result)
+}
+
+func TestApplyWithOptionRequireNamespaceExists(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	nsName := fmt.Sprintf("test-require-ns-%d", rand.Int31())
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "require-ns",
+			Namespace: nsName,
+		},
+	}
+	opts := &RunOptions{RequireNamespaceExists: pointer.Bool(true)}
+
+	_, err := Apply(ctx, cm, opts)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("namespace %q not found", nsName))
+
+	_, err = Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: nsName}}, opts)
+	assert.NoError(t, err)
+	_, err = Apply(ctx, cm, opts)
+	assert.NoError(t, err)
+}
+
+func TestApplyWithFieldOwner(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("test-apply-field-owner-%d", rand.Int31()),
+			Namespace: "default",
+		},
+		Data: map[string]string{"color": "red"},
+	}
+
+	var scenarios = []struct {
+		name      string
+		operation func(context.Context, client.Object, ...RunOption) (client.Object, error)
+		options   []RunOption
+		expected  string
+	}{
+		{
+			name:      "should use the default field owner",
+			operation: Apply,
+			expected:  defaultFieldOwner,
+		},
+		{
+			name:      "should use the provided field owner",
+			operation: Apply,
+			options:   []RunOption{WithFieldOwner("ci-job-42")},
+			expected:  "ci-job-42",
+		},
+		{
+			name:      "should use the default field owner for dry run",
+			operation: DryRun,
+			expected:  defaultFieldOwner,
+		},
+		{
+			name:      "should use the provided field owner for dry run",
+			operation: DryRun,
+			options:   []RunOption{WithFieldOwner("ci-job-43")},
+			expected:  "ci-job-43",
+		},
+	}
+
+	// scenarios are run in order since they apply the same object
+	for _, scenario := range scenarios {
+		got, err := scenario.operation(ctx, cm, scenario.options...)
+		assert.NoError(t, err, scenario.name)
+
+		var managers []string
+		for _, entry := range got.GetManagedFields() {
+			managers = append(managers, entry.Manager)
+		}
+		assert.Contains(t, managers, scenario.expected, scenario.name)
+	}
+}
+
+// TestServerSideUpsertVerbose compares the results of server side upsert
+// with client side upsert against the same object
+//
+// Note: All the scenarios should be run in a serial order
+func TestServerSideUpsertVerbose(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	name := fmt.Sprintf("test-server-side-upsert-%d", rand.Int31())
+	configMap := func(version string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Data: map[string]string{"version": version},
+		}
+	}
+	defer func() {
+		if err := Delete(ctx, configMap("")); err != nil {
+			t.Logf("teardown configmap: %s: %v", name, err)
+		}
+	}()
+
+	var scenarios = []struct {
+		name           string
+		operation      invokeVerboseFn
+		given          client.Object
+		expectedResult OperationResult
+	}{
+		{
+			name:           "should create via server side upsert",
+			operation:      ServerSideUpsertVerbose,
+			given:          configMap("v1"),
+			expectedResult: OperationResultCreated,
+		},
+		{
+			name:           "should not change via server side upsert of same state",
+			operation:      ServerSideUpsertVerbose,
+			given:          configMap("v1"),
+			expectedResult: OperationResultNone,
+		},
+		{
+			name:           "should not change via client side upsert of same state",
+			operation:      UpsertVerbose,
+			given:          configMap("v1"),
+			expectedResult: OperationResultNone,
+		},
+		{
+			name:           "should update via server side upsert",
+			operation:      ServerSideUpsertVerbose,
+			given:          configMap("v2"),
+			expectedResult: OperationResultUpdatedResourceOnly,
+		},
+		{
+			name:           "should update via client side upsert",
+			operation:      UpsertVerbose,
+			given:          configMap("v3"),
+			expectedResult: OperationResultUpdatedResourceOnly,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		_, result, err := scenario.operation(ctx, scenario.given)
+		assert.NoError(t, err, scenario.name)
+		assert.Equal(t, scenario.expectedResult, result, scenario.name)
+	}
+
+	got, err := Get(ctx, configMap(""))
+	assert.NoError(t, err)
+	assert.Equal(t, "v3", got.(*corev1.ConfigMap).Data["version"])
+}
+
+func TestApplyDirectorySorted(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	// the custom resource is found before its CRD in the directory
+	applied, err := ApplyDirectorySorted(ctx, "testdata/crd_and_cr")
+	assert.NoError(t, err)
+	if assert.Len(t, applied, 2) {
+		assert.Equal(t, "CustomResourceDefinition", applied[0].GetObjectKind().GroupVersionKind().Kind)
+		assert.Equal(t, "Widget", applied[1].GetObjectKind().GroupVersionKind().Kind)
+	}
+	defer func() {
+		for idx := len(applied) - 1; idx >= 0; idx-- {
+			if delErr := Delete(ctx, applied[idx]); delErr != nil {
+				t.Logf("teardown: %v", delErr)
+			}
+		}
+	}()
+
+	widget := &unstructured.Unstructured{}
+	widget.SetAPIVersion("test.simplekube.io/v1")
+	widget.SetKind("Widget")
+	widget.SetNamespace("default")
+	widget.SetName("sorted")
+	_, err = Get(ctx, widget)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,327 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/simplekube/kit/pkg/pointer"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDescribeDeletionBlockers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       fmt.Sprintf("test-deletion-blockers-%d", rand.Int31()),
+			Namespace:  "default",
+			Finalizers: []string{"protect.io/testing"},
+		},
+	}
+	_, err := Create(ctx, cm)
+	assert.NoError(t, err)
+	defer func() {
+		cm.SetFinalizers(nil)
+		if _, updateErr := Upsert(ctx, cm, &RunOptions{SetFinalizersToNullDuringUpsert: pointer.Bool(true)}); updateErr != nil {
+			t.Logf("teardown configmap: remove finalizers: %s %s: %v", cm.Namespace, cm.Name, updateErr)
+		}
+	}()
+
+	err = Delete(ctx, cm)
+	assert.NoError(t, err)
+
+	got, err := DescribeDeletionBlockers(ctx, cm)
+	assert.NoError(t, err)
+	assert.Contains(t, got, "protect.io/testing")
+	assert.NotContains(t, got, `deletionTimestamp=""`)
+}
+
+// failingDeleteClient fails the Delete calls with the provided error
+type failingDeleteClient struct {
+	client.Client
+	err error
+}
+
+func (c *failingDeleteClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.err
+}
+
+func TestDeleteWithOptionDiagnoseDeleteFailures(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := metav1.NewTime(time.Date(2021, 11, 2, 10, 0, 0, 0, time.UTC))
+	stuck := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "stuck",
+			Namespace:         "default",
+			DeletionTimestamp: &deletedAt,
+			Finalizers:        []string{"protect.io/testing"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "web-uid"},
+			},
+		},
+	}
+	deleteErr := errors.New("admission webhook denied the request")
+
+	var scenarios = []struct {
+		name        string
+		given       client.Object
+		diagnose    *bool
+		errContains []string
+		errExact    string
+	}{
+		{
+			name:     "should return the delete error as-is when not diagnosed",
+			given:    stuck,
+			errExact: deleteErr.Error(),
+		},
+		{
+			name:     "should return the delete error as-is when diagnosis is disabled",
+			given:    stuck,
+			diagnose: pointer.Bool(false),
+			errExact: deleteErr.Error(),
+		},
+		{
+			name:     "should add the deletion blockers to the delete error",
+			given:    stuck,
+			diagnose: pointer.Bool(true),
+			errContains: []string{
+				deleteErr.Error(),
+				fmt.Sprintf("deletionTimestamp=%q", deletedAt.String()),
+				`finalizers=["protect.io/testing"]`,
+				`ownerReferences=["Deployment/web"]`,
+			},
+		},
+		{
+			name: "should add the diagnosis error when the object is not found",
+			given: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "default"},
+			},
+			diagnose:    pointer.Bool(true),
+			errContains: []string{deleteErr.Error(), "diagnose:", "not found"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := &failingDeleteClient{
+				Client: fake.NewClientBuilder().WithObjects(stuck.DeepCopy()).Build(),
+				err:    deleteErr,
+			}
+			err := Delete(
+				context.Background(),
+				scenario.given,
+				&RunOptions{Client: cli, DiagnoseDeleteFailures: scenario.diagnose},
+			)
+			assert.Error(t, err)
+			assert.ErrorIs(t, err, deleteErr)
+			if scenario.errExact != "" {
+				assert.Equal(t, scenario.errExact, err.Error())
+			}
+			for _, want := range scenario.errContains {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestForceDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       fmt.Sprintf("test-force-delete-%d", rand.Int31()),
+			Namespace:  "default",
+			Finalizers: []string{"protect.io/testing"},
+		},
+	}
+	_, err := Create(ctx, cm)
+	assert.NoError(t, err)
+
+	// object is stuck in terminating state due to its finalizer
+	err = Delete(ctx, cm)
+	assert.NoError(t, err)
+	_, err = Get(ctx, cm)
+	assert.NoError(t, err)
+
+	err = ForceDelete(ctx, cm)
+	assert.NoError(t, err)
+	_, err = Get(ctx, cm)
+	assert.True(t, apierrors.IsNotFound(errors.Cause(err)))
+
+	// deleting an object that is not found is not an error
+	err = ForceDelete(ctx, cm)
+	assert.NoError(t, err)
+}
+
+func TestDeleteAllWithReport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("test-delete-report-%d", rand.Int31()),
+			Namespace: "default",
+		},
+	}
+	missing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("test-delete-report-missing-%d", rand.Int31()),
+			Namespace: "default",
+		},
+	}
+	_, err := Create(ctx, existing)
+	assert.NoError(t, err)
+
+	report, err := DeleteAllWithReport(ctx, []client.Object{existing, missing})
+	assert.NoError(t, err)
+	assert.Len(t, report.Outcomes, 2)
+	assert.True(t, report.Outcomes[0].Deleted)
+	assert.NoError(t, report.Outcomes[0].Err)
+	assert.False(t, report.Outcomes[1].Deleted)
+	assert.NoError(t, report.Outcomes[1].Err)
+}
+
+// deleteOptionsRecorder records the options of the last Delete call
+type deleteOptionsRecorder struct {
+	client.Client
+	got client.DeleteOptions
+}
+
+func (c *deleteOptionsRecorder) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.got = client.DeleteOptions{}
+	c.got.ApplyOptions(opts)
+	return nil
+}
+
+func TestDeleteWithOptionDeleteOptions(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "delete-options",
+			Namespace: "default",
+		},
+	}
+	foreground := metav1.DeletePropagationForeground
+
+	var scenarios = []struct {
+		name              string
+		operation         func(ctx context.Context, given client.Object, options ...RunOption) error
+		deleteOptions     []client.DeleteOption
+		expectGracePeriod *int64
+		expectPropagation *metav1.DeletionPropagation
+	}{
+		{
+			name:      "should delete with defaults",
+			operation: Delete,
+		},
+		{
+			name:              "should pass the propagation policy",
+			operation:         Delete,
+			deleteOptions:     []client.DeleteOption{client.PropagationPolicy(foreground)},
+			expectPropagation: &foreground,
+		},
+		{
+			name:              "should force delete with zero grace period",
+			operation:         ForceDelete,
+			expectGracePeriod: pointer.Int64(0),
+		},
+		{
+			name:              "should let caller override grace period of force delete",
+			operation:         ForceDelete,
+			deleteOptions:     []client.DeleteOption{client.GracePeriodSeconds(30)},
+			expectGracePeriod: pointer.Int64(30),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := &deleteOptionsRecorder{
+				Client: fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build(),
+			}
+			err := scenario.operation(
+				context.Background(),
+				cm,
+				&RunOptions{Client: cli, DeleteOptions: scenario.deleteOptions},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectGracePeriod, cli.got.GracePeriodSeconds)
+			assert.Equal(t, scenario.expectPropagation, cli.got.PropagationPolicy)
+		})
+	}
+}
+
+// deleteOrderRecorder records the kinds of the deleted objects along
+// with the options of the last Delete call
+type deleteOrderRecorder struct {
+	client.Client
+	kinds []string
+	got   client.DeleteOptions
+}
+
+func (c *deleteOrderRecorder) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.kinds = append(c.kinds, obj.GetObjectKind().GroupVersionKind().Kind)
+	c.got = client.DeleteOptions{}
+	c.got.ApplyOptions(opts)
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestDeleteDirectorySortedDeletesInReverseOrder(t *testing.T) {
+	t.Parallel()
+
+	foreground := metav1.DeletePropagationForeground
+	cli := &deleteOrderRecorder{
+		Client: fake.NewClientBuilder().WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "sorted"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "sorted", Namespace: "sorted"}},
+		).Build(),
+	}
+	err := DeleteDirectorySorted(
+		context.Background(),
+		"testdata/namespace_and_configmap",
+		WithClient(cli),
+		WithPropagationPolicy(foreground),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ConfigMap", "Namespace"}, cli.kinds)
+	assert.Equal(t, &foreground, cli.got.PropagationPolicy)
+
+	// deleting objects that are not found is not an error
+	err = DeleteDirectorySorted(context.Background(), "testdata/namespace_and_configmap", WithClient(cli))
+	assert.NoError(t, err)
+}
+
+func TestDeleteDirectorySorted(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	applied, err := ApplyDirectorySorted(ctx, "testdata/gadget_crd_and_cr")
+	assert.NoError(t, err)
+	assert.Len(t, applied, 2)
+
+	err = DeleteDirectorySorted(ctx, "testdata/gadget_crd_and_cr")
+	assert.NoError(t, err)
+	for _, obj := range applied {
+		_, err = Get(ctx, obj)
+		assert.True(t, apierrors.IsNotFound(errors.Cause(err)), "expected not found: %v", err)
+	}
+}
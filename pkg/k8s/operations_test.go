@@ -2,16 +2,28 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/simplekube/kit/pkg/pointer"
+
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestGetKindVersionForObject(t *testing.T) {
@@ -459,3 +471,990 @@ func TestObjectEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestDriftPatch(t *testing.T) {
+	t.Parallel()
+
+	var nsName = fmt.Sprintf("test-drift-patch-%d", rand.Int31())
+	var ns = &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Namespace",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nsName,
+		},
+	}
+	_, err := Create(context.Background(), ns)
+	assert.NoError(t, err)
+
+	var scenarios = []struct {
+		name     string
+		resource client.Object
+		expected string
+	}{
+		{
+			name:     "should return an empty patch when local state matches the cluster state",
+			resource: ns.DeepCopy(),
+			expected: `{}`,
+		},
+		{
+			name: "should return a patch with the label that is missing in the cluster state",
+			resource: &corev1.Namespace{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Namespace",
+					APIVersion: "v1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: nsName,
+					Labels: map[string]string{
+						"dummy": "testing",
+					},
+				},
+			},
+			expected: `{"metadata":{"labels":{"dummy":"testing"}}}`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			patch, err := DriftPatch(context.Background(), scenario.resource)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, string(patch))
+		})
+	}
+}
+
+func TestDeepMerge(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name     string
+		base     map[string]interface{}
+		overlay  map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:    "should return overlay when base is nil",
+			overlay: map[string]interface{}{"kind": "Pod"},
+			expected: map[string]interface{}{
+				"kind": "Pod",
+			},
+		},
+		{
+			name: "should merge maps recursively with overlay winning",
+			base: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "base",
+					"labels": map[string]interface{}{"app": "base", "tier": "web"},
+				},
+			},
+			overlay: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "overlay"},
+				},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "base",
+					"labels": map[string]interface{}{"app": "overlay", "tier": "web"},
+				},
+			},
+		},
+		{
+			name: "should merge list maps by detected key",
+			base: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "app", "image": "app:v1", "tty": true},
+					map[string]interface{}{"name": "sidecar", "image": "sidecar:v1"},
+				},
+			},
+			overlay: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "app", "image": "app:v2"},
+				},
+			},
+			expected: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "app", "image": "app:v2", "tty": true},
+					map[string]interface{}{"name": "sidecar", "image": "sidecar:v1"},
+				},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DeepMerge(scenario.base, scenario.overlay)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expected, got)
+		})
+	}
+}
+
+func TestListTyped(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	nsName := fmt.Sprintf("test-list-typed-%d", rand.Int31())
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nsName,
+		},
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "list-typed",
+			Namespace: nsName,
+		},
+	}
+	_, err := CreateAll(ctx, []client.Object{ns, cm})
+	assert.NoError(t, err)
+
+	got, err := ListTyped(ctx, &corev1.ConfigMapList{}, &RunOptions{
+		ListOptions: []client.ListOption{client.InNamespace(nsName)},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, got.Items, 1)
+	assert.Equal(t, "list-typed", got.Items[0].Name)
+
+	_, err = ListTyped(ctx, (*corev1.ConfigMapList)(nil))
+	assert.Error(t, err)
+}
+
+func TestPatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("test-patch-%d", rand.Int31()),
+			Namespace: "default",
+		},
+		Data: map[string]string{"color": "red", "size": "large"},
+	}
+	_, err := Create(ctx, cm)
+	assert.NoError(t, err)
+
+	var scenarios = []struct {
+		name      string
+		operation InvokeFn
+		given     client.Object
+		expected  map[string]string
+	}{
+		{
+			name:      "should merge patch",
+			operation: MergePatch,
+			given: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cm.Name, Namespace: cm.Namespace},
+				Data:       map[string]string{"color": "blue"},
+			},
+			expected: map[string]string{"color": "blue", "size": "large"},
+		},
+		{
+			name:      "should strategic merge patch",
+			operation: StrategicMergePatch,
+			given: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cm.Name, Namespace: cm.Namespace},
+				Data:       map[string]string{"weight": "light"},
+			},
+			expected: map[string]string{"color": "blue", "size": "large", "weight": "light"},
+		},
+		{
+			name:      "should json patch",
+			operation: JSONPatch([]byte(`[{"op": "remove", "path": "/data/size"}]`)),
+			given:     cm,
+			expected:  map[string]string{"color": "blue", "weight": "light"},
+		},
+	}
+
+	// scenarios are run in order since each one builds on the previous
+	for _, scenario := range scenarios {
+		got, err := scenario.operation(ctx, scenario.given)
+		assert.NoError(t, err, scenario.name)
+		assert.Equal(t, scenario.expected, got.(*corev1.ConfigMap).Data, scenario.name)
+	}
+}
+
+func TestParseGVK(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name     string
+		gvk      string
+		expected schema.GroupVersionKind
+		isError  bool
+	}{
+		{
+			name:     "should parse group version kind",
+			gvk:      "apps/v1/Deployment",
+			expected: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		},
+		{
+			name:     "should parse core version kind",
+			gvk:      "v1/ConfigMap",
+			expected: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+		},
+		{
+			name:    "should fail when kind is missing",
+			gvk:     "apps/v1/",
+			isError: true,
+		},
+		{
+			name:    "should fail when version is missing",
+			gvk:     "ConfigMap",
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseGVK(scenario.gvk)
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expected, got)
+			}
+		})
+	}
+}
+
+func TestListForGVK(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	nsName := fmt.Sprintf("test-list-gvk-%d", rand.Int31())
+	_, err := CreateAll(ctx, []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: nsName}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "list-gvk", Namespace: nsName}},
+	})
+	assert.NoError(t, err)
+
+	got, err := ListForGVK(ctx, "v1/ConfigMap", &RunOptions{
+		ListOptions: []client.ListOption{client.InNamespace(nsName)},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, got.Items, 1)
+	assert.Equal(t, "list-gvk", got.Items[0].GetName())
+}
+
+func TestUpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("test-update-status-%d", rand.Int31()),
+		},
+	}
+	created, err := Create(ctx, ns)
+	assert.NoError(t, err)
+
+	withStatus := created.(*corev1.Namespace).DeepCopy()
+	withStatus.Status.Conditions = []corev1.NamespaceCondition{
+		{Type: "Simulated", Status: corev1.ConditionTrue},
+	}
+	updated, err := UpdateStatus(ctx, withStatus)
+	assert.NoError(t, err)
+	assert.Len(t, updated.(*corev1.Namespace).Status.Conditions, 1)
+
+	got, err := GetStatus(ctx, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.NamespaceConditionType("Simulated"), got.(*corev1.Namespace).Status.Conditions[0].Type)
+}
+
+// statusServer serves the status subresource of the namespaced objects
+// of the provided kind found in the provided client. Requests other than
+// the get of the status subresource are not found.
+func statusServer(cli client.Client, gvk schema.GroupVersionKind) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// path is of the form /<api>/namespaces/<ns>/<resource>/<name>/status
+		_, path, _ := strings.Cut(r.URL.Path, "/namespaces/")
+		segments := strings.Split(path, "/")
+		if len(segments) != 4 || segments[3] != "status" || r.Method != http.MethodGet {
+			http.NotFound(w, r)
+			return
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		if err := cli.Get(r.Context(), client.ObjectKey{Namespace: segments[0], Name: segments[2]}, obj); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(obj)
+	}))
+}
+
+func TestGetStatus(t *testing.T) {
+	t.Parallel()
+
+	deployGVK := appsv1.SchemeGroupVersion.WithKind("Deployment")
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(deployGVK, meta.RESTScopeNamespace)
+	cli := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "observed", Namespace: "default"},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 2},
+	}).Build()
+	srv := statusServer(cli, deployGVK)
+	t.Cleanup(srv.Close)
+	opts := &RunOptions{Client: cli, RestConfig: &rest.Config{Host: srv.URL}}
+
+	unstruct := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(deployGVK)
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		return obj
+	}
+
+	var scenarios = []struct {
+		name          string
+		given         client.Object
+		readyReplicas func(obj client.Object) int64
+		isError       bool
+	}{
+		{
+			name:  "should populate the status of the provided object",
+			given: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "observed", Namespace: "default"}},
+			readyReplicas: func(obj client.Object) int64 {
+				return int64(obj.(*appsv1.Deployment).Status.ReadyReplicas)
+			},
+		},
+		{
+			name:  "should populate the status of the provided unstructured object",
+			given: unstruct("observed"),
+			readyReplicas: func(obj client.Object) int64 {
+				got, _, _ := unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object, "status", "readyReplicas")
+				return got
+			},
+		},
+		{
+			name:    "should error when the object is not found",
+			given:   unstruct("missing"),
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := GetStatus(context.Background(), scenario.given, opts)
+			if scenario.isError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "failed to get status")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Same(t, scenario.given, got, "should populate the provided object")
+			assert.Equal(t, int64(2), scenario.readyReplicas(scenario.given))
+		})
+	}
+}
+
+// recordingClient counts the Get & Patch calls made against it. Patch
+// calls are not forwarded since the fake client does not support
+// server side apply.
+type recordingClient struct {
+	client.Client
+	gets    int
+	patches int
+}
+
+func (c *recordingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	c.gets++
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *recordingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.patches++
+	return nil
+}
+
+func TestHasDriftedUsesProvidedOptions(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "has-drifted",
+			Namespace: "default",
+		},
+		Data: map[string]string{"color": "red"},
+	}
+	cli := &recordingClient{
+		Client: fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build(),
+	}
+
+	isDrift, _, err := HasDrifted(context.Background(), cm, &RunOptions{Client: cli})
+	assert.NoError(t, err)
+	assert.False(t, isDrift)
+	assert.Equal(t, 1, cli.gets)
+	assert.Equal(t, 1, cli.patches)
+}
+
+func TestHasDriftedWithIgnorePaths(t *testing.T) {
+	t.Parallel()
+
+	const lastApplied = "kubectl.kubernetes.io/last-applied-configuration"
+	observed := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ignore-paths",
+			Namespace:   "default",
+			Annotations: map[string]string{lastApplied: "old", "team": "a"},
+		},
+		Data: map[string]string{"color": "red"},
+	}
+
+	var scenarios = []struct {
+		name        string
+		annotations map[string]string
+		data        map[string]string
+		ignorePaths []string
+		isDrift     bool
+	}{
+		{
+			name:        "should report drift when the annotation differs",
+			annotations: map[string]string{lastApplied: "new"},
+			data:        map[string]string{"color": "red"},
+			isDrift:     true,
+		},
+		{
+			name:        "should not report drift when only the ignored annotation differs",
+			annotations: map[string]string{lastApplied: "new"},
+			data:        map[string]string{"color": "red"},
+			ignorePaths: []string{`metadata.annotations["` + lastApplied + `"]`},
+		},
+		{
+			name:        "should report drift of the fields that are not ignored",
+			annotations: map[string]string{lastApplied: "new"},
+			data:        map[string]string{"color": "blue"},
+			ignorePaths: []string{`metadata.annotations["` + lastApplied + `"]`},
+			isDrift:     true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := &recordingClient{
+				Client: fake.NewClientBuilder().WithObjects(observed.DeepCopy()).Build(),
+			}
+			given := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ignore-paths",
+					Namespace:   "default",
+					Annotations: scenario.annotations,
+				},
+				Data: scenario.data,
+			}
+			isDrift, diff, err := HasDrifted(
+				context.Background(),
+				given,
+				&RunOptions{Client: cli},
+				WithIgnorePaths(scenario.ignorePaths...),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isDrift, isDrift, diff)
+		})
+	}
+}
+
+func TestOperationForActionType(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name    string
+		action  ActionType
+		isError bool
+	}{
+		{name: "should support create", action: ActionTypeCreate},
+		{name: "should support create or merge", action: ActionTypeCreateOrMerge},
+		{name: "should support get", action: ActionTypeGet},
+		{name: "should support delete", action: ActionTypeDelete},
+		{name: "should support update", action: ActionTypeUpdate},
+		{name: "should support apply", action: ActionTypeApply},
+		{name: "should support patch", action: ActionTypePatch},
+		{name: "should fail for unknown action", action: "Unknown", isError: true},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := OperationForActionType(scenario.action)
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, got)
+			}
+		})
+	}
+}
+
+func TestAssertOrError(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "assert-or-error",
+			Namespace: "default",
+		},
+		Data: map[string]string{"color": "red"},
+	}
+	changed := cm.DeepCopy()
+	changed.Data["color"] = "blue"
+	missing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "assert-or-error-missing",
+			Namespace: "default",
+		},
+	}
+
+	var scenarios = []struct {
+		name             string
+		given            client.Object
+		assertOptions    AssertOptions
+		verboseDiff      bool
+		isError          bool
+		isAssertionError bool
+		expectedMsg      string
+	}{
+		{
+			name:          "should pass when object is found",
+			given:         cm,
+			assertOptions: AssertOptions{AssertType: AssertTypeIsFound},
+		},
+		{
+			name:             "should fail with assertion error when object is not found",
+			given:            missing,
+			assertOptions:    AssertOptions{AssertType: AssertTypeIsFound},
+			isError:          true,
+			isAssertionError: true,
+		},
+		{
+			name:             "should not embed the diff by default",
+			given:            changed,
+			assertOptions:    AssertOptions{AssertType: AssertTypeIsEquals},
+			isError:          true,
+			isAssertionError: true,
+			expectedMsg:      "assert failed: Equals: default/assert-or-error",
+		},
+		{
+			name:             "should embed the diff when verbose",
+			given:            changed,
+			assertOptions:    AssertOptions{AssertType: AssertTypeIsEquals},
+			verboseDiff:      true,
+			isError:          true,
+			isAssertionError: true,
+		},
+		{
+			name:          "should fail without assertion error for unsupported assert type",
+			given:         cm,
+			assertOptions: AssertOptions{AssertType: "Unknown"},
+			isError:       true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build()
+			err := AssertOrError(
+				context.Background(),
+				scenario.given,
+				scenario.assertOptions,
+				&RunOptions{Client: cli, VerboseDiff: &scenario.verboseDiff},
+			)
+			if scenario.expectedMsg != "" {
+				assert.EqualError(t, err, scenario.expectedMsg)
+			}
+			if scenario.verboseDiff {
+				assert.Contains(t, err.Error(), "blue")
+			}
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, scenario.isAssertionError, IsAssertionError(err))
+		})
+	}
+}
+
+func TestIsStrictEqualAndIsSupersetOf(t *testing.T) {
+	t.Parallel()
+
+	deployment := func(labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "strict",
+				Namespace: "default",
+				Labels:    labels,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "strict"}},
+			},
+		}
+	}
+	// observed has system fields & status set by the cluster
+	observed := deployment(map[string]string{"app": "strict", "extra": "true"})
+	observed.ResourceVersion = "100"
+	observed.UID = "0000-1111"
+	observed.Generation = 2
+	observed.Status.ReadyReplicas = 1
+
+	var scenarios = []struct {
+		name             string
+		desired          *appsv1.Deployment
+		isEqual          bool
+		isStrictEqual    bool
+		isSupersetOf     bool
+		expectedDiffPart string
+	}{
+		{
+			name:          "should match all when labels are same",
+			desired:       deployment(map[string]string{"app": "strict", "extra": "true"}),
+			isEqual:       true,
+			isStrictEqual: true,
+			isSupersetOf:  true,
+		},
+		{
+			name:             "should be equal but not strictly when observed has extra labels",
+			desired:          deployment(map[string]string{"app": "strict"}),
+			isEqual:          true,
+			expectedDiffPart: "extra",
+		},
+		{
+			name:             "should be superset but not equal when desired has extra labels",
+			desired:          deployment(map[string]string{"app": "strict", "extra": "true", "more": "true"}),
+			isSupersetOf:     true,
+			expectedDiffPart: "more",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			isEqual, err := IsEqual(observed, scenario.desired)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isEqual, isEqual)
+
+			isStrictEqual, diff, err := IsStrictEqualWithDiffOutput(observed, scenario.desired)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isStrictEqual, isStrictEqual, diff)
+			if !isStrictEqual {
+				assert.Contains(t, diff, scenario.expectedDiffPart)
+			}
+
+			isSupersetOf, diff, err := IsSupersetOfWithDiffOutput(observed, scenario.desired)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isSupersetOf, isSupersetOf, diff)
+		})
+	}
+}
+
+func TestIsEqualAtPaths(t *testing.T) {
+	t.Parallel()
+
+	deployment := func(replicas int32, readyReplicas int32, image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "paths", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: pointer.Int32(replicas),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+				},
+			},
+			Status: appsv1.DeploymentStatus{ReadyReplicas: readyReplicas},
+		}
+	}
+	observed := deployment(3, 3, "nginx:1.21")
+
+	var scenarios = []struct {
+		name             string
+		desired          *appsv1.Deployment
+		paths            []string
+		isEqual          bool
+		isErr            bool
+		expectedDiffPart string
+	}{
+		{
+			name:    "should ignore the fields outside the nested status path",
+			desired: deployment(1, 3, "nginx:latest"),
+			paths:   []string{"status.readyReplicas"},
+			isEqual: true,
+		},
+		{
+			name:             "should not match when the nested status field differs",
+			desired:          deployment(3, 1, "nginx:1.21"),
+			paths:            []string{"status.readyReplicas"},
+			expectedDiffPart: "status.readyReplicas",
+		},
+		{
+			name:             "should not match when any of the paths differ",
+			desired:          deployment(3, 3, "nginx:latest"),
+			paths:            []string{"spec.replicas", "spec.template.spec.containers"},
+			expectedDiffPart: "nginx:latest",
+		},
+		{
+			name:    "should match when the path is found in neither",
+			desired: deployment(1, 1, "nginx:latest"),
+			paths:   []string{"spec.paused"},
+			isEqual: true,
+		},
+		{
+			name:    "should error without paths",
+			desired: deployment(3, 3, "nginx:1.21"),
+			isErr:   true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			isEqual, diff, err := IsEqualAtPaths(observed, scenario.desired, scenario.paths...)
+			if scenario.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isEqual, isEqual, diff)
+			if !isEqual {
+				assert.Contains(t, diff, scenario.expectedDiffPart)
+			}
+		})
+	}
+}
+
+func TestAssertWithComparePaths(t *testing.T) {
+	t.Parallel()
+
+	observed := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "compare-paths", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(3)},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 3},
+	}
+	opts := &RunOptions{Client: fake.NewClientBuilder().WithObjects(observed).Build()}
+	expected := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "compare-paths", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(1)},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 3},
+	}
+
+	result, _, err := Assert(context.Background(), expected, AssertOptions{AssertType: AssertTypeIsEquals}, opts)
+	assert.NoError(t, err)
+	assert.False(t, result, "replicas should differ when comparing the whole object")
+
+	result, diff, err := Assert(context.Background(), expected, AssertOptions{
+		AssertType:   AssertTypeIsEquals,
+		ComparePaths: []string{"status.readyReplicas"},
+	}, opts)
+	assert.NoError(t, err)
+	assert.True(t, result, diff)
+}
+
+func TestAssertWithNormalizeDefaults(t *testing.T) {
+	t.Parallel()
+
+	// the client-go scheme has no defaulters & hence a scheme with a
+	// pod defaulter similar to the one of the API server is used
+	rscheme := runtime.NewScheme()
+	assert.NoError(t, scheme.AddToScheme(rscheme))
+	rscheme.AddTypeDefaultingFunc(&corev1.Pod{}, func(obj interface{}) {
+		pod := obj.(*corev1.Pod)
+		for idx := range pod.Spec.Containers {
+			container := &pod.Spec.Containers[idx]
+			if container.ImagePullPolicy == "" {
+				container.ImagePullPolicy = corev1.PullIfNotPresent
+			}
+			if container.TerminationMessagePath == "" {
+				container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+			}
+		}
+	})
+
+	observed := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "normalize-defaults", Namespace: "default"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:                   "app",
+			Image:                  "nginx",
+			ImagePullPolicy:        corev1.PullIfNotPresent,
+			TerminationMessagePath: corev1.TerminationMessagePathDefault,
+		}}},
+	}
+	opts := &RunOptions{
+		Client: fake.NewClientBuilder().WithScheme(rscheme).WithObjects(observed).Build(),
+		Scheme: rscheme,
+	}
+	bare := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "normalize-defaults", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx"}}},
+	}
+	unstructuredBare := &unstructured.Unstructured{}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(bare)
+	assert.NoError(t, err)
+	unstructuredBare.SetUnstructuredContent(content)
+	unstructuredBare.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+
+	var scenarios = []struct {
+		name              string
+		expected          client.Object
+		normalizeDefaults bool
+		isMatch           bool
+	}{
+		{
+			name:     "should not strictly match a bare container spec",
+			expected: bare,
+		},
+		{
+			name:              "should strictly match a bare container spec after defaulting",
+			expected:          bare,
+			normalizeDefaults: true,
+			isMatch:           true,
+		},
+		{
+			name:              "should strictly match an unstructured bare container spec after defaulting",
+			expected:          unstructuredBare,
+			normalizeDefaults: true,
+			isMatch:           true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, diff, err := Assert(context.Background(), scenario.expected, AssertOptions{
+				AssertType:        AssertTypeIsStrictEquals,
+				NormalizeDefaults: scenario.normalizeDefaults,
+			}, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isMatch, result, diff)
+			if !scenario.isMatch {
+				assert.Contains(t, diff, "imagePullPolicy")
+			}
+		})
+	}
+}
+
+func TestSetDefaultNamespace(t *testing.T) {
+	t.Parallel()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	cli := fake.NewClientBuilder().WithRESTMapper(mapper).Build()
+
+	var scenarios = []struct {
+		name              string
+		given             client.Object
+		defaultNamespace  string
+		expectedNamespace string
+	}{
+		{
+			name:              "should set namespace of namespaced object",
+			given:             &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}},
+			defaultNamespace:  "team-a",
+			expectedNamespace: "team-a",
+		},
+		{
+			name:              "should not override namespace set by the object",
+			given:             &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "team-b"}},
+			defaultNamespace:  "team-a",
+			expectedNamespace: "team-b",
+		},
+		{
+			name:             "should not set namespace of cluster scoped object",
+			given:            &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}},
+			defaultNamespace: "team-a",
+		},
+		{
+			name:  "should not set namespace when default namespace is not set",
+			given: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			givenNamespace := scenario.given.GetNamespace()
+			got, err := setDefaultNamespace(
+				&RunOptions{Client: cli, Scheme: cli.Scheme(), DefaultNamespace: scenario.defaultNamespace},
+				scenario.given,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectedNamespace, got.GetNamespace())
+			assert.Equal(t, givenNamespace, scenario.given.GetNamespace()) // given is not mutated
+		})
+	}
+}
+
+func TestCreateWithDefaultNamespace(t *testing.T) {
+	t.Parallel()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	cli := fake.NewClientBuilder().WithRESTMapper(mapper).Build()
+	opts := []RunOption{&RunOptions{Client: cli}, WithDefaultNamespace("team-a")}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "default-namespace"}}
+	created, err := Create(context.Background(), cm, opts...)
+	assert.NoError(t, err)
+	assert.Equal(t, "team-a", created.GetNamespace())
+
+	got, err := Get(context.Background(), cm, opts...)
+	assert.NoError(t, err)
+	assert.Equal(t, "team-a", got.GetNamespace())
+}
+
+func TestCreateForManifestString(t *testing.T) {
+	t.Parallel()
+
+	manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: from-string
+  namespace: default
+---
+{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "from-json", "namespace": "default"}}
+`
+	cli := fake.NewClientBuilder().Build()
+	created, err := CreateForManifestString(context.Background(), manifest, &RunOptions{Client: cli})
+	assert.NoError(t, err)
+	assert.Len(t, created, 2)
+
+	list := &corev1.ConfigMapList{}
+	assert.NoError(t, cli.List(context.Background(), list))
+	assert.Len(t, list.Items, 2)
+
+	_, err = CreateForManifestString(context.Background(), "", &RunOptions{Client: cli})
+	assert.Error(t, err)
+}
@@ -11,7 +11,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 // TestUpsertVerbose verifies Upsert logic
@@ -307,16 +310,17 @@ func TestUpsertVerboseWithOptionAcceptNullFieldValues(t *testing.T) {
 			isUpsert: true,
 		},
 		{
-			name:       "should verify no change to cluster state when finalizers is set to empty value",
+			name:       "should verify change to cluster state when finalizers is set to empty value",
 			deployObj:  desiredDeploy.DeepCopy(),
 			finalizers: []string{},
-			result:     OperationResultNone, // BUG?: []string{} is not set
+			result:     OperationResultUpdatedResourceOnly,
+			isUpsert:   true,
 		},
 		{
 			name:       "should verify no change to cluster state when finalizers is set to nil",
 			deployObj:  desiredDeploy.DeepCopy(),
 			finalizers: []string(nil),
-			result:     OperationResultNone, // BUG?: []string(nil) is not set
+			result:     OperationResultNone, // nil can not be told apart from unset
 		},
 	}
 	ctx := context.Background()
@@ -478,3 +482,294 @@ func TestUpsertVerboseWithOptionSetFinalizersToNull(t *testing.T) {
 		})
 	}
 }
+
+// TestUpsertVerboseWithOptionPreserveObservedFields verifies Upsert logic
+// with PreserveObservedFieldsDuringUpsert option set
+//
+// Note: All the scenarios should be run in a serial order
+// Note: Execution of each scenario is **dependent** on execution of
+// previous scenario
+func TestUpsertVerboseWithOptionPreserveObservedFields(t *testing.T) {
+	svcName := fmt.Sprintf("test-svc-%d", rand.Int31())
+	desiredSvc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svcName,
+			Namespace: "default",
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name: "http",
+					Port: 80,
+				},
+			},
+		},
+	}
+	// desired state that un-sets the cluster ip allocated by the cluster
+	desiredSvcWithEmptyClusterIP := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "Service",
+			"apiVersion": "v1",
+			"metadata": map[string]interface{}{
+				"name":      svcName,
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"clusterIP": "",
+				"ports": []interface{}{
+					map[string]interface{}{
+						"name": "http",
+						"port": int64(80),
+					},
+				},
+			},
+		},
+	}
+
+	// these test scenarios must run one after the other
+	scenarios := []struct {
+		name           string
+		svcObj         client.Object
+		preserveFields []string
+		result         OperationResult
+		isError        bool
+	}{
+		{
+			name:   "should verify successful creation of service",
+			svcObj: desiredSvc.DeepCopy(),
+			result: OperationResultCreated,
+		},
+		{
+			name:    "should fail to revert the cluster ip since it is immutable",
+			svcObj:  desiredSvcWithEmptyClusterIP.DeepCopy(),
+			isError: true,
+		},
+		{
+			name:           "should verify no change to cluster state since cluster ip is preserved",
+			svcObj:         desiredSvcWithEmptyClusterIP.DeepCopy(),
+			preserveFields: []string{"spec.clusterIP"},
+			result:         OperationResultNone,
+		},
+	}
+	ctx := context.Background()
+	// teardown in defer statement
+	defer func() {
+		deleteErr := klient.Delete(ctx, desiredSvc)
+		if deleteErr != nil {
+			t.Logf("teardown service: delete operation: %s %s: %v", desiredSvc.Namespace, desiredSvc.Name, deleteErr)
+		}
+	}()
+	for _, scenario := range scenarios {
+		testcase := scenario // pin it
+		t.Run(testcase.name, func(t *testing.T) {
+			// target run options under test
+			opts := &RunOptions{
+				AcceptNullFieldValuesDuringUpsert:  pointer.Bool(true),
+				PreserveObservedFieldsDuringUpsert: testcase.preserveFields,
+			}
+			// target function under test
+			_, result, err := UpsertVerbose(ctx, testcase.svcObj, opts)
+			if testcase.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testcase.result, result)
+		})
+	}
+}
+
+func TestUpsertAllWithOnObjectProcessed(t *testing.T) {
+	t.Parallel()
+
+	var cmName = fmt.Sprintf("test-upsert-progress-%d", rand.Int31())
+	var given = []client.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cmName,
+				Namespace: "default",
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cmName,
+				Namespace: "none", // namespace does not exist
+			},
+		},
+	}
+
+	var processed []string
+	var results []OperationResult
+	var errs []error
+	opts := &RunOptions{
+		OnObjectProcessed: func(obj client.Object, result OperationResult, err error) {
+			processed = append(processed, obj.GetNamespace())
+			results = append(results, result)
+			errs = append(errs, err)
+		},
+	}
+	_, err := UpsertAll(context.Background(), given, opts)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"default", "none"}, processed)
+	assert.Equal(t, []OperationResult{OperationResultCreated, OperationResultNone}, results)
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1])
+}
+
+func TestUpsertVerboseUnstructuredWithoutSchemeRegistration(t *testing.T) {
+	t.Parallel()
+
+	// neither the client nor the run options know about this kind
+	emptyScheme := runtime.NewScheme()
+	cli := fake.NewClientBuilder().WithScheme(emptyScheme).Build()
+	opts := &RunOptions{Client: cli, Scheme: emptyScheme}
+
+	widget := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "example.simplekube.io/v1",
+			"kind":       "Widget",
+			"metadata": map[string]interface{}{
+				"name":      "upsert-unregistered",
+				"namespace": "default",
+			},
+		},
+	}
+
+	_, result, err := UpsertVerbose(context.Background(), widget, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, OperationResultCreated, result)
+
+	changed := widget.DeepCopy()
+	changed.Object["spec"] = map[string]interface{}{"size": "large"}
+	_, result, err = UpsertVerbose(context.Background(), changed, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, OperationResultUpdatedResourceOnly, result)
+
+	got, err := Get(context.Background(), widget, opts)
+	assert.NoError(t, err)
+	size, _, _ := unstructured.NestedString(got.(*unstructured.Unstructured).Object, "spec", "size")
+	assert.Equal(t, "large", size)
+}
+
+func TestUpsertVerboseClearsFinalizersWithEmptyValue(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "upsert-clear-finalizers",
+			Namespace:  "default",
+			Finalizers: []string{"protect.io/storage", "protect.io/compute"},
+		},
+	}
+
+	var scenarios = []struct {
+		name               string
+		finalizers         []string
+		acceptNullValues   bool
+		expectedResult     OperationResult
+		expectedFinalizers []string
+	}{
+		{
+			name:             "should clear finalizers when set to empty value",
+			finalizers:       []string{},
+			acceptNullValues: true,
+			expectedResult:   OperationResultUpdatedResourceOnly,
+		},
+		{
+			name:               "should retain finalizers when set to nil",
+			acceptNullValues:   true,
+			expectedResult:     OperationResultNone,
+			expectedFinalizers: cm.Finalizers,
+		},
+		{
+			name:               "should retain finalizers when null values are not accepted",
+			finalizers:         []string{},
+			expectedResult:     OperationResultNone,
+			expectedFinalizers: cm.Finalizers,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build()
+			opts := &RunOptions{Client: cli, AcceptNullFieldValuesDuringUpsert: &scenario.acceptNullValues}
+			desired := cm.DeepCopy()
+			desired.SetFinalizers(scenario.finalizers)
+
+			_, result, err := UpsertVerbose(context.Background(), desired, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectedResult, result)
+
+			got, err := Get(context.Background(), cm, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectedFinalizers, got.GetFinalizers())
+		})
+	}
+}
+
+func TestUpsertVerboseWithDiff(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "upsert-with-diff",
+			Namespace: "default",
+			Labels:    map[string]string{"tier": "backend"},
+		},
+	}
+
+	var scenarios = []struct {
+		name           string
+		observed       []client.Object
+		labels         map[string]string
+		expectedResult OperationResult
+		expectedDiff   []string
+	}{
+		{
+			name:           "should not report a diff when the resource is created",
+			labels:         map[string]string{"tier": "frontend"},
+			expectedResult: OperationResultCreated,
+		},
+		{
+			name:           "should not report a diff when the resource is not updated",
+			observed:       []client.Object{cm.DeepCopy()},
+			labels:         map[string]string{"tier": "backend"},
+			expectedResult: OperationResultNone,
+		},
+		{
+			name:           "should report the changed label when the resource is updated",
+			observed:       []client.Object{cm.DeepCopy()},
+			labels:         map[string]string{"tier": "frontend"},
+			expectedResult: OperationResultUpdatedResourceOnly,
+			expectedDiff:   []string{"tier", "backend", "frontend"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := fake.NewClientBuilder().WithObjects(scenario.observed...).Build()
+			desired := cm.DeepCopy()
+			desired.SetLabels(scenario.labels)
+
+			_, result, diff, err := UpsertVerboseWithDiff(context.Background(), desired, &RunOptions{Client: cli})
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectedResult, result)
+			if len(scenario.expectedDiff) == 0 {
+				assert.Empty(t, diff)
+			}
+			for _, expected := range scenario.expectedDiff {
+				assert.Contains(t, diff, expected)
+			}
+		})
+	}
+}
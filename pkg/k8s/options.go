@@ -1,10 +1,20 @@
 package k8s
 
 import (
+	"io"
+	"net/url"
+	"sync"
+
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 // This file makes use of functional options pattern
@@ -21,6 +31,10 @@ type RunOptions struct {
 	Clientset *kubernetes.Clientset
 	Scheme    *runtime.Scheme
 
+	// RestConfig is used to invoke APIs that are not supported by the
+	// controller-runtime client e.g. pod exec
+	RestConfig *rest.Config
+
 	// Desired state field(s) with null or empty value(s) are considered
 	// as valid during Upsert operation
 	AcceptNullFieldValuesDuringUpsert *bool
@@ -28,6 +42,139 @@ type RunOptions struct {
 	// SetFinalizersToNullDuringUpsert when true will set the target's
 	// finalizers to nil during Upsert operation
 	SetFinalizersToNullDuringUpsert *bool
+
+	// PreserveObservedFieldsDuringUpsert is the list of dotted field
+	// paths e.g. spec.clusterIP whose observed values are retained during
+	// Upsert operation
+	PreserveObservedFieldsDuringUpsert []string
+
+	// OnObjectProcessed when set is invoked after each object is processed
+	// by an operation invoked against a list of objects e.g. ApplyAll. This
+	// can be used to report progress.
+	OnObjectProcessed func(obj client.Object, result OperationResult, err error)
+
+	// DiagnoseDeleteFailures when true adds the deletion timestamp,
+	// finalizers & owner references of the object to the error returned
+	// by a failed Delete operation
+	DiagnoseDeleteFailures *bool
+
+	// ListOptions are passed to the client when listing objects e.g.
+	// to filter by namespace or labels
+	ListOptions []client.ListOption
+
+	// RequireNamespaceExists when true makes Create, Apply & Upsert
+	// operations verify the presence of the object's namespace. This
+	// results in a clear error instead of a confusing server error.
+	RequireNamespaceExists *bool
+
+	// WarningHandler when set is invoked with each warning returned by
+	// the API server e.g. usage of a deprecated API. This is applicable
+	// only when the Client is not set & hence built from the RestConfig.
+	WarningHandler func(message string)
+
+	// RateLimiter when set throttles every request made by the clients
+	// that are built from the RestConfig. A single limiter e.g. a token
+	// bucket registered via RegisterBaseRunOptions caps the total QPS of
+	// this package irrespective of the number of parallel invocations.
+	RateLimiter flowcontrol.RateLimiter
+
+	// ReportSink when set receives a JSON line per step executed by a
+	// Job
+	ReportSink io.Writer
+
+	// FieldOwner is the field manager used by server side apply based
+	// operations i.e. Apply & DryRun
+	FieldOwner string
+
+	// Cache when set serves the repeated gets of an object from the
+	// objects fetched earlier. This is disabled by default.
+	Cache *ObjectCache
+
+	// ConflictStrategy decides how Apply handles conflicts with the
+	// fields owned by other field managers. Apply forces the ownership
+	// of these fields if this is not set.
+	ConflictStrategy ConflictStrategy
+
+	// DeleteOptions are passed to the client when deleting objects e.g.
+	// to set the propagation policy or the grace period
+	DeleteOptions []client.DeleteOption
+
+	// LogTailLines when set limits the logs returned by GetLogs to the
+	// provided number of lines from the end
+	LogTailLines *int64
+
+	// LogPrevious when true makes GetLogs return the logs of the
+	// previous terminated instance of the container
+	LogPrevious *bool
+
+	// VerboseDiff when true embeds the difference between the observed
+	// & the expected states in the error returned by a failed assertion.
+	// This is not the default since the difference can be huge.
+	VerboseDiff *bool
+
+	// DefaultNamespace when set is used as the namespace of the namespace
+	// scoped objects that do not set their namespace. Objects that set
+	// their namespace & cluster scoped objects are left as-is.
+	DefaultNamespace string
+
+	// Logger when set receives the progress of the steps executed by a
+	// Job. Failures are logged as errors while the start & the end of
+	// each step are logged at V(1). Log lines are discarded if this is
+	// not set.
+	Logger logr.Logger
+
+	// MetricsRecorder when set records the latency & the outcome of the
+	// API calls made by the operations e.g. Get, Create & Apply
+	MetricsRecorder MetricsRecorder
+
+	// RetryPolicy when set retries the API calls of Get, Create, Update,
+	// Patch, Apply & Delete that fail with a transient error
+	RetryPolicy *RetryPolicy
+
+	// GCRegistrar when set gets a DeleteRunner registered for each object
+	// created by Create, Upsert & ServerSideUpsert. A registrar per Job or
+	// per suite can then be torn down independently of the others.
+	GCRegistrar Registrar
+
+	// IgnorePaths is the list of dotted field paths e.g.
+	// metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"]
+	// that are ignored by HasDrifted & DriftPatch
+	IgnorePaths []string
+
+	// RedactKinds is the list of kinds whose data & stringData values are
+	// redacted in the diffs reported by the assertions, HasDrifted &
+	// UpsertVerboseWithDiff
+	//
+	// Note: Secrets are always redacted
+	RedactKinds []schema.GroupKind
+
+	// clientsets memoizes the clientset built from the rest config. This
+	// is shared by the copies of the base run options & is not inherited
+	// by the options that override the rest config.
+	clientsets *clientsetCache
+}
+
+// clientsetCache holds the clientset built from the rest config of the
+// run options it is set in
+type clientsetCache struct {
+	mu        sync.Mutex
+	clientset *kubernetes.Clientset
+}
+
+// getOrBuild returns the memoized clientset or else builds one via the
+// provided function. A failed build is not memoized.
+func (c *clientsetCache) getOrBuild(build func() (*kubernetes.Clientset, error)) (*kubernetes.Clientset, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clientset != nil {
+		return c.clientset, nil
+	}
+	cs, err := build()
+	if err != nil {
+		return nil, err
+	}
+	c.clientset = cs
+	return cs, nil
 }
 
 // compile time check to assert if the structure
@@ -56,12 +203,211 @@ func (o *RunOptions) ApplyTo(target RunOption) error {
 	if o.Scheme != nil {
 		targetObj.Scheme = o.Scheme
 	}
+	if o.RestConfig != nil {
+		targetObj.RestConfig = o.RestConfig
+	}
+	if o.clientsets != nil || o.RestConfig != nil || o.WarningHandler != nil || o.RateLimiter != nil {
+		// clientset built from the overridden config is not the same
+		targetObj.clientsets = o.clientsets
+	}
 	if o.AcceptNullFieldValuesDuringUpsert != nil {
 		targetObj.AcceptNullFieldValuesDuringUpsert = o.AcceptNullFieldValuesDuringUpsert
 	}
 	if o.SetFinalizersToNullDuringUpsert != nil {
 		targetObj.SetFinalizersToNullDuringUpsert = o.SetFinalizersToNullDuringUpsert
 	}
+	if o.PreserveObservedFieldsDuringUpsert != nil {
+		targetObj.PreserveObservedFieldsDuringUpsert = o.PreserveObservedFieldsDuringUpsert
+	}
+	if o.OnObjectProcessed != nil {
+		targetObj.OnObjectProcessed = o.OnObjectProcessed
+	}
+	if o.DiagnoseDeleteFailures != nil {
+		targetObj.DiagnoseDeleteFailures = o.DiagnoseDeleteFailures
+	}
+	if o.ListOptions != nil {
+		targetObj.ListOptions = o.ListOptions
+	}
+	if o.RequireNamespaceExists != nil {
+		targetObj.RequireNamespaceExists = o.RequireNamespaceExists
+	}
+	if o.WarningHandler != nil {
+		targetObj.WarningHandler = o.WarningHandler
+	}
+	if o.RateLimiter != nil {
+		targetObj.RateLimiter = o.RateLimiter
+	}
+	if o.ReportSink != nil {
+		targetObj.ReportSink = o.ReportSink
+	}
+	if o.FieldOwner != "" {
+		targetObj.FieldOwner = o.FieldOwner
+	}
+	if o.ConflictStrategy != "" {
+		targetObj.ConflictStrategy = o.ConflictStrategy
+	}
+	if o.Cache != nil {
+		targetObj.Cache = o.Cache
+	}
+	if o.DeleteOptions != nil {
+		targetObj.DeleteOptions = o.DeleteOptions
+	}
+	if o.LogTailLines != nil {
+		targetObj.LogTailLines = o.LogTailLines
+	}
+	if o.LogPrevious != nil {
+		targetObj.LogPrevious = o.LogPrevious
+	}
+	if o.VerboseDiff != nil {
+		targetObj.VerboseDiff = o.VerboseDiff
+	}
+	if o.DefaultNamespace != "" {
+		targetObj.DefaultNamespace = o.DefaultNamespace
+	}
+	if o.Logger != nil {
+		targetObj.Logger = o.Logger
+	}
+	if o.MetricsRecorder != nil {
+		targetObj.MetricsRecorder = o.MetricsRecorder
+	}
+	if o.RetryPolicy != nil {
+		targetObj.RetryPolicy = o.RetryPolicy
+	}
+	if o.GCRegistrar != nil {
+		targetObj.GCRegistrar = o.GCRegistrar
+	}
+	if o.IgnorePaths != nil {
+		targetObj.IgnorePaths = o.IgnorePaths
+	}
+	if o.RedactKinds != nil {
+		targetObj.RedactKinds = o.RedactKinds
+	}
+	return nil
+}
+
+// toRunOptions returns the provided target as RunOptions
+func toRunOptions(target RunOption) (*RunOptions, error) {
+	if target == nil {
+		return nil, errors.Errorf("nil target options")
+	}
+	targetObj, ok := target.(*RunOptions)
+	if !ok {
+		return nil, errors.Errorf("invalid options type: want 'RunOptions' got %T", target)
+	}
+	return targetObj, nil
+}
+
+type withClient struct {
+	client client.Client
+}
+
+// ApplyTo sets the client in the provided target
+func (o withClient) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.Client = o.client
+	return nil
+}
+
+// WithClient returns an option that sets the client
+func WithClient(c client.Client) RunOption {
+	return withClient{client: c}
+}
+
+type withScheme struct {
+	scheme *runtime.Scheme
+}
+
+// ApplyTo sets the scheme in the provided target
+func (o withScheme) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.Scheme = o.scheme
+	return nil
+}
+
+// WithScheme returns an option that sets the scheme
+func WithScheme(s *runtime.Scheme) RunOption {
+	return withScheme{scheme: s}
+}
+
+type withClientset struct {
+	clientset *kubernetes.Clientset
+}
+
+// ApplyTo sets the clientset in the provided target
+func (o withClientset) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.Clientset = o.clientset
+	return nil
+}
+
+// WithClientset returns an option that sets the clientset
+func WithClientset(cs *kubernetes.Clientset) RunOption {
+	return withClientset{clientset: cs}
+}
+
+type withFieldOwner struct {
+	name string
+}
+
+// ApplyTo sets the field owner in the provided target
+func (o withFieldOwner) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.FieldOwner = o.name
+	return nil
+}
+
+// WithFieldOwner returns an option that sets the field manager used
+// by server side apply based operations
+func WithFieldOwner(name string) RunOption {
+	return withFieldOwner{name: name}
+}
+
+// Validate returns error if the options are not consistent with
+// each other
+func (o *RunOptions) Validate() error {
+	if o == nil {
+		return errors.New("nil options")
+	}
+	if o.RestConfig != nil && o.Clientset != nil {
+		configURL, err := url.Parse(o.RestConfig.Host)
+		if err != nil {
+			return errors.Wrapf(err, "invalid rest config host %q", o.RestConfig.Host)
+		}
+		clientsetURL := o.Clientset.CoreV1().RESTClient().Get().URL()
+		if configURL.Host != "" && configURL.Host != clientsetURL.Host {
+			return errors.Errorf(
+				"invalid options: rest config host %q does not match clientset host %q",
+				configURL.Host, clientsetURL.Host,
+			)
+		}
+	}
+	for _, path := range o.PreserveObservedFieldsDuringUpsert {
+		if _, err := ParseFieldPath(path); err != nil {
+			return errors.Wrap(err, "invalid options: preserve observed fields during upsert")
+		}
+	}
+	for _, path := range o.IgnorePaths {
+		if _, err := ParseFieldPath(path); err != nil {
+			return errors.Wrap(err, "invalid options: ignore paths")
+		}
+	}
+	switch o.ConflictStrategy {
+	case "", ConflictStrategyForce, ConflictStrategyFail, ConflictStrategyReport:
+	default:
+		return errors.Errorf("invalid options: unsupported conflict strategy %q", o.ConflictStrategy)
+	}
 	return nil
 }
 
@@ -88,5 +434,284 @@ func FromRunOptions(options ...RunOption) (*RunOptions, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = target.Validate()
+	if err != nil {
+		return nil, err
+	}
 	return &target, nil
 }
+
+// getRestConfig returns the rest config set in the provided options
+// or else loads it from the kubeconfig
+func getRestConfig(opts *RunOptions) (*rest.Config, error) {
+	if opts.RestConfig != nil {
+		return opts.RestConfig, nil
+	}
+	// Note: GetConfig is preferred over GetConfigOrDie since the
+	// latter exits the process when kubeconfig is not found
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kubeconfig")
+	}
+	return cfg, nil
+}
+
+// warningHandlerFunc adapts a function to rest.WarningHandler
+type warningHandlerFunc func(message string)
+
+// compile time check to assert if warningHandlerFunc
+// implements rest.WarningHandler
+var _ rest.WarningHandler = warningHandlerFunc(nil)
+
+// HandleWarningHeader invokes the function with the warning message
+func (fn warningHandlerFunc) HandleWarningHeader(code int, agent string, message string) {
+	fn(message)
+}
+
+// getClientConfig returns the rest config to build the default clients
+// with the warning handler & rate limiter set if any
+//
+// Note: The rest config set in the options is not mutated
+func getClientConfig(opts *RunOptions) (*rest.Config, error) {
+	cfg, err := getRestConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.WarningHandler == nil && opts.RateLimiter == nil {
+		return cfg, nil
+	}
+	cfg = rest.CopyConfig(cfg)
+	if opts.WarningHandler != nil {
+		cfg.WarningHandler = warningHandlerFunc(opts.WarningHandler)
+	}
+	if opts.RateLimiter != nil {
+		cfg.RateLimiter = opts.RateLimiter
+	}
+	return cfg, nil
+}
+
+// GetOrBuildClientset returns the clientset set in the options or else
+// builds one from the rest config i.e. the same config that is used to
+// build the default client. The clientset built from the base run
+// options is memoized & is hence reused by the subsequent operations.
+//
+// Note: A clientset built from the rest config, warning handler or rate
+// limiter set at the operation is not memoized
+func (o *RunOptions) GetOrBuildClientset() (*kubernetes.Clientset, error) {
+	if o == nil {
+		return nil, errors.New("nil options")
+	}
+	if o.Clientset != nil {
+		return o.Clientset, nil
+	}
+	build := func() (*kubernetes.Clientset, error) {
+		cfg, err := getClientConfig(o)
+		if err != nil {
+			return nil, err
+		}
+		cs, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to initialise clientset")
+		}
+		return cs, nil
+	}
+	if o.clientsets == nil {
+		return build()
+	}
+	return o.clientsets.getOrBuild(build)
+}
+
+type withDefaultNamespace struct {
+	namespace string
+}
+
+// ApplyTo sets the default namespace in the provided target
+func (o withDefaultNamespace) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.DefaultNamespace = o.namespace
+	return nil
+}
+
+// WithDefaultNamespace returns an option that sets the namespace of the
+// namespace scoped objects that do not set their namespace
+func WithDefaultNamespace(namespace string) RunOption {
+	return withDefaultNamespace{namespace: namespace}
+}
+
+type withLogger struct {
+	logger logr.Logger
+}
+
+// ApplyTo sets the logger in the provided target
+func (o withLogger) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.Logger = o.logger
+	return nil
+}
+
+// WithLogger returns an option that sets the logger used to report the
+// progress of a Job
+func WithLogger(logger logr.Logger) RunOption {
+	return withLogger{logger: logger}
+}
+
+// getLogger returns the logger set in the provided options or else a
+// logger that discards the log lines
+func getLogger(opts *RunOptions) logr.Logger {
+	if opts == nil || opts.Logger == nil {
+		return logr.Discard()
+	}
+	return opts.Logger
+}
+
+type withMetricsRecorder struct {
+	recorder MetricsRecorder
+}
+
+// ApplyTo sets the metrics recorder in the provided target
+func (o withMetricsRecorder) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.MetricsRecorder = o.recorder
+	return nil
+}
+
+// WithMetricsRecorder returns an option that sets the recorder of the
+// latency & the outcome of the operations
+func WithMetricsRecorder(recorder MetricsRecorder) RunOption {
+	return withMetricsRecorder{recorder: recorder}
+}
+
+type withPropagationPolicy struct {
+	policy metav1.DeletionPropagation
+}
+
+// ApplyTo adds the propagation policy to the delete options of the
+// provided target
+func (o withPropagationPolicy) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	// copy to avoid mutating the delete options shared with other options
+	deleteOpts := make([]client.DeleteOption, 0, len(targetObj.DeleteOptions)+1)
+	deleteOpts = append(deleteOpts, targetObj.DeleteOptions...)
+	targetObj.DeleteOptions = append(deleteOpts, client.PropagationPolicy(o.policy))
+	return nil
+}
+
+// WithPropagationPolicy returns an option that deletes the dependents of
+// the deleted objects as per the provided policy e.g. Foreground
+func WithPropagationPolicy(policy metav1.DeletionPropagation) RunOption {
+	return withPropagationPolicy{policy: policy}
+}
+
+type withGCRegistrar struct {
+	registrar Registrar
+}
+
+// ApplyTo sets the garbage collector registrar in the provided target
+func (o withGCRegistrar) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.GCRegistrar = o.registrar
+	return nil
+}
+
+// WithGCRegistrar returns an option that registers the created objects
+// for garbage collection in the provided registrar
+func WithGCRegistrar(registrar Registrar) RunOption {
+	return withGCRegistrar{registrar: registrar}
+}
+
+type withIgnorePaths struct {
+	paths []string
+}
+
+// ApplyTo sets the ignore paths in the provided target
+func (o withIgnorePaths) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.IgnorePaths = o.paths
+	return nil
+}
+
+// WithIgnorePaths returns an option that ignores the fields at the
+// provided dotted paths during drift detection
+func WithIgnorePaths(paths ...string) RunOption {
+	return withIgnorePaths{paths: paths}
+}
+
+type withConflictStrategy struct {
+	strategy ConflictStrategy
+}
+
+// ApplyTo sets the conflict strategy in the provided target
+func (o withConflictStrategy) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.ConflictStrategy = o.strategy
+	return nil
+}
+
+// WithConflictStrategy returns an option that decides how Apply handles
+// conflicts with the fields owned by other field managers
+func WithConflictStrategy(strategy ConflictStrategy) RunOption {
+	return withConflictStrategy{strategy: strategy}
+}
+
+type withCache struct {
+	cache *ObjectCache
+}
+
+// ApplyTo sets the object cache in the provided target
+func (o withCache) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.Cache = o.cache
+	return nil
+}
+
+// WithCache returns an option that serves the repeated gets of an object
+// from the provided cache. A single cache can be shared by the steps of
+// a Job to avoid fetching the same object repeatedly.
+func WithCache(cache *ObjectCache) RunOption {
+	return withCache{cache: cache}
+}
+
+type withRedactKinds struct {
+	kinds []schema.GroupKind
+}
+
+// ApplyTo sets the redact kinds in the provided target
+func (o withRedactKinds) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.RedactKinds = o.kinds
+	return nil
+}
+
+// WithRedactKinds returns an option that redacts the data & stringData
+// values of the provided kinds in the reported diffs. Secrets are
+// redacted irrespective of this option.
+func WithRedactKinds(kinds ...schema.GroupKind) RunOption {
+	return withRedactKinds{kinds: kinds}
+}
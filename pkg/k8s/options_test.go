@@ -0,0 +1,314 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/simplekube/kit/pkg/pointer"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRunOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	clusterA, err := kubernetes.NewForConfig(&rest.Config{Host: "https://cluster-a:6443"})
+	assert.NoError(t, err)
+
+	var scenarios = []struct {
+		name    string
+		options *RunOptions
+		isError bool
+	}{
+		{
+			name:    "should accept empty options",
+			options: &RunOptions{},
+		},
+		{
+			name: "should accept rest config & clientset of the same cluster",
+			options: &RunOptions{
+				RestConfig: &rest.Config{Host: "https://cluster-a:6443"},
+				Clientset:  clusterA,
+			},
+		},
+		{
+			name: "should reject rest config & clientset of different clusters",
+			options: &RunOptions{
+				RestConfig: &rest.Config{Host: "https://cluster-b:6443"},
+				Clientset:  clusterA,
+			},
+			isError: true,
+		},
+		{
+			name: "should reject invalid field path to preserve during upsert",
+			options: &RunOptions{
+				PreserveObservedFieldsDuringUpsert: []string{"metadata.labels[app"},
+			},
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := scenario.options.Validate()
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			_, err = FromRunOptions(scenario.options)
+			assert.Equal(t, scenario.isError, err != nil)
+		})
+	}
+}
+
+func TestGetClientConfigWithWarningHandler(t *testing.T) {
+	t.Parallel()
+
+	given := &rest.Config{Host: "https://cluster-a:6443"}
+	var warnings []string
+	got, err := getClientConfig(&RunOptions{
+		RestConfig: given,
+		WarningHandler: func(message string) {
+			warnings = append(warnings, message)
+		},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, given.WarningHandler, "provided rest config must not be mutated")
+
+	got.WarningHandler.HandleWarningHeader(299, "-", "v1beta1 Ingress is deprecated")
+	assert.Equal(t, []string{"v1beta1 Ingress is deprecated"}, warnings)
+}
+
+func TestGetClientConfigWithRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	given := &rest.Config{Host: "https://cluster-a:6443"}
+	limiter := flowcontrol.NewTokenBucketRateLimiter(5, 10)
+	got, err := getClientConfig(&RunOptions{
+		RestConfig:  given,
+		RateLimiter: limiter,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, limiter, got.RateLimiter)
+	assert.Nil(t, given.RateLimiter, "provided rest config must not be mutated")
+
+	got, err = getClientConfig(&RunOptions{RestConfig: given})
+	assert.NoError(t, err)
+	assert.Same(t, given, got)
+}
+
+// Note: This test is not run in parallel since it modifies the base
+// run options that are used by other tests
+func TestUnregisterBaseRunOptions(t *testing.T) {
+	_baseRunOptionsMu.RLock()
+	original, wasRegistered := _baseRunOptions, _isBaseRunOptionsRegistered
+	_baseRunOptionsMu.RUnlock()
+	defer func() {
+		UnregisterBaseRunOptions()
+		if wasRegistered {
+			assert.NoError(t, RegisterBaseRunOptions(original))
+		}
+	}()
+
+	UnregisterBaseRunOptions()
+	first := &RunOptions{DiagnoseDeleteFailures: pointer.Bool(true)}
+	assert.NoError(t, RegisterBaseRunOptions(first))
+	assert.Error(t, RegisterBaseRunOptions(&RunOptions{}), "re-register without reset")
+	assert.Same(t, first, getBaseRunOptions())
+
+	UnregisterBaseRunOptions()
+	second := &RunOptions{DiagnoseDeleteFailures: pointer.Bool(false)}
+	assert.NoError(t, RegisterBaseRunOptions(second))
+
+	got, err := makeRunOptionsWithBase()
+	assert.NoError(t, err)
+	assert.False(t, *got.DiagnoseDeleteFailures)
+}
+
+func TestOptionConstructors(t *testing.T) {
+	t.Parallel()
+
+	clusterA, err := kubernetes.NewForConfig(&rest.Config{Host: "https://cluster-a:6443"})
+	assert.NoError(t, err)
+	schemeA := runtime.NewScheme()
+	schemeB := runtime.NewScheme()
+
+	var scenarios = []struct {
+		name     string
+		options  []RunOption
+		expected *RunOptions
+	}{
+		{
+			name:    "should compose option constructors",
+			options: []RunOption{WithScheme(schemeA), WithClientset(clusterA), WithFieldOwner("my-test")},
+			expected: &RunOptions{
+				Scheme:     schemeA,
+				Clientset:  clusterA,
+				FieldOwner: "my-test",
+			},
+		},
+		{
+			name:    "should let later options override earlier ones",
+			options: []RunOption{WithScheme(schemeA), &RunOptions{Scheme: schemeB, FieldOwner: "literal"}, WithFieldOwner("my-test")},
+			expected: &RunOptions{
+				Scheme:     schemeB,
+				FieldOwner: "my-test",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := FromRunOptions(scenario.options...)
+			assert.NoError(t, err)
+			assert.Same(t, scenario.expected.Scheme, got.Scheme)
+			assert.Same(t, scenario.expected.Clientset, got.Clientset)
+			assert.Equal(t, scenario.expected.FieldOwner, got.FieldOwner)
+		})
+	}
+}
+
+func TestOptionConstructorWithInvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	err := WithFieldOwner("my-test").ApplyTo(WithScheme(nil))
+	assert.Error(t, err)
+}
+
+func TestRunOptionsGetOrBuildClientset(t *testing.T) {
+	t.Parallel()
+
+	prebuilt, err := kubernetes.NewForConfig(&rest.Config{Host: "https://cluster-a:6443"})
+	assert.NoError(t, err)
+
+	var scenarios = []struct {
+		name       string
+		options    *RunOptions
+		expectHost string
+		isMemoized bool
+		isError    bool
+	}{
+		{
+			name:       "should return the prebuilt clientset",
+			options:    &RunOptions{Clientset: prebuilt},
+			expectHost: "cluster-a:6443",
+			isMemoized: true,
+		},
+		{
+			name:       "should build the clientset from the rest config",
+			options:    &RunOptions{RestConfig: &rest.Config{Host: "https://cluster-b:6443"}},
+			expectHost: "cluster-b:6443",
+		},
+		{
+			name: "should memoize the clientset built from the base run options",
+			options: &RunOptions{
+				RestConfig: &rest.Config{Host: "https://cluster-c:6443"},
+				clientsets: &clientsetCache{},
+			},
+			expectHost: "cluster-c:6443",
+			isMemoized: true,
+		},
+		{
+			name:    "should error for nil options",
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := scenario.options.GetOrBuildClientset()
+			if scenario.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectHost, got.CoreV1().RESTClient().Get().URL().Host)
+
+			again, err := scenario.options.GetOrBuildClientset()
+			assert.NoError(t, err)
+			if scenario.isMemoized {
+				assert.Same(t, got, again, "clientset should be memoized")
+			} else {
+				assert.NotSame(t, got, again, "clientset should not be memoized")
+			}
+		})
+	}
+}
+
+// Note: This test is not run in parallel since it modifies the base
+// run options that are used by other tests
+func TestClientsetIsReusedAcrossOperations(t *testing.T) {
+	_baseRunOptionsMu.RLock()
+	original, wasRegistered := _baseRunOptions, _isBaseRunOptionsRegistered
+	_baseRunOptionsMu.RUnlock()
+	defer func() {
+		UnregisterBaseRunOptions()
+		if wasRegistered {
+			assert.NoError(t, RegisterBaseRunOptions(original))
+		}
+	}()
+
+	ctx := context.Background()
+	deploy := func(name string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(1)},
+		}
+	}
+	cli := fake.NewClientBuilder().WithObjects(deploy("first"), deploy("second")).Build()
+	srv := scaleServer(cli)
+	defer srv.Close()
+
+	UnregisterBaseRunOptions()
+	assert.NoError(t, RegisterBaseRunOptions(&RunOptions{
+		Client:     cli,
+		RestConfig: &rest.Config{Host: srv.URL},
+	}))
+
+	// operations are run in parallel to detect races via go test -race
+	var wg sync.WaitGroup
+	for _, name := range []string{"first", "second"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			_, err := Scale(ctx, deploy(name), 2)
+			assert.NoError(t, err)
+		}(name)
+	}
+	wg.Wait()
+
+	first, err := makeRunOptionsWithBase()
+	assert.NoError(t, err)
+	second, err := makeRunOptionsWithBase()
+	assert.NoError(t, err)
+	got, err := first.GetOrBuildClientset()
+	assert.NoError(t, err)
+	again, err := second.GetOrBuildClientset()
+	assert.NoError(t, err)
+	assert.Same(t, got, again, "operations should reuse the clientset")
+	assert.Same(t, getBaseRunOptions().clientsets.clientset, got, "clientset should be built by the operations")
+
+	overridden, err := makeRunOptionsWithBase(&RunOptions{RestConfig: &rest.Config{Host: srv.URL}})
+	assert.NoError(t, err)
+	other, err := overridden.GetOrBuildClientset()
+	assert.NoError(t, err)
+	assert.NotSame(t, got, other, "overridden rest config should not reuse the clientset")
+}
@@ -0,0 +1,186 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PlanAction is the change that applying an object would make to the
+// cluster
+type PlanAction string
+
+const (
+	// PlanActionCreate implies the object is absent & would be created
+	PlanActionCreate PlanAction = "create"
+
+	// PlanActionUpdate implies the object has drifted & would be updated
+	PlanActionUpdate PlanAction = "update"
+
+	// PlanActionUnchanged implies the object has not drifted
+	PlanActionUnchanged PlanAction = "unchanged"
+)
+
+// PlanEntry is the planned change of a single object
+type PlanEntry struct {
+	Object client.Object
+	Action PlanAction
+
+	// Diff is the drift between the observed & the dry run states of
+	// the object. This is set only if the object would be updated.
+	Diff string
+}
+
+// Plan is the list of changes that applying a set of objects would make
+// to the cluster
+type Plan struct {
+	Entries []PlanEntry
+}
+
+// Count returns the number of entries with the provided action
+func (p *Plan) Count(action PlanAction) int {
+	if p == nil {
+		return 0
+	}
+	var count int
+	for _, entry := range p.Entries {
+		if entry.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// String returns the human readable form of the plan. Each entry is
+// prefixed with + if it would be created, ~ if it would be updated & a
+// blank otherwise. Diffs of the updates follow their entries.
+func (p *Plan) String() string {
+	if p == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, entry := range p.Entries {
+		var prefix = " "
+		switch entry.Action {
+		case PlanActionCreate:
+			prefix = "+"
+		case PlanActionUpdate:
+			prefix = "~"
+		}
+		fmt.Fprintf(&sb, "%s %s: %s\n", prefix, entry.Action, k8sutil.DescribeObj(entry.Object))
+		if entry.Diff != "" {
+			fmt.Fprintf(&sb, "%s\n", entry.Diff)
+		}
+	}
+	fmt.Fprintf(
+		&sb,
+		"Plan: %d to create, %d to update, %d unchanged\n",
+		p.Count(PlanActionCreate), p.Count(PlanActionUpdate), p.Count(PlanActionUnchanged),
+	)
+	return sb.String()
+}
+
+// planObject returns the change that applying the provided object would
+// make to the cluster
+func planObject(ctx context.Context, given client.Object, options ...RunOption) (PlanEntry, error) {
+	var entry = PlanEntry{Object: given}
+	observed, err := Get(ctx, given, options...)
+	if err != nil {
+		if !apierrors.IsNotFound(errors.Cause(err)) {
+			return entry, err
+		}
+		entry.Action = PlanActionCreate
+		return entry, nil
+	}
+	isDrift, drift, err := hasDriftedFrom(ctx, observed, given, options...)
+	if err != nil {
+		return entry, err
+	}
+	if !isDrift {
+		entry.Action = PlanActionUnchanged
+		return entry, nil
+	}
+	entry.Action = PlanActionUpdate
+	entry.Diff = drift
+	return entry, nil
+}
+
+// PlanObjects returns the changes that applying the provided objects
+// would make to the cluster without mutating the cluster. Each object is
+// dry run & compared against its observed state. Errors if any are
+// collected & returned as an aggregate.
+func PlanObjects(ctx context.Context, objects []client.Object, options ...RunOption) (*Plan, error) {
+	var plan = &Plan{}
+	var finalError *multierror.Error
+	for _, obj := range objects {
+		entry, err := planObject(ctx, obj, options...)
+		if err != nil {
+			finalError = multierror.Append(finalError, errors.Wrapf(err, "plan %s", k8sutil.DescribeObj(obj)))
+			continue
+		}
+		plan.Entries = append(plan.Entries, entry)
+	}
+	return plan, finalError.ErrorOrNil()
+}
+
+// PlanYAMLs returns the changes that applying the objects found in the
+// provided file paths would make to the cluster without mutating the
+// cluster
+func PlanYAMLs(ctx context.Context, filePaths []string, options ...RunOption) (*Plan, error) {
+	objs, err := k8sutil.BuildObjectsFromYMLs(filePaths)
+	if err != nil {
+		return nil, err
+	}
+	cObjs, err := toClientObjects(objs, filePaths)
+	if err != nil {
+		return nil, err
+	}
+	return PlanObjects(ctx, cObjs, options...)
+}
+
+// PlanRunner writes the plan of the provided file paths to the provided
+// writer when run e.g. before a later step of a Job applies the files
+type PlanRunner struct {
+	FilePaths []string
+
+	// Writer receives the plan & defaults to stdout
+	Writer io.Writer
+}
+
+// compile time check to assert if the structure
+// PlanRunner implements the interface Runner
+var _ Runner = (*PlanRunner)(nil)
+
+// Run plans & prints the changes
+func (p *PlanRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if p == nil {
+		return errors.New("nil plan runner")
+	}
+	plan, err := PlanYAMLs(ctx, p.FilePaths, opts...)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if p.Writer != nil {
+		w = p.Writer
+	}
+	_, err = io.WriteString(w, plan.String())
+	return errors.Wrap(err, "failed to write plan")
+}
+
+// String describes the runner
+func (p *PlanRunner) String() string {
+	if p == nil {
+		return "plan"
+	}
+	return fmt.Sprintf("plan %s", strings.Join(p.FilePaths, ", "))
+}
@@ -0,0 +1,58 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPlanYAMLs(t *testing.T) {
+	t.Parallel()
+
+	cm := func(name, color string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Data:       map[string]string{"color": color},
+		}
+	}
+	// Note: Dry runs are not forwarded since the fake client does not
+	// support server side apply
+	cli := &recordingClient{
+		Client: fake.NewClientBuilder().WithObjects(
+			cm("plan-modified", "red"),
+			cm("plan-unchanged", "green"),
+		).Build(),
+	}
+
+	plan, err := PlanYAMLs(context.Background(), []string{"testdata/plan_configmaps.yaml"}, &RunOptions{Client: cli})
+	assert.NoError(t, err)
+	assert.Len(t, plan.Entries, 3)
+
+	var actions = map[string]PlanAction{}
+	for _, entry := range plan.Entries {
+		actions[entry.Object.GetName()] = entry.Action
+	}
+	assert.Equal(t, map[string]PlanAction{
+		"plan-new":       PlanActionCreate,
+		"plan-modified":  PlanActionUpdate,
+		"plan-unchanged": PlanActionUnchanged,
+	}, actions)
+	assert.Equal(t, 2, cli.patches, "should dry run the observed objects only")
+
+	var out bytes.Buffer
+	r := &PlanRunner{FilePaths: []string{"testdata/plan_configmaps.yaml"}, Writer: &out}
+	assert.NoError(t, r.Run(context.Background(), &RunOptions{Client: cli}))
+	assert.Contains(t, out.String(), "+ create: ns=default: name=plan-new")
+	assert.Contains(t, out.String(), "~ update: ns=default: name=plan-modified")
+	assert.Contains(t, out.String(), `"blue"`)
+	assert.Contains(t, out.String(), "Plan: 1 to create, 1 to update, 1 unchanged")
+
+	got, err := Get(context.Background(), cm("plan-new", ""), &RunOptions{Client: cli})
+	assert.Error(t, err, "should not create the new object")
+	assert.Nil(t, got)
+}
@@ -0,0 +1,228 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PortForward forwards the provided local port to the provided port of
+// the referred pod. A free local port is picked if the provided local
+// port is 0. The forwarded local port is passed to onReady once the
+// forwarding is ready. The forwarding is stopped once onReady returns
+// or the context is done.
+func PortForward(ctx context.Context, pod PodRef, podPort, localPort int, onReady func(ctx context.Context, localPort int) error, options ...RunOption) error {
+	opts, err := makeRunOptionsWithBase(options...)
+	if err != nil {
+		return err
+	}
+	if onReady == nil {
+		return errors.New("nil on ready callback")
+	}
+	cfg, err := getRestConfig(opts)
+	if err != nil {
+		return err
+	}
+	cs, err := opts.GetOrBuildClientset()
+	if err != nil {
+		return err
+	}
+
+	req := cs.CoreV1().RESTClient().
+		Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+	transport, upgrader, err := spdy.RoundTripperFor(cfg)
+	if err != nil {
+		return errors.Wrapf(err, "failed to initialise round tripper: pod %s", pod)
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(
+		dialer,
+		[]string{"localhost"},
+		[]string{fmt.Sprintf("%d:%d", localPort, podPort)},
+		stopCh,
+		readyCh,
+		io.Discard,
+		io.Discard,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to initialise port forwarder: pod %s", pod)
+	}
+
+	var errCh = make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+	// stop the forwarder & wait till its listeners are closed
+	stop := func() error {
+		close(stopCh)
+		return <-errCh
+	}
+
+	select {
+	case <-ctx.Done():
+		_ = stop()
+		return errors.Wrapf(ctx.Err(), "port forward: pod %s", pod)
+	case err := <-errCh:
+		return errors.Wrapf(err, "failed to port forward: pod %s", pod)
+	case <-readyCh:
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		_ = stop()
+		return errors.Errorf("failed to get forwarded ports: pod %s: %v", pod, err)
+	}
+
+	var callbackErr = make(chan error, 1)
+	go func() {
+		callbackErr <- onReady(ctx, int(ports[0].Local))
+	}()
+	select {
+	case <-ctx.Done():
+		_ = stop()
+		return errors.Wrapf(ctx.Err(), "port forward: pod %s", pod)
+	case err := <-errCh:
+		return errors.Wrapf(err, "port forward stopped: pod %s", pod)
+	case err := <-callbackErr:
+		if stopErr := stop(); stopErr != nil && err == nil {
+			return errors.Wrapf(stopErr, "failed to stop port forward: pod %s", pod)
+		}
+		return err
+	}
+}
+
+// resolveServicePort returns the pod & the pod's port that back the
+// provided port of the referred service. The first running pod that is
+// selected by the service is picked.
+func resolveServicePort(ctx context.Context, namespace, name string, servicePort int, options ...RunOption) (PodRef, int, error) {
+	svcObj, err := Get(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}, options...)
+	if err != nil {
+		return PodRef{}, 0, err
+	}
+	svc := svcObj.(*corev1.Service)
+	if len(svc.Spec.Selector) == 0 {
+		return PodRef{}, 0, errors.Errorf("service %s/%s has no selector", namespace, name)
+	}
+	var targetPort *intstr.IntOrString
+	for _, port := range svc.Spec.Ports {
+		if int(port.Port) == servicePort {
+			target := port.TargetPort
+			targetPort = &target
+			break
+		}
+	}
+	if targetPort == nil {
+		return PodRef{}, 0, errors.Errorf("service %s/%s does not expose port %d", namespace, name, servicePort)
+	}
+
+	listOptions := append([]RunOption{}, options...)
+	listOptions = append(listOptions, &RunOptions{
+		ListOptions: []client.ListOption{
+			client.InNamespace(namespace),
+			client.MatchingLabelsSelector{Selector: labels.SelectorFromSet(svc.Spec.Selector)},
+		},
+	})
+	listObj, err := List(ctx, &corev1.PodList{}, listOptions...)
+	if err != nil {
+		return PodRef{}, 0, err
+	}
+	for _, pod := range listObj.(*corev1.PodList).Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		ref := PodRef{Name: pod.Name, Namespace: pod.Namespace}
+		switch {
+		case targetPort.Type == intstr.Int && targetPort.IntVal == 0:
+			// target port defaults to the service port
+			return ref, servicePort, nil
+		case targetPort.Type == intstr.Int:
+			return ref, int(targetPort.IntVal), nil
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name == targetPort.StrVal {
+					return ref, int(port.ContainerPort), nil
+				}
+			}
+		}
+		return PodRef{}, 0, errors.Errorf("named port %q not found: pod %s", targetPort.StrVal, ref)
+	}
+	return PodRef{}, 0, errors.Errorf("no running pod found for service %s/%s", namespace, name)
+}
+
+// PortForwardRunner forwards a local port to a port of the referred pod
+// or service when run & invokes the provided callback with the local
+// port. The forwarding is stopped once the callback returns.
+type PortForwardRunner struct {
+	Namespace string
+
+	// PodName refers to the pod to forward to. This is ignored if
+	// ServiceName is set.
+	PodName string
+
+	// ServiceName refers to the service to forward to. The first running
+	// pod selected by the service backs the forwarding.
+	ServiceName string
+
+	// Port is the port of the pod or the service
+	Port int
+
+	// LocalPort is the local port to listen on. A free port is picked
+	// if this is not set.
+	LocalPort int
+
+	// OnReady is invoked with the local port once the forwarding is
+	// ready e.g. to hit http://localhost:<port>
+	OnReady func(ctx context.Context, localPort int) error
+}
+
+// compile time check to assert if the structure
+// PortForwardRunner implements the interface Runner
+var _ Runner = (*PortForwardRunner)(nil)
+
+// Run forwards the port till the callback returns
+func (p *PortForwardRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if p == nil {
+		return errors.New("nil port forward runner")
+	}
+	pod, port := PodRef{Name: p.PodName, Namespace: p.Namespace}, p.Port
+	if p.ServiceName != "" {
+		var err error
+		pod, port, err = resolveServicePort(ctx, p.Namespace, p.ServiceName, p.Port, opts...)
+		if err != nil {
+			return err
+		}
+	}
+	return PortForward(ctx, pod, port, p.LocalPort, p.OnReady, opts...)
+}
+
+// String describes the runner
+func (p *PortForwardRunner) String() string {
+	if p == nil {
+		return "port forward"
+	}
+	name := p.PodName
+	if p.ServiceName != "" {
+		name = "service/" + p.ServiceName
+	}
+	return fmt.Sprintf("port forward %s/%s:%d", p.Namespace, name, p.Port)
+}
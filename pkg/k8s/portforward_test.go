@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolveServicePort(t *testing.T) {
+	t.Parallel()
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "web"},
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+				{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt(9100)},
+				{Name: "admin", Port: 8081},
+			},
+		},
+	}
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "app",
+				Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	cli := fake.NewClientBuilder().WithObjects(
+		service,
+		pod("pending", corev1.PodPending),
+		pod("running", corev1.PodRunning),
+	).Build()
+
+	var scenarios = []struct {
+		name        string
+		servicePort int
+		expectPort  int
+		isErr       bool
+	}{
+		{
+			name:        "should resolve a named target port",
+			servicePort: 80,
+			expectPort:  8080,
+		},
+		{
+			name:        "should resolve a numeric target port",
+			servicePort: 9090,
+			expectPort:  9100,
+		},
+		{
+			name:        "should default the target port to the service port",
+			servicePort: 8081,
+			expectPort:  8081,
+		},
+		{
+			name:        "should error for a port not exposed by the service",
+			servicePort: 443,
+			isErr:       true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			ref, port, err := resolveServicePort(context.Background(), "default", "web", scenario.servicePort, WithClient(cli))
+			if scenario.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, PodRef{Name: "running", Namespace: "default"}, ref)
+			assert.Equal(t, scenario.expectPort, port)
+		})
+	}
+}
+
+func TestPortForwardRunnerWithNilCallback(t *testing.T) {
+	t.Parallel()
+
+	runner := &PortForwardRunner{Namespace: "default", PodName: "web", Port: 8080}
+	assert.EqualError(t, runner.Run(context.Background()), "nil on ready callback")
+}
@@ -0,0 +1,111 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// PrintYAML returns the provided object serialized as YAML. The object's
+// kind & apiVersion are set from the client-go scheme if they are missing. Managed
+// fields are left out since they are seldom useful while diagnosing.
+//
+// Note: The values of the kinds set in RunOptions.RedactKinds are redacted
+func PrintYAML(obj client.Object, options ...RunOption) (string, error) {
+	opts, err := makeRunOptionsWithBase(options...)
+	if err != nil {
+		return "", err
+	}
+	r := newRedactor(opts)
+	r.secrets = false
+	return printYAML(obj, r)
+}
+
+// printYAML returns the provided object serialized as YAML with its
+// values redacted by the provided redactor
+func printYAML(obj client.Object, r redactor) (string, error) {
+	if obj == nil {
+		return "", errors.New("nil object")
+	}
+	gvk, err := gvkForObject(obj, scheme.Scheme)
+	if err != nil {
+		return "", errors.Wrap(err, "extract gvk")
+	}
+	printed, _ := obj.DeepCopyObject().(client.Object)
+	printed.GetObjectKind().SetGroupVersionKind(gvk)
+	printed.SetManagedFields(nil)
+	printed, err = r.redact(printed)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := toUnstructuredContent(printed)
+	if err != nil {
+		return "", errors.Wrapf(err, "convert to unstructured: %s", gvk)
+	}
+	out, err := yaml.Marshal(content)
+	if err != nil {
+		return "", errors.Wrapf(err, "encode to yaml: %s", gvk)
+	}
+	return string(out), nil
+}
+
+// PrintRunner writes the observed state of the provided object as YAML
+// to the provided writer when run e.g. after a failed step of a Job
+type PrintRunner struct {
+	Object client.Object
+
+	// Writer receives the YAML & defaults to stdout
+	Writer io.Writer
+
+	// RedactSecrets when true redacts the data values of a Secret
+	//
+	// Note: The values of the kinds set in RunOptions.RedactKinds are
+	// always redacted
+	RedactSecrets bool
+}
+
+// compile time check to assert if the structure
+// PrintRunner implements the interface Runner
+var _ Runner = (*PrintRunner)(nil)
+
+// Run gets & prints the object
+func (p *PrintRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if p == nil {
+		return errors.New("nil print runner")
+	}
+	options, err := makeRunOptions(opts...)
+	if err != nil {
+		return err
+	}
+	actual, err := Get(ctx, p.Object, options)
+	if err != nil {
+		return err
+	}
+	r := newRedactor(options)
+	r.secrets = p.RedactSecrets
+	printed, err := printYAML(actual, r)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if p.Writer != nil {
+		w = p.Writer
+	}
+	_, err = io.WriteString(w, printed)
+	return errors.Wrap(err, "failed to write yaml")
+}
+
+// String describes the runner
+func (p *PrintRunner) String() string {
+	if p == nil || p.Object == nil {
+		return "print"
+	}
+	return fmt.Sprintf("print %s/%s", p.Object.GetNamespace(), p.Object.GetName())
+}
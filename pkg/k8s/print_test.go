@@ -0,0 +1,122 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPrintYAML(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "print-yaml",
+			Namespace:     "default",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "test"}},
+		},
+		Data: map[string]string{"color": "crimson"},
+	}
+	got, err := PrintYAML(cm)
+	assert.NoError(t, err)
+	assert.Contains(t, got, "apiVersion: v1\n")
+	assert.Contains(t, got, "kind: ConfigMap\n")
+	assert.Contains(t, got, "  color: crimson\n")
+	assert.NotContains(t, got, "managedFields")
+	assert.Empty(t, cm.Kind) // given is not mutated
+
+	got, err = PrintYAML(cm, WithRedactKinds(schema.GroupKind{Kind: "ConfigMap"}))
+	assert.NoError(t, err)
+	assert.Contains(t, got, "  color: <redacted hmac:")
+	assert.NotContains(t, got, "crimson")
+}
+
+func TestPrintRunner(t *testing.T) {
+	t.Parallel()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "print-runner",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{"password": []byte("s3cr3t")},
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "print-runner",
+			Namespace: "default",
+		},
+		Data: map[string]string{"token": "t0k3n"},
+	}
+	unstructuredSecret := &unstructured.Unstructured{}
+	unstructuredSecret.SetAPIVersion("v1")
+	unstructuredSecret.SetKind("Secret")
+	unstructuredSecret.SetNamespace(secret.Namespace)
+	unstructuredSecret.SetName(secret.Name)
+
+	var scenarios = []struct {
+		name          string
+		object        client.Object
+		redactSecrets bool
+		redactKinds   []schema.GroupKind
+		expected      string
+		notExpected   string
+	}{
+		{
+			name:     "should print secret data when not redacted",
+			object:   secret,
+			expected: "password: czNjcjN0",
+		},
+		{
+			name:          "should redact secret data",
+			object:        secret,
+			redactSecrets: true,
+			expected:      "password: <redacted hmac:",
+			notExpected:   "czNjcjN0",
+		},
+		{
+			name:          "should redact data of unstructured secret",
+			object:        unstructuredSecret,
+			redactSecrets: true,
+			expected:      "password: <redacted hmac:",
+			notExpected:   "czNjcjN0",
+		},
+		{
+			name:     "should print config map data when not redacted",
+			object:   cm,
+			expected: "token: t0k3n",
+		},
+		{
+			name:        "should redact data of the kinds set in the options",
+			object:      cm,
+			redactKinds: []schema.GroupKind{{Kind: "ConfigMap"}},
+			expected:    "token: <redacted hmac:",
+			notExpected: "t0k3n",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			runner := &PrintRunner{Object: scenario.object, Writer: &buf, RedactSecrets: scenario.redactSecrets}
+			cli := fake.NewClientBuilder().WithObjects(secret.DeepCopy(), cm.DeepCopy()).Build()
+			err := runner.Run(context.Background(), &RunOptions{Client: cli, RedactKinds: scenario.redactKinds})
+			assert.NoError(t, err)
+			assert.Contains(t, buf.String(), scenario.expected)
+			if scenario.notExpected != "" {
+				assert.NotContains(t, buf.String(), scenario.notExpected)
+			}
+		})
+	}
+}
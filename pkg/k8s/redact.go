@@ -0,0 +1,236 @@
+package k8s
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// secretGroupKind is the group & kind of a Secret
+var secretGroupKind = schema.GroupKind{Kind: "Secret"}
+
+// redactedFields are the top level fields whose values are redacted
+var redactedFields = []string{"data", "stringData"}
+
+// lastAppliedPath is the field path of the last applied configuration.
+// This is redacted since it holds a copy of the redacted fields.
+var lastAppliedPath = []string{"metadata", "annotations", corev1.LastAppliedConfigAnnotation}
+
+// redactKey is the key of the digests of the redacted values. The key is
+// random per process. Hence a digest can not be reversed by hashing the
+// candidate values while the same values result in the same digest
+// within a run.
+var redactKey = func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(errors.Wrap(err, "generate redact key"))
+	}
+	return key
+}()
+
+// redactor redacts the data, stringData & last applied configuration of
+// Secrets & of the provided kinds
+type redactor struct {
+	kinds  []schema.GroupKind
+	scheme *runtime.Scheme
+
+	// secrets when true redacts the Secrets irrespective of the kinds
+	secrets bool
+}
+
+// defaultRedactor redacts Secrets only. This is used by the functions
+// that do not accept RunOptions.
+var defaultRedactor = redactor{scheme: scheme.Scheme, secrets: true}
+
+// newRedactor returns a redactor of the Secrets & of the kinds set in the
+// provided options
+func newRedactor(opts *RunOptions) redactor {
+	var r = redactor{kinds: opts.RedactKinds, scheme: opts.Scheme, secrets: true}
+	if r.scheme == nil {
+		r.scheme = scheme.Scheme
+	}
+	return r
+}
+
+// isRedacted returns true if any of the provided objects is of a kind
+// that needs to be redacted
+func (r redactor) isRedacted(objs ...client.Object) bool {
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		gvk, err := gvkForObject(obj, r.scheme)
+		if err != nil {
+			continue
+		}
+		if r.secrets && gvk.GroupKind() == secretGroupKind {
+			return true
+		}
+		for _, kind := range r.kinds {
+			if gvk.GroupKind() == kind {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactValue returns a keyed digest of the provided value. The digest
+// lets a diff tell whether a value changed without revealing the value.
+func redactValue(value interface{}) string {
+	mac := hmac.New(sha256.New, redactKey)
+	_, _ = mac.Write([]byte(fmt.Sprint(value)))
+	return fmt.Sprintf("<redacted hmac:%x>", mac.Sum(nil)[:8])
+}
+
+// redactMapValues returns the provided value with its entries redacted
+// if it is a map or else the redacted value
+func redactMapValues(value interface{}) interface{} {
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return redactValue(value)
+	}
+	var redacted = make(map[string]interface{}, len(entries))
+	for key, entry := range entries {
+		redacted[key] = redactValue(entry)
+	}
+	return redacted
+}
+
+// redactAt returns the provided value with the value at the provided
+// field path redacted. The maps along the path are copied & hence the
+// provided value is not mutated.
+func redactAt(value interface{}, fields []string) interface{} {
+	if len(fields) == 0 {
+		return redactValue(value)
+	}
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	entry, found := entries[fields[0]]
+	if !found || entry == nil {
+		return value
+	}
+	var redacted = make(map[string]interface{}, len(entries))
+	for key, val := range entries {
+		redacted[key] = val
+	}
+	redacted[fields[0]] = redactAt(entry, fields[1:])
+	return redacted
+}
+
+// redactContent returns a copy of the provided content with the values
+// of its data, stringData & last applied configuration redacted
+//
+// Note: The provided content is not mutated
+func redactContent(content map[string]interface{}) map[string]interface{} {
+	if content == nil {
+		return nil
+	}
+	var redacted = make(map[string]interface{}, len(content))
+	for key, value := range content {
+		redacted[key] = value
+	}
+	for _, field := range redactedFields {
+		if value, found := redacted[field]; found && value != nil {
+			redacted[field] = redactMapValues(value)
+		}
+	}
+	redacted, _ = redactAt(redacted, lastAppliedPath).(map[string]interface{})
+	return redacted
+}
+
+// redact returns a redacted copy of the provided object if it is of a
+// kind that needs to be redacted or else the object as-is
+func (r redactor) redact(obj client.Object) (client.Object, error) {
+	if !r.isRedacted(obj) {
+		return obj, nil
+	}
+	gvk, err := gvkForObject(obj, r.scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, "extract gvk")
+	}
+	content, err := toUnstructuredContent(obj)
+	if err != nil {
+		return nil, err
+	}
+	redacted := &unstructured.Unstructured{Object: redactContent(content)}
+	redacted.SetGroupVersionKind(gvk)
+	return redacted, nil
+}
+
+// diff returns the cmp.Diff of the provided unstructured instances or
+// contents. The values are redacted if any of the provided objects is of
+// a kind that needs to be redacted.
+func (r redactor) diff(x, y interface{}, objs ...client.Object) string {
+	if !r.isRedacted(objs...) {
+		return cmp.Diff(x, y)
+	}
+	redact := func(v interface{}) interface{} {
+		switch content := v.(type) {
+		case *unstructured.Unstructured:
+			if content == nil {
+				return content
+			}
+			return &unstructured.Unstructured{Object: redactContent(content.Object)}
+		case map[string]interface{}:
+			return redactContent(content)
+		}
+		return v
+	}
+	return cmp.Diff(redact(x), redact(y))
+}
+
+// diffAtPaths is same as diff but for the values keyed by their dotted
+// field paths. Values of the paths within data, stringData & the last
+// applied configuration are redacted.
+func (r redactor) diffAtPaths(x, y map[string]interface{}, objs ...client.Object) string {
+	if !r.isRedacted(objs...) {
+		return cmp.Diff(x, y)
+	}
+	redact := func(values map[string]interface{}) map[string]interface{} {
+		var redacted = make(map[string]interface{}, len(values))
+		for path, value := range values {
+			redacted[path] = value
+			fields, err := ParseFieldPath(path)
+			if err != nil || len(fields) == 0 {
+				continue
+			}
+			for _, field := range redactedFields {
+				if fields[0] == field {
+					redacted[path] = redactMapValues(value)
+				}
+			}
+			if isPrefixPath(fields, lastAppliedPath) {
+				redacted[path] = redactAt(value, lastAppliedPath[len(fields):])
+			}
+		}
+		return redacted
+	}
+	return cmp.Diff(redact(x), redact(y))
+}
+
+// isPrefixPath returns true if the provided field path is same as or is
+// a parent of the provided full path
+func isPrefixPath(fields, full []string) bool {
+	if len(fields) > len(full) {
+		return false
+	}
+	for i := range fields {
+		if fields[i] != full[i] {
+			return false
+		}
+	}
+	return true
+}
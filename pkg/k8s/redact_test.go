@@ -0,0 +1,204 @@
+package k8s
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	observedSecretValue = "observed-s3cr3t"
+	desiredSecretValue  = "desired-s3cr3t"
+)
+
+// assertNoSecretValues asserts that the provided diff does not reveal
+// the secret values in either plain or base64 encoded form
+func assertNoSecretValues(t *testing.T, diff string) {
+	t.Helper()
+	for _, value := range []string{observedSecretValue, desiredSecretValue} {
+		assert.NotContains(t, diff, value)
+		assert.NotContains(t, diff, base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+}
+
+func TestDiffOutputRedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	secret := func(value string) *corev1.Secret {
+		return &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+			Data:       map[string][]byte{"password": []byte(value)},
+			StringData: map[string]string{"token": value},
+		}
+	}
+	// lastApplied returns the secret with its last applied configuration
+	// annotation set similar to kubectl apply
+	lastApplied := func(value string) *corev1.Secret {
+		obj := secret(value)
+		obj.Annotations = map[string]string{
+			corev1.LastAppliedConfigAnnotation: fmt.Sprintf(
+				`{"apiVersion":"v1","kind":"Secret","data":{"password":%q},"stringData":{"token":%q}}`,
+				base64.StdEncoding.EncodeToString([]byte(value)),
+				value,
+			),
+		}
+		return obj
+	}
+	unstructSecret := func(value string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Secret")
+		obj.SetName("creds")
+		obj.SetNamespace("default")
+		_ = unstructured.SetNestedStringMap(obj.Object, map[string]string{"token": value}, "stringData")
+		return obj
+	}
+
+	var scenarios = []struct {
+		name     string
+		observed client.Object
+		desired  client.Object
+		diffFn   func(observed, desired client.Object) (bool, string, error)
+	}{
+		{
+			name:     "should redact the diff of is equal",
+			observed: secret(observedSecretValue),
+			desired:  secret(desiredSecretValue),
+			diffFn:   IsEqualWithDiffOutput,
+		},
+		{
+			name:     "should redact the diff of is equal for unstructured secrets",
+			observed: unstructSecret(observedSecretValue),
+			desired:  unstructSecret(desiredSecretValue),
+			diffFn:   IsEqualWithDiffOutput,
+		},
+		{
+			name:     "should redact the diff of is strict equal",
+			observed: secret(observedSecretValue),
+			desired:  secret(desiredSecretValue),
+			diffFn:   IsStrictEqualWithDiffOutput,
+		},
+		{
+			name:     "should redact the diff of is superset of",
+			observed: secret(observedSecretValue),
+			desired:  &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"}},
+			diffFn:   IsSupersetOfWithDiffOutput,
+		},
+		{
+			name:     "should redact the last applied configuration in the diff of is strict equal",
+			observed: lastApplied(observedSecretValue),
+			desired:  lastApplied(desiredSecretValue),
+			diffFn:   IsStrictEqualWithDiffOutput,
+		},
+		{
+			name:     "should redact the last applied configuration in the diff of is superset of",
+			observed: lastApplied(observedSecretValue),
+			desired: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name:        "creds",
+				Namespace:   "default",
+				Annotations: lastApplied(desiredSecretValue).Annotations,
+			}},
+			diffFn: IsSupersetOfWithDiffOutput,
+		},
+		{
+			name:     "should redact the last applied configuration in the diff of is equal at paths",
+			observed: lastApplied(observedSecretValue),
+			desired:  lastApplied(desiredSecretValue),
+			diffFn: func(observed, desired client.Object) (bool, string, error) {
+				return IsEqualAtPaths(observed, desired, "metadata.annotations")
+			},
+		},
+		{
+			name:     "should redact the diff of is equal at paths",
+			observed: secret(observedSecretValue),
+			desired:  secret(desiredSecretValue),
+			diffFn: func(observed, desired client.Object) (bool, string, error) {
+				return IsEqualAtPaths(observed, desired, "data.password", "stringData")
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			isEqual, diff, err := scenario.diffFn(scenario.observed, scenario.desired)
+			assert.NoError(t, err)
+			assert.False(t, isEqual)
+			assert.Contains(t, diff, "<redacted hmac:")
+			assertNoSecretValues(t, diff)
+		})
+	}
+}
+
+func TestRedactValue(t *testing.T) {
+	t.Parallel()
+
+	unkeyed := sha256.Sum256([]byte(observedSecretValue))
+	redacted := redactValue(observedSecretValue)
+	assert.Equal(t, redacted, redactValue(observedSecretValue), "same value should have the same digest")
+	assert.NotEqual(t, redacted, redactValue(desiredSecretValue))
+	assert.NotContains(t, redacted, fmt.Sprintf("%x", unkeyed[:8]), "digest should be keyed")
+}
+
+func TestAssertWithRedactKinds(t *testing.T) {
+	t.Parallel()
+
+	cm := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+			Data:       map[string]string{"password": value},
+		}
+	}
+	cli := fake.NewClientBuilder().WithObjects(cm(observedSecretValue)).Build()
+
+	var scenarios = []struct {
+		name        string
+		redactKinds []schema.GroupKind
+		isRedacted  bool
+	}{
+		{
+			name: "should not redact config maps by default",
+		},
+		{
+			name:        "should redact the configured kinds",
+			redactKinds: []schema.GroupKind{{Kind: "ConfigMap"}},
+			isRedacted:  true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, diff, err := Assert(
+				context.Background(),
+				cm(desiredSecretValue),
+				AssertOptions{AssertType: AssertTypeIsEquals},
+				&RunOptions{Client: cli},
+				WithRedactKinds(scenario.redactKinds...),
+			)
+			assert.NoError(t, err)
+			assert.False(t, result)
+			if scenario.isRedacted {
+				assertNoSecretValues(t, diff)
+			} else {
+				assert.Contains(t, diff, observedSecretValue)
+				assert.Contains(t, diff, desiredSecretValue)
+			}
+		})
+	}
+}
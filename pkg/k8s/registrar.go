@@ -0,0 +1,249 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BaseRegistrar is an in-memory Registrar that retains the order in
+// which the entries were registered
+type BaseRegistrar struct {
+	EntityType EntityType
+
+	mu             sync.RWMutex
+	Store          map[Key]Runner
+	orderedEntries []Key
+}
+
+// compile time check to assert if the structure
+// BaseRegistrar implements the interface Registrar
+var _ Registrar = (*BaseRegistrar)(nil)
+
+// NewGarbageCollector returns a new registrar that stores the runners
+// that clean up the resources created during a run
+func NewGarbageCollector() *BaseRegistrar {
+	return &BaseRegistrar{
+		EntityType: EntityTypeGarbageCollector,
+		Store:      make(map[Key]Runner),
+	}
+}
+
+// Get fetches the Runner instance corresponding to the provided key
+func (r *BaseRegistrar) Get(key Key) Runner {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.Store[key]
+}
+
+// GetKeys fetches all the keys in the order of their registration
+func (r *BaseRegistrar) GetKeys() []Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]Key, len(r.orderedEntries))
+	copy(keys, r.orderedEntries)
+	return keys
+}
+
+// GetRunners fetches all the runners in the order of their registration
+func (r *BaseRegistrar) GetRunners() []Runner {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	runners := make([]Runner, 0, len(r.orderedEntries))
+	for _, key := range r.orderedEntries {
+		runners = append(runners, r.Store[key])
+	}
+	return runners
+}
+
+// Type of entities stored in this registrar
+func (r *BaseRegistrar) Type() EntityType {
+	return r.EntityType
+}
+
+// Register stores the provided runner. The runner must implement
+// RegistrarEntry & its type must match the registrar's type.
+func (r *BaseRegistrar) Register(s Runner) error {
+	entry, ok := s.(RegistrarEntry)
+	if !ok {
+		return errors.Errorf("invalid runner %T: does not implement RegistrarEntry", s)
+	}
+	if entry.Type() != r.EntityType {
+		return errors.Errorf("invalid runner type: want %q got %q", r.EntityType, entry.Type())
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Store == nil {
+		r.Store = make(map[Key]Runner)
+	}
+	if _, found := r.Store[entry.Key()]; found {
+		return errors.Errorf("runner %q is already registered", entry.Key())
+	}
+	r.Store[entry.Key()] = s
+	r.orderedEntries = append(r.orderedEntries, entry.Key())
+	return nil
+}
+
+// IsRegistered returns true if the provided key was registered earlier
+func (r *BaseRegistrar) IsRegistered(key Key) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, found := r.Store[key]
+	return found
+}
+
+// Reset removes all the registered entries
+func (r *BaseRegistrar) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Store = make(map[Key]Runner)
+	r.orderedEntries = nil
+}
+
+// deregister removes the entries of the provided keys
+func (r *BaseRegistrar) deregister(keys []Key) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var removed = make(map[Key]bool, len(keys))
+	for _, key := range keys {
+		delete(r.Store, key)
+		removed[key] = true
+	}
+	var retained = make([]Key, 0, len(r.orderedEntries))
+	for _, key := range r.orderedEntries {
+		if !removed[key] {
+			retained = append(retained, key)
+		}
+	}
+	r.orderedEntries = retained
+}
+
+// Teardown runs all the runners of the provided registrar in the
+// order of their registration irrespective of their failures. Errors
+// if any are returned as an aggregate.
+func Teardown(ctx context.Context, registrar Registrar, opts ...RunOption) error {
+	if registrar == nil {
+		return errors.New("nil registrar")
+	}
+	var finalError *multierror.Error
+	for _, runner := range registrar.GetRunners() {
+		if err := runner.Run(ctx, opts...); err != nil {
+			finalError = multierror.Append(finalError, err)
+		}
+	}
+	return finalError.ErrorOrNil()
+}
+
+// TeardownAndReset runs all the runners of the provided registrar &
+// then removes them from the registrar
+//
+// Note: This is the recommended way to clean up after a run
+func TeardownAndReset(ctx context.Context, registrar *BaseRegistrar, opts ...RunOption) error {
+	if registrar == nil {
+		return errors.New("nil registrar")
+	}
+	defer registrar.Reset()
+	return Teardown(ctx, registrar, opts...)
+}
+
+// TeardownByKeyPrefix runs the runners of the provided registrar whose
+// keys start with the provided prefix in the order of their registration
+// & then removes them from the registrar. Other runners are retained
+// e.g. those of the suites that use a different namespace prefix.
+//
+// Note: GetKeys returns an ordered snapshot of the keys to pick from
+func TeardownByKeyPrefix(ctx context.Context, registrar *BaseRegistrar, prefix string, opts ...RunOption) error {
+	if registrar == nil {
+		return errors.New("nil registrar")
+	}
+	var keys []Key
+	for _, key := range registrar.GetKeys() {
+		if strings.HasPrefix(string(key), prefix) {
+			keys = append(keys, key)
+		}
+	}
+	defer registrar.deregister(keys)
+
+	var finalError *multierror.Error
+	for _, key := range keys {
+		runner := registrar.Get(key)
+		if runner == nil {
+			continue
+		}
+		if err := runner.Run(ctx, opts...); err != nil {
+			finalError = multierror.Append(finalError, err)
+		}
+	}
+	return finalError.ErrorOrNil()
+}
+
+// registerForGarbageCollection registers a DeleteRunner of the provided
+// object in the garbage collector set in the provided options if any.
+// An object that is registered already is left as-is.
+func registerForGarbageCollection(opts *RunOptions, created client.Object) error {
+	if opts == nil || opts.GCRegistrar == nil || created == nil {
+		return nil
+	}
+	entry := &DeleteRunner{Object: created}
+	if opts.GCRegistrar.IsRegistered(entry.Key()) {
+		return nil
+	}
+	if err := opts.GCRegistrar.Register(entry); err != nil {
+		return errors.Wrapf(err, "register for garbage collection: %s", k8sutil.DescribeObj(created))
+	}
+	return nil
+}
+
+// DeleteRunner is a garbage collector entry that deletes the provided
+// object when run. An object that is not found is considered as deleted.
+//
+// Note: Its key is of the form namespace:name:gvk
+type DeleteRunner struct {
+	Object client.Object
+}
+
+// compile time check to assert if the structure
+// DeleteRunner implements the interface Runner
+var _ Runner = (*DeleteRunner)(nil)
+
+// compile time check to assert if the structure
+// DeleteRunner implements the interface RegistrarEntry
+var _ RegistrarEntry = (*DeleteRunner)(nil)
+
+// Run deletes the object
+func (d *DeleteRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if d == nil || d.Object == nil {
+		return errors.New("nil delete runner")
+	}
+	err := Delete(ctx, d.Object, opts...)
+	if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+		return errors.Wrapf(err, "teardown %s", k8sutil.DescribeObj(d.Object))
+	}
+	return nil
+}
+
+// Key identifies the object to be deleted
+func (d *DeleteRunner) Key() Key {
+	return Key(k8sutil.ObjKey(d.Object))
+}
+
+// Type returns the garbage collector entity type
+func (d *DeleteRunner) Type() EntityType {
+	return EntityTypeGarbageCollector
+}
+
+// String describes the runner
+func (d *DeleteRunner) String() string {
+	if d == nil || d.Object == nil {
+		return "delete"
+	}
+	return fmt.Sprintf("delete %s/%s", d.Object.GetNamespace(), d.Object.GetName())
+}
@@ -0,0 +1,143 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// gcRunner is a garbage collector entry that counts its invocations
+type gcRunner struct {
+	countingRunner
+	key Key
+}
+
+func (r *gcRunner) Key() Key {
+	return r.key
+}
+
+func (r *gcRunner) Type() EntityType {
+	return EntityTypeGarbageCollector
+}
+
+func TestBaseRegistrarRegister(t *testing.T) {
+	t.Parallel()
+
+	gc := NewGarbageCollector()
+	assert.NoError(t, gc.Register(&gcRunner{key: "first"}))
+	assert.NoError(t, gc.Register(&gcRunner{key: "second"}))
+	assert.Error(t, gc.Register(&gcRunner{key: "first"}), "duplicate key")
+	assert.Error(t, gc.Register(&countingRunner{}), "not a registrar entry")
+	assert.Error(t, gc.Register(Noop()), "type mismatch")
+
+	assert.Equal(t, []Key{"first", "second"}, gc.GetKeys())
+	assert.True(t, gc.IsRegistered("second"))
+}
+
+func TestTeardownAndReset(t *testing.T) {
+	t.Parallel()
+
+	gc := NewGarbageCollector()
+	first := &gcRunner{key: "first"}
+	second := &gcRunner{key: "second"}
+	assert.NoError(t, gc.Register(first))
+	assert.NoError(t, gc.Register(second))
+
+	err := TeardownAndReset(context.Background(), gc)
+	assert.NoError(t, err)
+	assert.Empty(t, gc.GetKeys())
+
+	third := &gcRunner{key: "third"}
+	assert.NoError(t, gc.Register(third))
+	err = TeardownAndReset(context.Background(), gc)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, first.count, "first batch should be torn down once")
+	assert.Equal(t, 1, second.count, "first batch should be torn down once")
+	assert.Equal(t, 1, third.count)
+}
+
+func TestTeardownByKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var objects []client.Object
+	for _, ns := range []string{"team-a", "team-b"} {
+		for _, name := range []string{"first", "second"} {
+			objects = append(objects, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			})
+		}
+	}
+	cli := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+	gc := NewGarbageCollector()
+	for _, obj := range objects {
+		assert.NoError(t, gc.Register(&DeleteRunner{Object: obj}))
+	}
+	keys := gc.GetKeys()
+	assert.Len(t, keys, 4)
+
+	err := TeardownByKeyPrefix(ctx, gc, "team-a:", WithClient(cli))
+	assert.NoError(t, err)
+	assert.Equal(t, keys[2:], gc.GetKeys(), "entries of other namespaces should be retained")
+
+	var remaining corev1.ConfigMapList
+	assert.NoError(t, cli.List(ctx, &remaining))
+	var got []string
+	for _, item := range remaining.Items {
+		got = append(got, item.Namespace+"/"+item.Name)
+	}
+	assert.ElementsMatch(t, []string{"team-b/first", "team-b/second"}, got)
+
+	// tearing down again is a no-op
+	assert.NoError(t, TeardownByKeyPrefix(ctx, gc, "team-a:", WithClient(cli)))
+	assert.Len(t, gc.GetKeys(), 2)
+}
+
+func TestCreateWithGCRegistrar(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}},
+	).Build()
+	gc := NewGarbageCollector()
+	other := NewGarbageCollector()
+
+	created := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: "default"}}
+	_, err := Create(ctx, created, WithClient(cli), WithGCRegistrar(gc))
+	assert.NoError(t, err)
+
+	upserted := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "upserted", Namespace: "default"}}
+	_, err = Upsert(ctx, upserted, WithClient(cli), WithGCRegistrar(gc))
+	assert.NoError(t, err)
+
+	// objects that are not created are not registered
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+		Data:       map[string]string{"color": "red"},
+	}
+	_, err = Upsert(ctx, existing, WithClient(cli), WithGCRegistrar(gc))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Key{
+		Key(k8sutil.ObjKey(created)),
+		Key(k8sutil.ObjKey(upserted)),
+	}, gc.GetKeys())
+	assert.Empty(t, other.GetKeys(), "registrars should be independent")
+
+	assert.NoError(t, TeardownAndReset(ctx, gc, WithClient(cli)))
+	var remaining corev1.ConfigMapList
+	assert.NoError(t, cli.List(ctx, &remaining))
+	if assert.Len(t, remaining.Items, 1) {
+		assert.Equal(t, "existing", remaining.Items[0].Name)
+	}
+}
@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RetryPolicy defines the retries of the API calls that fail with a
+// transient error i.e. a conflict, a server timeout or too many requests.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first
+	// one. There are no retries if this is less than 2.
+	MaxAttempts int
+
+	// Backoff is the wait before the first retry. It is doubled after
+	// each retry.
+	Backoff time.Duration
+
+	// MutateOnConflict when set is invoked by Update with the latest
+	// state of the object after a conflict. It is expected to re-apply
+	// the desired changes to this object which is then updated.
+	//
+	// Note: Update does not retry on conflicts if this is not set since
+	// the stale object would conflict again
+	MutateOnConflict func(latest client.Object) error
+}
+
+// isTransientError returns true if the provided error may succeed when
+// the API call is retried
+func isTransientError(err error) bool {
+	err = errors.Cause(err)
+	return apierrors.IsConflict(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTooManyRequests(err)
+}
+
+// retryOnTransientError invokes the provided call till it succeeds, its
+// error is not retryable or the attempts of the policy are exhausted.
+// The call receives the error of the previous attempt if any. Errors
+// are retryable if they are transient unless retryable is set.
+func retryOnTransientError(ctx context.Context, policy *RetryPolicy, retryable func(error) bool, call func(lastErr error) error) error {
+	if retryable == nil {
+		retryable = isTransientError
+	}
+	err := call(nil)
+	if policy == nil {
+		return err
+	}
+	backoff := policy.Backoff
+	for attempt := 1; attempt < policy.MaxAttempts && err != nil && retryable(err); attempt++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(err, "retry: %s", ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
+		err = call(err)
+	}
+	return err
+}
+
+type withRetryPolicy struct {
+	policy *RetryPolicy
+}
+
+// ApplyTo sets the retry policy in the provided target
+func (o withRetryPolicy) ApplyTo(target RunOption) error {
+	targetObj, err := toRunOptions(target)
+	if err != nil {
+		return err
+	}
+	targetObj.RetryPolicy = o.policy
+	return nil
+}
+
+// WithRetryPolicy returns an option that retries the API calls that fail
+// with a transient error
+func WithRetryPolicy(policy *RetryPolicy) RunOption {
+	return withRetryPolicy{policy: policy}
+}
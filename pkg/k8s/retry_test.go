@@ -0,0 +1,172 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// flakyClient fails the first failures number of Get & Update calls
+// with the configured error
+type flakyClient struct {
+	client.Client
+	failures int
+	err      error
+	calls    int
+}
+
+func (c *flakyClient) fail() error {
+	c.calls++
+	if c.calls <= c.failures {
+		return c.err
+	}
+	return nil
+}
+
+func (c *flakyClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if err := c.fail(); err != nil {
+		return err
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *flakyClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.fail(); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	resource := schema.GroupResource{Resource: "configmaps"}
+	var scenarios = []struct {
+		name        string
+		err         error
+		policy      *RetryPolicy
+		isErr       bool
+		expectCalls int
+	}{
+		{
+			name:        "should not retry without a policy",
+			err:         apierrors.NewServerTimeout(resource, "get", 1),
+			isErr:       true,
+			expectCalls: 1,
+		},
+		{
+			name:        "should retry a server timeout",
+			err:         apierrors.NewServerTimeout(resource, "get", 1),
+			policy:      &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+			expectCalls: 3,
+		},
+		{
+			name:        "should retry too many requests",
+			err:         apierrors.NewTooManyRequests("slow down", 1),
+			policy:      &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+			expectCalls: 3,
+		},
+		{
+			name:        "should give up after max attempts",
+			err:         apierrors.NewServerTimeout(resource, "get", 1),
+			policy:      &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+			isErr:       true,
+			expectCalls: 2,
+		},
+		{
+			name:        "should not retry a non transient error",
+			err:         apierrors.NewForbidden(resource, "cm", errors.New("denied")),
+			policy:      &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+			isErr:       true,
+			expectCalls: 1,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "retry", Namespace: "default"}}
+			cli := &flakyClient{
+				Client:   fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build(),
+				failures: 2,
+				err:      scenario.err,
+			}
+			_, err := Get(context.Background(), cm, &RunOptions{Client: cli, RetryPolicy: scenario.policy})
+			if scenario.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, scenario.expectCalls, cli.calls)
+		})
+	}
+}
+
+func TestUpdateWithRetryPolicyOnConflict(t *testing.T) {
+	t.Parallel()
+
+	resource := schema.GroupResource{Resource: "configmaps"}
+	var scenarios = []struct {
+		name        string
+		mutate      bool
+		isErr       bool
+		expectColor string
+	}{
+		{
+			name:  "should not retry a conflict without a mutation",
+			isErr: true,
+		},
+		{
+			name:        "should re-apply the mutation to the latest state on conflict",
+			mutate:      true,
+			expectColor: "blue",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "retry-update", Namespace: "default"},
+				Data:       map[string]string{"color": "red"},
+			}
+			fakeClient := fake.NewClientBuilder().WithObjects(cm.DeepCopy()).Build()
+			desired := &corev1.ConfigMap{}
+			assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(cm), desired))
+			desired.Data["color"] = "blue"
+
+			policy := &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+			if scenario.mutate {
+				policy.MutateOnConflict = func(latest client.Object) error {
+					latest.(*corev1.ConfigMap).Data["color"] = "blue"
+					return nil
+				}
+			}
+			cli := &flakyClient{
+				Client:   fakeClient,
+				failures: 1,
+				err:      apierrors.NewConflict(resource, cm.Name, errors.New("stale")),
+			}
+			got, err := Update(context.Background(), desired, &RunOptions{Client: cli, RetryPolicy: policy})
+			if scenario.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectColor, got.(*corev1.ConfigMap).Data["color"])
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// scaler gets & updates the scale subresource of an object
+type scaler interface {
+	GetScale(ctx context.Context, name string, opts metav1.GetOptions) (*autoscalingv1.Scale, error)
+	UpdateScale(ctx context.Context, name string, scale *autoscalingv1.Scale, opts metav1.UpdateOptions) (*autoscalingv1.Scale, error)
+}
+
+// scalerFor returns the scaler of the provided kind in the provided
+// namespace
+func scalerFor(cs kubernetes.Interface, gk schema.GroupKind, namespace string) (scaler, error) {
+	switch gk {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"}:
+		return cs.AppsV1().Deployments(namespace), nil
+	case schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		return cs.AppsV1().StatefulSets(namespace), nil
+	case schema.GroupKind{Group: "apps", Kind: "ReplicaSet"}:
+		return cs.AppsV1().ReplicaSets(namespace), nil
+	}
+	return nil, errors.Errorf("scale is not supported for %s", gk)
+}
+
+// Scale sets the replicas of the provided Deployment, StatefulSet or
+// ReplicaSet via its scale subresource & returns the updated scale
+func Scale(ctx context.Context, obj client.Object, replicas int32, options ...RunOption) (client.Object, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, errors.New("nil object")
+	}
+	gvk, err := apiutil.GVKForObject(obj, opts.Scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, "extract gvk")
+	}
+	cs, err := opts.GetOrBuildClientset()
+	if err != nil {
+		return nil, err
+	}
+	s, err := scalerFor(cs, gvk.GroupKind(), obj.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	scale, err := s.GetScale(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get scale")
+	}
+	scale.Spec.Replicas = replicas
+	updated, err := s.UpdateScale(ctx, obj.GetName(), scale, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scale")
+	}
+	invalidateCache(opts, obj)
+	return updated, nil
+}
+
+// ScaleRunner scales the provided object to the provided replicas when
+// run
+type ScaleRunner struct {
+	Object   client.Object
+	Replicas int32
+}
+
+// compile time check to assert if the structure
+// ScaleRunner implements the interface Runner
+var _ Runner = (*ScaleRunner)(nil)
+
+// Run scales the object
+func (s *ScaleRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if s == nil {
+		return errors.New("nil scale runner")
+	}
+	_, err := Scale(ctx, s.Object, s.Replicas, opts...)
+	return err
+}
+
+// String describes the runner
+func (s *ScaleRunner) String() string {
+	if s == nil || s.Object == nil {
+		return "scale"
+	}
+	return fmt.Sprintf("scale %s/%s to %d", s.Object.GetNamespace(), s.Object.GetName(), s.Replicas)
+}
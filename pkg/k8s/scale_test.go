@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScalerFor(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name    string
+		gk      schema.GroupKind
+		isError bool
+	}{
+		{
+			name: "should support deployment",
+			gk:   schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		},
+		{
+			name: "should support statefulset",
+			gk:   schema.GroupKind{Group: "apps", Kind: "StatefulSet"},
+		},
+		{
+			name: "should support replicaset",
+			gk:   schema.GroupKind{Group: "apps", Kind: "ReplicaSet"},
+		},
+		{
+			name:    "should fail for kind without scale",
+			gk:      schema.GroupKind{Kind: "ConfigMap"},
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := scalerFor(fake.NewSimpleClientset(), scenario.gk, "default")
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, got)
+			}
+		})
+	}
+}
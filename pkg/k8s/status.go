@@ -0,0 +1,866 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+	"github.com/simplekube/kit/pkg/util"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// This file exposes assertions that are evaluated against the state
+// of resources observed in the cluster
+
+// toUnstructuredContent converts the provided object to its
+// unstructured representation
+func toUnstructuredContent(obj client.Object) (map[string]interface{}, error) {
+	if obj == nil {
+		return nil, errors.New("nil object")
+	}
+	if un, ok := obj.(*unstructured.Unstructured); ok {
+		return un.UnstructuredContent(), nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert to unstructured")
+	}
+	return content, nil
+}
+
+// addressFromStatus returns the first address found in the status of
+// the provided object
+//
+// Note: Both status.loadBalancer.ingress[] (Ingress & Service) as well
+// as status.addresses[] (Gateway) are looked up
+func addressFromStatus(obj map[string]interface{}) (string, error) {
+	lbIngresses, _, err := unstructured.NestedSlice(obj, "status", "loadBalancer", "ingress")
+	if err != nil {
+		return "", errors.Wrap(err, "read status.loadBalancer.ingress")
+	}
+	for _, item := range lbIngresses {
+		lbIngress, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ip, _ := lbIngress["ip"].(string); ip != "" {
+			return ip, nil
+		}
+		if hostname, _ := lbIngress["hostname"].(string); hostname != "" {
+			return hostname, nil
+		}
+	}
+
+	addresses, _, err := unstructured.NestedSlice(obj, "status", "addresses")
+	if err != nil {
+		return "", errors.Wrap(err, "read status.addresses")
+	}
+	for _, item := range addresses {
+		address, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, _ := address["value"].(string); value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// GetAssignedAddress returns the ip or hostname assigned to the provided
+// Ingress, Service of type LoadBalancer or Gateway. An empty address is
+// returned if none has been assigned yet.
+func GetAssignedAddress(ctx context.Context, given client.Object, options ...RunOption) (string, error) {
+	actual, err := Get(ctx, given, options...)
+	if err != nil {
+		return "", err
+	}
+	content, err := toUnstructuredContent(actual)
+	if err != nil {
+		return "", err
+	}
+	return addressFromStatus(content)
+}
+
+// AssertHasAssignedAddress returns true if the provided Ingress, Service
+// of type LoadBalancer or Gateway has an ip or hostname assigned to it
+//
+// Note: The optional onAddress callback is invoked with the assigned
+// address when the assertion succeeds
+func AssertHasAssignedAddress(ctx context.Context, given client.Object, onAddress func(address string), options ...RunOption) (result bool, diff string, err error) {
+	address, err := GetAssignedAddress(ctx, given, options...)
+	if err != nil {
+		return false, "", err
+	}
+	if address == "" {
+		return false, "address is not assigned", nil
+	}
+	if onAddress != nil {
+		onAddress(address)
+	}
+	return true, "", nil
+}
+
+// toTypedObject converts the provided object into the provided
+// typed destination
+func toTypedObject(obj client.Object, dest interface{}) error {
+	content, err := toUnstructuredContent(obj)
+	if err != nil {
+		return err
+	}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, dest)
+	if err != nil {
+		return errors.Wrapf(err, "convert to %T", dest)
+	}
+	return nil
+}
+
+// isDaemonSetReady returns true if the daemonset's latest generation is
+// observed & its pods are scheduled & ready on all the eligible nodes
+func isDaemonSetReady(ds *appsv1.DaemonSet) (bool, string) {
+	if ds.Status.ObservedGeneration < ds.Generation {
+		return false, fmt.Sprintf(
+			"want observed generation %d got %d",
+			ds.Generation, ds.Status.ObservedGeneration,
+		)
+	}
+	if ds.Status.CurrentNumberScheduled != ds.Status.DesiredNumberScheduled {
+		return false, fmt.Sprintf(
+			"want %d scheduled pods got %d",
+			ds.Status.DesiredNumberScheduled, ds.Status.CurrentNumberScheduled,
+		)
+	}
+	if ds.Status.NumberReady != ds.Status.DesiredNumberScheduled {
+		return false, fmt.Sprintf(
+			"want %d ready pods got %d",
+			ds.Status.DesiredNumberScheduled, ds.Status.NumberReady,
+		)
+	}
+	if ds.Status.NumberUnavailable != 0 {
+		return false, fmt.Sprintf("want 0 unavailable pods got %d", ds.Status.NumberUnavailable)
+	}
+	return true, ""
+}
+
+// AssertDaemonSetReady returns true if the provided daemonset has its
+// pods scheduled & ready on all the eligible nodes
+func AssertDaemonSetReady(ctx context.Context, given client.Object, options ...RunOption) (result bool, diff string, err error) {
+	actual, err := Get(ctx, given, options...)
+	if err != nil {
+		return false, "", err
+	}
+	var ds appsv1.DaemonSet
+	if err := toTypedObject(actual, &ds); err != nil {
+		return false, "", err
+	}
+	result, diff = isDaemonSetReady(&ds)
+	return result, diff, nil
+}
+
+// podsPerNode returns the number of provided pods scheduled on each node
+//
+// Note: Pods that are not scheduled yet are not considered
+func podsPerNode(pods []corev1.Pod) map[string]int {
+	var distribution = make(map[string]int)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		distribution[pod.Spec.NodeName]++
+	}
+	return distribution
+}
+
+// describeDistribution returns a string format of the provided
+// distribution sorted by node name
+func describeDistribution(distribution map[string]int) string {
+	var nodes = make([]string, 0, len(distribution))
+	for node := range distribution {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var entries = make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		entries = append(entries, fmt.Sprintf("%s=%d", node, distribution[node]))
+	}
+	return strings.Join(entries, ", ")
+}
+
+// AssertPodSpread returns true if the pods matching the provided list
+// options are scheduled on at least the provided number of distinct
+// nodes. The actual distribution of pods per node is returned as the
+// diff when the assertion fails.
+func AssertPodSpread(ctx context.Context, minDistinctNodes int, listOptions []client.ListOption, options ...RunOption) (result bool, diff string, err error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return false, "", err
+	}
+	var pods corev1.PodList
+	err = opts.Client.List(ctx, &pods, listOptions...)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to list pods")
+	}
+	distribution := podsPerNode(pods.Items)
+	if len(distribution) < minDistinctNodes {
+		return false, fmt.Sprintf(
+			"want pods on at least %d distinct nodes got %d: [%s]",
+			minDistinctNodes, len(distribution), describeDistribution(distribution),
+		), nil
+	}
+	return true, "", nil
+}
+
+// DeploymentRevisionAnnotation is the annotation set by the deployment
+// controller against a Deployment & its ReplicaSets to track the revision
+const DeploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// isRevisionAdvanced returns true if the current revision is greater
+// than the previous revision
+//
+// Note: An empty previous revision is considered as revision 0
+func isRevisionAdvanced(current, previous string) (bool, error) {
+	if current == "" {
+		return false, nil
+	}
+	currentRev, err := strconv.ParseInt(current, 10, 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid current revision %q", current)
+	}
+	var previousRev int64
+	if previous != "" {
+		previousRev, err = strconv.ParseInt(previous, 10, 64)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid previous revision %q", previous)
+		}
+	}
+	return currentRev > previousRev, nil
+}
+
+// findReplicaSetForRevision returns the replicaset owned by the provided
+// owner that corresponds to the provided revision
+func findReplicaSetForRevision(replicaSets []appsv1.ReplicaSet, owner metav1.Object, revision string) *appsv1.ReplicaSet {
+	for idx := range replicaSets {
+		rs := &replicaSets[idx]
+		if !metav1.IsControlledBy(rs, owner) {
+			continue
+		}
+		if rs.GetAnnotations()[DeploymentRevisionAnnotation] == revision {
+			return rs
+		}
+	}
+	return nil
+}
+
+// listReplicaSetsOfDeployment lists the replicasets that match the
+// selector of the provided deployment
+func listReplicaSetsOfDeployment(ctx context.Context, opts *RunOptions, deploy *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid deployment selector")
+	}
+	var replicaSets appsv1.ReplicaSetList
+	err = opts.Client.List(
+		ctx,
+		&replicaSets,
+		client.InNamespace(deploy.GetNamespace()),
+		client.MatchingLabelsSelector{Selector: selector},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list replicasets")
+	}
+	return replicaSets.Items, nil
+}
+
+// isReplicaSetAvailable returns true if all the desired replicas of the
+// provided replicaset are ready & available
+func isReplicaSetAvailable(rs *appsv1.ReplicaSet) (bool, string) {
+	var desired int32 = 1
+	if rs.Spec.Replicas != nil {
+		desired = *rs.Spec.Replicas
+	}
+	if rs.Status.ReadyReplicas != desired {
+		return false, fmt.Sprintf("want %d ready replicas got %d", desired, rs.Status.ReadyReplicas)
+	}
+	if rs.Status.AvailableReplicas != desired {
+		return false, fmt.Sprintf("want %d available replicas got %d", desired, rs.Status.AvailableReplicas)
+	}
+	return true, ""
+}
+
+// AssertNewRevisionReady returns true if the provided deployment has
+// rolled out a revision newer than the provided previous revision & the
+// replicaset corresponding to this new revision is fully available
+//
+// Note: The previous revision can be captured before changing the pod
+// template by reading the deployment.kubernetes.io/revision annotation
+func AssertNewRevisionReady(ctx context.Context, given client.Object, previousRevision string, options ...RunOption) (result bool, diff string, err error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return false, "", err
+	}
+	actual, err := Get(ctx, given, options...)
+	if err != nil {
+		return false, "", err
+	}
+	var deploy appsv1.Deployment
+	if err := toTypedObject(actual, &deploy); err != nil {
+		return false, "", err
+	}
+
+	revision := deploy.GetAnnotations()[DeploymentRevisionAnnotation]
+	isAdvanced, err := isRevisionAdvanced(revision, previousRevision)
+	if err != nil {
+		return false, "", err
+	}
+	if !isAdvanced {
+		return false, fmt.Sprintf("want revision newer than %q got %q", previousRevision, revision), nil
+	}
+
+	replicaSets, err := listReplicaSetsOfDeployment(ctx, opts, &deploy)
+	if err != nil {
+		return false, "", err
+	}
+	rs := findReplicaSetForRevision(replicaSets, &deploy, revision)
+	if rs == nil {
+		return false, fmt.Sprintf("replicaset for revision %q is not found", revision), nil
+	}
+	result, diff = isReplicaSetAvailable(rs)
+	return result, diff, nil
+}
+
+// GetPodTemplateHash returns the pod-template-hash label of the replicaset
+// that corresponds to the current revision of the provided deployment
+//
+// Note: An error is returned if the deployment's revision annotation or
+// the replicaset's pod-template-hash label is not set
+func GetPodTemplateHash(ctx context.Context, given client.Object, options ...RunOption) (string, error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return "", err
+	}
+	actual, err := Get(ctx, given, options...)
+	if err != nil {
+		return "", err
+	}
+	var deploy appsv1.Deployment
+	if err := toTypedObject(actual, &deploy); err != nil {
+		return "", err
+	}
+	replicaSets, err := listReplicaSetsOfDeployment(ctx, opts, &deploy)
+	if err != nil {
+		return "", err
+	}
+	revision := deploy.GetAnnotations()[DeploymentRevisionAnnotation]
+	if revision == "" {
+		return "", errors.Errorf(
+			"annotation %s is not set: deployment %s/%s",
+			DeploymentRevisionAnnotation, deploy.GetNamespace(), deploy.GetName(),
+		)
+	}
+	rs := findReplicaSetForRevision(replicaSets, &deploy, revision)
+	if rs == nil {
+		return "", errors.Errorf("replicaset for revision %q is not found", revision)
+	}
+	hash := rs.GetLabels()[appsv1.DefaultDeploymentUniqueLabelKey]
+	if hash == "" {
+		return "", errors.Errorf(
+			"label %s is not set: replicaset %s/%s",
+			appsv1.DefaultDeploymentUniqueLabelKey, rs.GetNamespace(), rs.GetName(),
+		)
+	}
+	return hash, nil
+}
+
+// AssertPodTemplateHash returns true if the pod-template-hash of the
+// provided deployment's current replicaset has changed from the provided
+// hash when expectChanged is true or is same as the provided hash when
+// expectChanged is false
+//
+// Note: The previous hash can be captured via GetPodTemplateHash
+func AssertPodTemplateHash(ctx context.Context, given client.Object, previousHash string, expectChanged bool, options ...RunOption) (result bool, diff string, err error) {
+	if previousHash == "" {
+		return false, "", errors.New("empty previous hash")
+	}
+	hash, err := GetPodTemplateHash(ctx, given, options...)
+	if err != nil {
+		return false, "", err
+	}
+	isChanged := hash != previousHash
+	if isChanged != expectChanged {
+		return false, fmt.Sprintf(
+			"want changed=%t got changed=%t: previous hash %q: current hash %q",
+			expectChanged, isChanged, previousHash, hash,
+		), nil
+	}
+	return true, "", nil
+}
+
+// replicasOf returns the value of the provided integer field path of the
+// provided object or else the provided default value if the field is not
+// set
+func replicasOf(obj map[string]interface{}, defaultValue int64, fields ...string) (int64, error) {
+	val, found, err := unstructured.NestedInt64(obj, fields...)
+	if err != nil {
+		return 0, errors.Wrapf(err, "read %s", strings.Join(fields, "."))
+	}
+	if !found {
+		return defaultValue, nil
+	}
+	return val, nil
+}
+
+// isFullyAvailable returns true if the desired replicas of the provided
+// workload i.e. Deployment, StatefulSet or ReplicaSet are ready as well
+// as updated
+func isFullyAvailable(obj map[string]interface{}) (bool, string, error) {
+	// spec.replicas defaults to 1
+	desired, err := replicasOf(obj, 1, "spec", "replicas")
+	if err != nil {
+		return false, "", err
+	}
+	ready, err := replicasOf(obj, 0, "status", "readyReplicas")
+	if err != nil {
+		return false, "", err
+	}
+	if ready != desired {
+		return false, fmt.Sprintf("want %d ready replicas got %d", desired, ready), nil
+	}
+	if kind, _, _ := unstructured.NestedString(obj, "kind"); kind == "ReplicaSet" {
+		// replicaset does not track updated replicas
+		return true, "", nil
+	}
+	updated, err := replicasOf(obj, 0, "status", "updatedReplicas")
+	if err != nil {
+		return false, "", err
+	}
+	if updated != desired {
+		return false, fmt.Sprintf("want %d updated replicas got %d", desired, updated), nil
+	}
+	return true, "", nil
+}
+
+// observeFullyAvailable gets the latest state of the provided workload &
+// returns true if it is fully available
+func observeFullyAvailable(ctx context.Context, opts *RunOptions, given client.Object) (bool, string, error) {
+	invalidateCache(opts, given)
+	actual, err := Get(ctx, given, opts)
+	if err != nil {
+		return false, "", err
+	}
+	kind, _, err := GetKindVersionForObject(actual, opts.Scheme)
+	if err != nil {
+		return false, "", err
+	}
+	content, err := toUnstructuredContent(actual)
+	if err != nil {
+		return false, "", err
+	}
+	// copy since the content of an unstructured instance is not a copy
+	var withKind = make(map[string]interface{}, len(content)+1)
+	for key, value := range content {
+		withKind[key] = value
+	}
+	withKind["kind"] = kind
+	return isFullyAvailable(withKind)
+}
+
+// AssertFullyAvailable returns true if the desired replicas of the provided
+// Deployment, StatefulSet or ReplicaSet become ready as well as updated &
+// continue to be so for the provided duration. The workload is polled till
+// it is fully available or the provided retry timeout expires & then
+// at the retry interval for the stable duration. Unset retry interval &/
+// retry timeout are derived from KindDefaults.
+//
+// Note: The assertion fails as soon as the workload is found to be not
+// fully available during the stable duration
+func AssertFullyAvailable(ctx context.Context, given client.Object, stableFor time.Duration, eventually EventuallyOptions, options ...RunOption) (result bool, diff string, err error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return false, "", err
+	}
+	if given == nil {
+		return false, "", errors.New("nil object")
+	}
+	eventually, err = EventuallyOptionsForObject(given, eventually, opts.Scheme)
+	if err != nil {
+		return false, "", err
+	}
+	if eventually.RetryInterval == 0 {
+		eventually.RetryInterval = defaultRetryInterval
+	}
+	if eventually.RetryTimeout == 0 {
+		eventually.RetryTimeout = defaultRetryTimeout
+	}
+
+	var isAvailable bool
+	var observeErr error
+	retryErr := util.RetryWithContext(ctx, util.RetryOptions{
+		Immediate: true,
+		Interval:  eventually.RetryInterval,
+		Timeout:   eventually.RetryTimeout,
+	}, func() (bool, error) {
+		isAvailable, diff, observeErr = observeFullyAvailable(ctx, opts, given)
+		return isAvailable || observeErr != nil, nil
+	})
+	if observeErr != nil {
+		return false, "", observeErr
+	}
+	if err := ctx.Err(); err != nil {
+		return false, "", err
+	}
+	if retryErr != nil || !isAvailable {
+		// timed out before the workload became fully available
+		return false, diff, nil
+	}
+
+	// the retry interval is used between the checks of the stable duration
+	var interval = eventually.RetryInterval
+	if stableFor < interval {
+		interval = stableFor
+	}
+	deadline := time.Now().Add(stableFor)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return false, "", ctx.Err()
+		case <-time.After(interval):
+		}
+		isAvailable, diff, err := observeFullyAvailable(ctx, opts, given)
+		if err != nil || !isAvailable {
+			return false, diff, err
+		}
+	}
+	return true, "", nil
+}
+
+// isQuotaWithinLimits returns true if the utilization i.e. used / hard
+// of each provided resource of the quota does not exceed the provided
+// maximum utilization. The resources that are over are returned as the
+// diff.
+func isQuotaWithinLimits(quota *corev1.ResourceQuota, maxUtilization map[corev1.ResourceName]float64) (bool, string) {
+	var names = make([]string, 0, len(maxUtilization))
+	for name := range maxUtilization {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var overs []string
+	for _, name := range names {
+		resourceName := corev1.ResourceName(name)
+		hard, found := quota.Status.Hard[resourceName]
+		if !found {
+			overs = append(overs, fmt.Sprintf("%s: no hard limit", name))
+			continue
+		}
+		used := quota.Status.Used[resourceName]
+		if hard.IsZero() {
+			if !used.IsZero() {
+				overs = append(overs, fmt.Sprintf("%s: used %s of hard %s", name, used.String(), hard.String()))
+			}
+			continue
+		}
+		utilization := used.AsApproximateFloat64() / hard.AsApproximateFloat64()
+		if utilization > maxUtilization[resourceName] {
+			overs = append(overs, fmt.Sprintf(
+				"%s: utilization %.2f exceeds %.2f: used %s of hard %s",
+				name, utilization, maxUtilization[resourceName], used.String(), hard.String(),
+			))
+		}
+	}
+	if len(overs) != 0 {
+		return false, strings.Join(overs, ", ")
+	}
+	return true, ""
+}
+
+// AssertResourceQuota returns true if the utilization of each provided
+// resource of the referred ResourceQuota is within the provided maximum
+// utilization e.g. 0.8 for 80%. The resources that are over are returned
+// as the diff when the assertion fails.
+func AssertResourceQuota(ctx context.Context, namespace, quotaName string, maxUtilization map[corev1.ResourceName]float64, options ...RunOption) (result bool, diff string, err error) {
+	actual, err := Get(ctx, &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      quotaName,
+			Namespace: namespace,
+		},
+	}, options...)
+	if err != nil {
+		return false, "", err
+	}
+	var quota corev1.ResourceQuota
+	if err := toTypedObject(actual, &quota); err != nil {
+		return false, "", err
+	}
+	result, diff = isQuotaWithinLimits(&quota, maxUtilization)
+	return result, diff, nil
+}
+
+// IsPodReady returns true if the provided pod has its Ready
+// condition set to true
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isStatefulSetOrdered returns true if the provided pods are named as
+// per the statefulset's ordinals i.e. <name>-0 to <name>-(N-1), are all
+// ready & the statefulset has rolled out its update revision
+func isStatefulSetOrdered(sts *appsv1.StatefulSet, pods []corev1.Pod) (bool, string) {
+	var desired int32 = 1
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	var podsByName = make(map[string]*corev1.Pod, len(pods))
+	for i := range pods {
+		podsByName[pods[i].Name] = &pods[i]
+	}
+	if int32(len(pods)) != desired {
+		return false, fmt.Sprintf("want %d pods got %d", desired, len(pods))
+	}
+	for ordinal := int32(0); ordinal < desired; ordinal++ {
+		name := fmt.Sprintf("%s-%d", sts.Name, ordinal)
+		pod, found := podsByName[name]
+		if !found {
+			return false, fmt.Sprintf("pod %q not found", name)
+		}
+		if !IsPodReady(pod) {
+			return false, fmt.Sprintf("pod %q is not ready", name)
+		}
+	}
+	if sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+		return false, fmt.Sprintf(
+			"want current revision %q got %q",
+			sts.Status.UpdateRevision, sts.Status.CurrentRevision,
+		)
+	}
+	return true, ""
+}
+
+// AssertStatefulSetOrdered returns true if the referred statefulset has
+// its pods named <name>-0 to <name>-(N-1), all of them are ready & its
+// current revision is same as its update revision
+func AssertStatefulSetOrdered(ctx context.Context, name, namespace string, options ...RunOption) (result bool, diff string, err error) {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return false, "", err
+	}
+	actual, err := Get(ctx, &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}, opts)
+	if err != nil {
+		return false, "", err
+	}
+	var sts appsv1.StatefulSet
+	if err := toTypedObject(actual, &sts); err != nil {
+		return false, "", err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return false, "", errors.Wrap(err, "invalid statefulset selector")
+	}
+	var pods corev1.PodList
+	err = opts.Client.List(
+		ctx,
+		&pods,
+		client.InNamespace(namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to list pods")
+	}
+	result, diff = isStatefulSetOrdered(&sts, pods.Items)
+	return result, diff, nil
+}
+
+// AreAllReplicasReady returns true if the desired replicas of the
+// provided Deployment, StatefulSet or ReplicaSet are ready or if the
+// pods of the provided DaemonSet are ready on all the eligible nodes.
+// False is returned for other kinds.
+//
+// Note: spec.replicas defaults to 1
+func AreAllReplicasReady(obj client.Object) bool {
+	var desired *int32
+	var ready int32
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		desired, ready = workload.Spec.Replicas, workload.Status.ReadyReplicas
+	case *appsv1.StatefulSet:
+		desired, ready = workload.Spec.Replicas, workload.Status.ReadyReplicas
+	case *appsv1.ReplicaSet:
+		desired, ready = workload.Spec.Replicas, workload.Status.ReadyReplicas
+	case *appsv1.DaemonSet:
+		isReady, _ := isDaemonSetReady(workload)
+		return isReady
+	default:
+		return false
+	}
+	if desired == nil {
+		return ready == 1
+	}
+	return ready == *desired
+}
+
+// IsDeploymentAvailable returns true if the provided deployment's
+// controller has observed its latest generation, its Available condition
+// is set to true & all its desired replicas are ready
+func IsDeploymentAvailable(deploy *appsv1.Deployment) bool {
+	if deploy == nil || deploy.Status.ObservedGeneration < deploy.Generation {
+		return false
+	}
+	var isAvailable bool
+	for _, cond := range deploy.Status.Conditions {
+		if cond.Type == appsv1.DeploymentAvailable {
+			isAvailable = cond.Status == corev1.ConditionTrue
+			break
+		}
+	}
+	return isAvailable && AreAllReplicasReady(deploy)
+}
+
+// isWorkloadReady returns true if the provided Pod, Deployment,
+// StatefulSet, ReplicaSet or DaemonSet is ready
+func isWorkloadReady(obj client.Object, kind string) (bool, error) {
+	var typed client.Object
+	switch kind {
+	case "Pod":
+		typed = &corev1.Pod{}
+	case "Deployment":
+		typed = &appsv1.Deployment{}
+	case "StatefulSet":
+		typed = &appsv1.StatefulSet{}
+	case "ReplicaSet":
+		typed = &appsv1.ReplicaSet{}
+	case "DaemonSet":
+		typed = &appsv1.DaemonSet{}
+	default:
+		return false, errors.Errorf("readiness is not supported for kind %q", kind)
+	}
+	if err := toTypedObject(obj, typed); err != nil {
+		return false, err
+	}
+	switch workload := typed.(type) {
+	case *corev1.Pod:
+		return IsPodReady(workload), nil
+	case *appsv1.Deployment:
+		return IsDeploymentAvailable(workload), nil
+	}
+	return AreAllReplicasReady(typed), nil
+}
+
+// WaitForWorkloadReady polls the provided Pod, Deployment, StatefulSet,
+// ReplicaSet or DaemonSet till it is ready. The retry interval & the
+// retry timeout are derived from KindDefaults.
+func WaitForWorkloadReady(ctx context.Context, given client.Object, options ...RunOption) error {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	if given == nil {
+		return errors.New("nil object")
+	}
+	kind, _, err := GetKindVersionForObject(given, opts.Scheme)
+	if err != nil {
+		return err
+	}
+	eventually, err := EventuallyOptionsForObject(given, EventuallyOptions{}, opts.Scheme)
+	if err != nil {
+		return err
+	}
+	if eventually.RetryInterval == 0 {
+		eventually.RetryInterval = defaultRetryInterval
+	}
+	if eventually.RetryTimeout == 0 {
+		eventually.RetryTimeout = defaultRetryTimeout
+	}
+	err = util.RetryWithContext(ctx, util.RetryOptions{
+		Immediate: true,
+		Interval:  eventually.RetryInterval,
+		Timeout:   eventually.RetryTimeout,
+	}, func() (bool, error) {
+		// each attempt observes the latest state
+		invalidateCache(opts, given)
+		actual, err := Get(ctx, given, opts)
+		if err != nil {
+			return false, err
+		}
+		isReady, err := isWorkloadReady(actual, kind)
+		if err != nil {
+			return true, err
+		}
+		if !isReady {
+			return false, errors.Errorf("%s is not ready", kind)
+		}
+		return true, nil
+	})
+	return errors.Wrapf(err, "wait for workload ready: %s", k8sutil.DescribeObj(given))
+}
+
+// isGenerationObserved returns true if the status.observedGeneration of
+// the provided object is same as its metadata.generation
+func isGenerationObserved(obj map[string]interface{}) (bool, string, error) {
+	generation, found, err := unstructured.NestedInt64(obj, "metadata", "generation")
+	if err != nil {
+		return false, "", errors.Wrap(err, "read metadata.generation")
+	}
+	if !found {
+		return false, "", errors.New("metadata.generation is not set")
+	}
+	observed, found, err := unstructured.NestedInt64(obj, "status", "observedGeneration")
+	if err != nil {
+		return false, "", errors.Wrap(err, "read status.observedGeneration")
+	}
+	if !found {
+		return false, fmt.Sprintf("want observed generation %d got none", generation), nil
+	}
+	if observed != generation {
+		return false, fmt.Sprintf("want observed generation %d got %d", generation, observed), nil
+	}
+	return true, "", nil
+}
+
+// IsGenerationObserved returns true if the controller of the provided
+// object has reconciled its latest spec i.e. its status.observedGeneration
+// is same as its metadata.generation
+//
+// Note: An object without status.observedGeneration is not yet observed
+// while an object without metadata.generation results in an error
+func IsGenerationObserved(obj *unstructured.Unstructured) (bool, error) {
+	if obj == nil {
+		return false, errors.New("nil object")
+	}
+	isObserved, _, err := isGenerationObserved(obj.UnstructuredContent())
+	return isObserved, err
+}
+
+// AssertGenerationObserved returns true if the controller of the provided
+// object has observed its latest generation
+func AssertGenerationObserved(ctx context.Context, given client.Object, options ...RunOption) (result bool, diff string, err error) {
+	actual, err := Get(ctx, given, options...)
+	if err != nil {
+		return false, "", err
+	}
+	content, err := toUnstructuredContent(actual)
+	if err != nil {
+		return false, "", err
+	}
+	return isGenerationObserved(content)
+}
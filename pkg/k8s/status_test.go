@@ -0,0 +1,956 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/simplekube/kit/pkg/pointer"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAddressFromStatus(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name     string
+		given    map[string]interface{}
+		expected string
+		isError  bool
+	}{
+		{
+			name:  "should return empty address when status is not set",
+			given: map[string]interface{}{},
+		},
+		{
+			name: "should return the ip of the load balancer ingress",
+			given: map[string]interface{}{
+				"status": map[string]interface{}{
+					"loadBalancer": map[string]interface{}{
+						"ingress": []interface{}{
+							map[string]interface{}{"ip": "10.0.0.1"},
+						},
+					},
+				},
+			},
+			expected: "10.0.0.1",
+		},
+		{
+			name: "should return the hostname of the load balancer ingress",
+			given: map[string]interface{}{
+				"status": map[string]interface{}{
+					"loadBalancer": map[string]interface{}{
+						"ingress": []interface{}{
+							map[string]interface{}{"hostname": "lb.example.com"},
+						},
+					},
+				},
+			},
+			expected: "lb.example.com",
+		},
+		{
+			name: "should return the address of the gateway",
+			given: map[string]interface{}{
+				"status": map[string]interface{}{
+					"addresses": []interface{}{
+						map[string]interface{}{"type": "IPAddress", "value": "10.0.0.2"},
+					},
+				},
+			},
+			expected: "10.0.0.2",
+		},
+		{
+			name: "should fail when load balancer ingress is not a list",
+			given: map[string]interface{}{
+				"status": map[string]interface{}{
+					"loadBalancer": map[string]interface{}{
+						"ingress": "invalid",
+					},
+				},
+			},
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := addressFromStatus(scenario.given)
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsDaemonSetReady(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name         string
+		generation   int64
+		status       appsv1.DaemonSetStatus
+		isReady      bool
+		diffContains string
+	}{
+		{
+			name:       "should be ready when pods are ready on all eligible nodes",
+			generation: 2,
+			status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     2,
+				DesiredNumberScheduled: 3,
+				CurrentNumberScheduled: 3,
+				NumberReady:            3,
+			},
+			isReady: true,
+		},
+		{
+			name:       "should not be ready when the latest generation is not observed",
+			generation: 3,
+			status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     2,
+				DesiredNumberScheduled: 3,
+				CurrentNumberScheduled: 3,
+				NumberReady:            3,
+			},
+			diffContains: "want observed generation 3 got 2",
+		},
+		{
+			name:       "should not be ready when some pods are not scheduled",
+			generation: 2,
+			status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     2,
+				DesiredNumberScheduled: 3,
+				CurrentNumberScheduled: 2,
+				NumberReady:            3,
+			},
+			diffContains: "want 3 scheduled pods got 2",
+		},
+		{
+			name:       "should not be ready when some pods are not ready",
+			generation: 2,
+			status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     2,
+				DesiredNumberScheduled: 3,
+				CurrentNumberScheduled: 3,
+				NumberReady:            2,
+				NumberUnavailable:      1,
+			},
+			diffContains: "want 3 ready pods got 2",
+		},
+		{
+			name:       "should not be ready when some pods are unavailable",
+			generation: 2,
+			status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     2,
+				DesiredNumberScheduled: 3,
+				CurrentNumberScheduled: 3,
+				NumberReady:            3,
+				NumberUnavailable:      1,
+			},
+			diffContains: "want 0 unavailable pods got 1",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, diff := isDaemonSetReady(&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: scenario.generation},
+				Status:     scenario.status,
+			})
+			assert.Equal(t, scenario.isReady, got, diff)
+			assert.Contains(t, diff, scenario.diffContains)
+		})
+	}
+}
+
+func TestPodsPerNode(t *testing.T) {
+	t.Parallel()
+
+	pods := []corev1.Pod{
+		{Spec: corev1.PodSpec{NodeName: "node-1"}},
+		{Spec: corev1.PodSpec{NodeName: "node-2"}},
+		{Spec: corev1.PodSpec{NodeName: "node-1"}},
+		{Spec: corev1.PodSpec{}}, // not scheduled
+	}
+	got := podsPerNode(pods)
+	assert.Equal(t, map[string]int{"node-1": 2, "node-2": 1}, got)
+	assert.Equal(t, "node-1=2, node-2=1", describeDistribution(got))
+}
+
+func TestIsRevisionAdvanced(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name       string
+		current    string
+		previous   string
+		isAdvanced bool
+		isError    bool
+	}{
+		{
+			name:       "should be advanced when previous revision is not set",
+			current:    "1",
+			isAdvanced: true,
+		},
+		{
+			name:       "should be advanced when current revision is greater",
+			current:    "10",
+			previous:   "9",
+			isAdvanced: true,
+		},
+		{
+			name:     "should not be advanced when revisions are same",
+			current:  "2",
+			previous: "2",
+		},
+		{
+			name:     "should not be advanced when current revision is not set",
+			previous: "2",
+		},
+		{
+			name:     "should fail when revision is not a number",
+			current:  "two",
+			previous: "1",
+			isError:  true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := isRevisionAdvanced(scenario.current, scenario.previous)
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.isAdvanced, got)
+			}
+		})
+	}
+}
+
+func TestIsFullyAvailable(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name        string
+		given       map[string]interface{}
+		isAvailable bool
+	}{
+		{
+			name: "should be available when desired replicas are ready & updated",
+			given: map[string]interface{}{
+				"kind": "Deployment",
+				"spec": map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{
+					"readyReplicas":   int64(3),
+					"updatedReplicas": int64(3),
+				},
+			},
+			isAvailable: true,
+		},
+		{
+			name: "should default desired replicas to 1",
+			given: map[string]interface{}{
+				"kind": "StatefulSet",
+				"status": map[string]interface{}{
+					"readyReplicas":   int64(1),
+					"updatedReplicas": int64(1),
+				},
+			},
+			isAvailable: true,
+		},
+		{
+			name: "should not be available when some replicas are not updated",
+			given: map[string]interface{}{
+				"kind": "Deployment",
+				"spec": map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{
+					"readyReplicas":   int64(3),
+					"updatedReplicas": int64(2),
+				},
+			},
+		},
+		{
+			name: "should not be available when some replicas are not ready",
+			given: map[string]interface{}{
+				"kind": "Deployment",
+				"spec": map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{
+					"readyReplicas":   int64(2),
+					"updatedReplicas": int64(3),
+				},
+			},
+		},
+		{
+			name: "should not consider updated replicas of a replicaset",
+			given: map[string]interface{}{
+				"kind":   "ReplicaSet",
+				"spec":   map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{"readyReplicas": int64(2)},
+			},
+			isAvailable: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, diff, err := isFullyAvailable(scenario.given)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isAvailable, got, diff)
+		})
+	}
+}
+
+// rolloutClient returns the provided ready replicas of a Deployment one
+// per Get call & repeats the last one once these are exhausted
+type rolloutClient struct {
+	client.Client
+
+	mu       sync.Mutex
+	readies  []int64
+	getCalls int
+}
+
+func (c *rolloutClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if err := c.Client.Get(ctx, key, obj); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ready := c.readies[len(c.readies)-1]
+	if c.getCalls < len(c.readies) {
+		ready = c.readies[c.getCalls]
+	}
+	c.getCalls++
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		o.Status.ReadyReplicas = int32(ready)
+		o.Status.UpdatedReplicas = int32(ready)
+	case *unstructured.Unstructured:
+		_ = unstructured.SetNestedField(o.Object, ready, "status", "readyReplicas")
+		_ = unstructured.SetNestedField(o.Object, ready, "status", "updatedReplicas")
+	}
+	return nil
+}
+
+func TestAssertFullyAvailable(t *testing.T) {
+	t.Parallel()
+
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+	}
+	unstructDeploy := &unstructured.Unstructured{}
+	unstructDeploy.SetAPIVersion("apps/v1")
+	unstructDeploy.SetKind("Deployment")
+	unstructDeploy.SetName("web")
+	unstructDeploy.SetNamespace("default")
+	eventually := EventuallyOptions{RetryInterval: 5 * time.Millisecond, RetryTimeout: 100 * time.Millisecond}
+
+	var scenarios = []struct {
+		name         string
+		given        client.Object
+		readies      []int64
+		isAvailable  bool
+		diffContains string
+	}{
+		{
+			name:        "should pass when the rollout is already complete & holds",
+			given:       deploy,
+			readies:     []int64{2},
+			isAvailable: true,
+		},
+		{
+			name:        "should pass when the rollout completes & holds",
+			given:       deploy,
+			readies:     []int64{0, 1, 2},
+			isAvailable: true,
+		},
+		{
+			name:        "should pass for an unstructured instance",
+			given:       unstructDeploy,
+			readies:     []int64{1, 2},
+			isAvailable: true,
+		},
+		{
+			name:         "should fail when the rollout completes but regresses",
+			given:        deploy,
+			readies:      []int64{0, 2, 1, 2},
+			diffContains: "want 2 ready replicas got 1",
+		},
+		{
+			name:         "should fail when the rollout does not complete in time",
+			given:        deploy,
+			readies:      []int64{1},
+			diffContains: "want 2 ready replicas got 1",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := &rolloutClient{
+				Client:  fake.NewClientBuilder().WithObjects(deploy.DeepCopy()).Build(),
+				readies: scenario.readies,
+			}
+			given := scenario.given.DeepCopyObject().(client.Object)
+			got, diff, err := AssertFullyAvailable(
+				context.Background(), given, 30*time.Millisecond, eventually, &RunOptions{Client: cli},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isAvailable, got, diff)
+			assert.Contains(t, diff, scenario.diffContains)
+			assert.Equal(t, scenario.given, given, "should not mutate the provided object")
+			if scenario.isAvailable {
+				assert.Greater(t, cli.getCalls, len(scenario.readies), "should check the stable duration")
+			}
+		})
+	}
+}
+
+func TestAssertFullyAvailableWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+	}
+	cli := &rolloutClient{
+		Client:  fake.NewClientBuilder().WithObjects(deploy).Build(),
+		readies: []int64{1},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	got, _, err := AssertFullyAvailable(ctx, deploy, time.Second, EventuallyOptions{
+		RetryInterval: 5 * time.Millisecond,
+		RetryTimeout:  time.Minute,
+	}, &RunOptions{Client: cli})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, got)
+}
+
+func TestIsQuotaWithinLimits(t *testing.T) {
+	t.Parallel()
+
+	quota := &corev1.ResourceQuota{
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourcePods:    resource.MustParse("10"),
+				corev1.ResourceCPU:     resource.MustParse("2"),
+				corev1.ResourceSecrets: resource.MustParse("0"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourcePods: resource.MustParse("9"),
+				corev1.ResourceCPU:  resource.MustParse("500m"),
+			},
+		},
+	}
+
+	var scenarios = []struct {
+		name           string
+		maxUtilization map[corev1.ResourceName]float64
+		isWithin       bool
+		diff           string
+	}{
+		{
+			name: "should be within limits when utilization is below max",
+			maxUtilization: map[corev1.ResourceName]float64{
+				corev1.ResourcePods:    0.9,
+				corev1.ResourceCPU:     0.5,
+				corev1.ResourceSecrets: 0.5,
+			},
+			isWithin: true,
+		},
+		{
+			name: "should report the resource that is over",
+			maxUtilization: map[corev1.ResourceName]float64{
+				corev1.ResourcePods: 0.8,
+				corev1.ResourceCPU:  0.5,
+			},
+			diff: "pods: utilization 0.90 exceeds 0.80: used 9 of hard 10",
+		},
+		{
+			name: "should report the resource without hard limit",
+			maxUtilization: map[corev1.ResourceName]float64{
+				corev1.ResourceMemory: 0.8,
+			},
+			diff: "memory: no hard limit",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, diff := isQuotaWithinLimits(quota, scenario.maxUtilization)
+			assert.Equal(t, scenario.isWithin, got)
+			assert.Equal(t, scenario.diff, diff)
+		})
+	}
+}
+
+func TestIsStatefulSetOrdered(t *testing.T) {
+	t.Parallel()
+
+	readyPod := func(name string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32(2)},
+		Status: appsv1.StatefulSetStatus{
+			CurrentRevision: "db-1",
+			UpdateRevision:  "db-1",
+		},
+	}
+	rollingOut := sts.DeepCopy()
+	rollingOut.Status.UpdateRevision = "db-2"
+
+	var scenarios = []struct {
+		name      string
+		sts       *appsv1.StatefulSet
+		pods      []corev1.Pod
+		isOrdered bool
+		diff      string
+	}{
+		{
+			name:      "should be ordered when all ordinals are ready",
+			sts:       sts,
+			pods:      []corev1.Pod{readyPod("db-1"), readyPod("db-0")},
+			isOrdered: true,
+		},
+		{
+			name: "should not be ordered when an ordinal is missing",
+			sts:  sts,
+			pods: []corev1.Pod{readyPod("db-0"), readyPod("db-2")},
+			diff: `pod "db-1" not found`,
+		},
+		{
+			name: "should not be ordered when a pod is not ready",
+			sts:  sts,
+			pods: []corev1.Pod{readyPod("db-0"), {ObjectMeta: metav1.ObjectMeta{Name: "db-1"}}},
+			diff: `pod "db-1" is not ready`,
+		},
+		{
+			name: "should not be ordered when update is not rolled out",
+			sts:  rollingOut,
+			pods: []corev1.Pod{readyPod("db-0"), readyPod("db-1")},
+			diff: `want current revision "db-2" got "db-1"`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, diff := isStatefulSetOrdered(scenario.sts, scenario.pods)
+			assert.Equal(t, scenario.isOrdered, got)
+			assert.Equal(t, scenario.diff, diff)
+		})
+	}
+}
+
+func TestIsPodReady(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name       string
+		conditions []corev1.PodCondition
+		isReady    bool
+	}{
+		{
+			name: "should be ready when ready condition is true",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
+			isReady: true,
+		},
+		{
+			name: "should not be ready when ready condition is false",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			},
+		},
+		{
+			name: "should not be ready when ready condition is not set",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: scenario.conditions}}
+			assert.Equal(t, scenario.isReady, IsPodReady(pod))
+		})
+	}
+}
+
+func TestAreAllReplicasReady(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name    string
+		given   client.Object
+		isReady bool
+	}{
+		{
+			name: "should be ready when deployment has all replicas ready",
+			given: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: pointer.Int32(3)},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 3},
+			},
+			isReady: true,
+		},
+		{
+			name: "should not be ready when statefulset has some replicas ready",
+			given: &appsv1.StatefulSet{
+				Spec:   appsv1.StatefulSetSpec{Replicas: pointer.Int32(3)},
+				Status: appsv1.StatefulSetStatus{ReadyReplicas: 2},
+			},
+		},
+		{
+			name: "should default to 1 replica when replicas is not set",
+			given: &appsv1.ReplicaSet{
+				Status: appsv1.ReplicaSetStatus{ReadyReplicas: 1},
+			},
+			isReady: true,
+		},
+		{
+			name: "should be ready when daemonset pods are ready on all nodes",
+			given: &appsv1.DaemonSet{
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, CurrentNumberScheduled: 2, NumberReady: 2},
+			},
+			isReady: true,
+		},
+		{
+			name:  "should not be ready for unsupported kinds",
+			given: &corev1.ConfigMap{},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, scenario.isReady, AreAllReplicasReady(scenario.given))
+		})
+	}
+}
+
+func TestIsDeploymentAvailable(t *testing.T) {
+	t.Parallel()
+
+	available := []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+	}
+
+	var scenarios = []struct {
+		name       string
+		generation int64
+		status     appsv1.DeploymentStatus
+		isReady    bool
+	}{
+		{
+			name:       "should be available when condition is true & replicas are ready",
+			generation: 2,
+			status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				ReadyReplicas:      2,
+				Conditions:         available,
+			},
+			isReady: true,
+		},
+		{
+			name:       "should not be available when latest generation is not observed",
+			generation: 3,
+			status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				ReadyReplicas:      2,
+				Conditions:         available,
+			},
+		},
+		{
+			name:       "should not be available when replicas are not ready",
+			generation: 2,
+			status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				ReadyReplicas:      1,
+				Conditions:         available,
+			},
+		},
+		{
+			name:       "should not be available when condition is not set",
+			generation: 2,
+			status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				ReadyReplicas:      2,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			deploy := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: scenario.generation},
+				Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+				Status:     scenario.status,
+			}
+			assert.Equal(t, scenario.isReady, IsDeploymentAvailable(deploy))
+		})
+	}
+}
+
+func TestWaitForWorkloadReady(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready-pod", Namespace: "default"},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+		}},
+	}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	err := WaitForWorkloadReady(context.Background(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready-pod", Namespace: "default"},
+	}, &RunOptions{Client: cli})
+	assert.NoError(t, err)
+
+	err = WaitForWorkloadReady(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready-pod", Namespace: "default"},
+	}, &RunOptions{Client: fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready-pod", Namespace: "default"},
+	}).Build()})
+	assert.Error(t, err)
+}
+
+func TestIsGenerationObserved(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name       string
+		given      map[string]interface{}
+		isObserved bool
+		isError    bool
+	}{
+		{
+			name: "should be observed when the generations match",
+			given: map[string]interface{}{
+				"metadata": map[string]interface{}{"generation": int64(2)},
+				"status":   map[string]interface{}{"observedGeneration": int64(2)},
+			},
+			isObserved: true,
+		},
+		{
+			name: "should not be observed when the observed generation lags",
+			given: map[string]interface{}{
+				"metadata": map[string]interface{}{"generation": int64(3)},
+				"status":   map[string]interface{}{"observedGeneration": int64(2)},
+			},
+		},
+		{
+			name: "should not be observed when the observed generation is not set",
+			given: map[string]interface{}{
+				"metadata": map[string]interface{}{"generation": int64(1)},
+			},
+		},
+		{
+			name: "should error when the generation is not set",
+			given: map[string]interface{}{
+				"status": map[string]interface{}{"observedGeneration": int64(1)},
+			},
+			isError: true,
+		},
+		{
+			name: "should error when the observed generation is not an integer",
+			given: map[string]interface{}{
+				"metadata": map[string]interface{}{"generation": int64(1)},
+				"status":   map[string]interface{}{"observedGeneration": "1"},
+			},
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := IsGenerationObserved(&unstructured.Unstructured{Object: scenario.given})
+			if scenario.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.isObserved, got)
+		})
+	}
+}
+
+func TestAssertPodTemplateHash(t *testing.T) {
+	t.Parallel()
+
+	deploy := func(name string, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				UID:         types.UID(name),
+				Annotations: annotations,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			},
+		}
+	}
+	replicaSet := func(owner *appsv1.Deployment, hash string) *appsv1.ReplicaSet {
+		labels := map[string]string{"app": owner.Name}
+		if hash != "" {
+			labels[appsv1.DefaultDeploymentUniqueLabelKey] = hash
+		}
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            owner.Name + "-rs",
+				Namespace:       "default",
+				Labels:          labels,
+				Annotations:     map[string]string{DeploymentRevisionAnnotation: "2"},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, appsv1.SchemeGroupVersion.WithKind("Deployment"))},
+			},
+		}
+	}
+	revised := map[string]string{DeploymentRevisionAnnotation: "2"}
+	hashed := deploy("hashed", revised)
+	unhashed := deploy("unhashed", revised)
+	unrevised := deploy("unrevised", nil)
+	cli := fake.NewClientBuilder().WithObjects(
+		hashed, replicaSet(hashed, "5d8f9c"),
+		unhashed, replicaSet(unhashed, ""),
+		unrevised, replicaSet(unrevised, "7b6c4d"),
+	).Build()
+
+	var scenarios = []struct {
+		name          string
+		deploy        string
+		previousHash  string
+		expectChanged bool
+		expectResult  bool
+		diffContains  string
+		errContains   string
+	}{
+		{
+			name:         "should pass when the hash matches as expected",
+			deploy:       "hashed",
+			previousHash: "5d8f9c",
+			expectResult: true,
+		},
+		{
+			name:          "should pass when the hash changed as expected",
+			deploy:        "hashed",
+			previousHash:  "4a7e1b",
+			expectChanged: true,
+			expectResult:  true,
+		},
+		{
+			name:          "should fail when the hash is expected to change but matches",
+			deploy:        "hashed",
+			previousHash:  "5d8f9c",
+			expectChanged: true,
+			diffContains:  "want changed=true got changed=false",
+		},
+		{
+			name:         "should fail when the hash is expected to match but changed",
+			deploy:       "hashed",
+			previousHash: "4a7e1b",
+			diffContains: `want changed=false got changed=true: previous hash "4a7e1b": current hash "5d8f9c"`,
+		},
+		{
+			name:          "should error when the revision annotation is missing",
+			deploy:        "unrevised",
+			previousHash:  "7b6c4d",
+			expectChanged: true,
+			errContains:   "annotation deployment.kubernetes.io/revision is not set",
+		},
+		{
+			name:          "should error when the hash label is missing",
+			deploy:        "unhashed",
+			previousHash:  "4a7e1b",
+			expectChanged: true,
+			errContains:   "label pod-template-hash is not set",
+		},
+		{
+			name:          "should error when the previous hash is empty",
+			deploy:        "hashed",
+			expectChanged: true,
+			errContains:   "empty previous hash",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, diff, err := AssertPodTemplateHash(
+				context.Background(),
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: scenario.deploy, Namespace: "default"}},
+				scenario.previousHash,
+				scenario.expectChanged,
+				&RunOptions{Client: cli},
+			)
+			if scenario.errContains != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), scenario.errContains)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expectResult, result)
+			assert.Contains(t, diff, scenario.diffContains)
+		})
+	}
+}
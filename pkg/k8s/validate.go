@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// ValidateAllYAMLs validates the objects found in the provided YAML
+// files or directories against the cluster's schema & admission via a
+// server side dry run. Nothing is mutated in the cluster. The returned
+// error aggregates the failure of each object along with its file, its
+// namespace, name & GVK.
+func ValidateAllYAMLs(ctx context.Context, filePaths []string, options ...RunOption) error {
+	manifests, err := k8sutil.ScanForYMLsFromPaths(filePaths)
+	if err != nil {
+		return err
+	}
+	var finalError *multierror.Error
+	for _, manifest := range manifests {
+		objs, err := k8sutil.BuildObjectsFromYMLs([]string{manifest})
+		if err != nil {
+			finalError = multierror.Append(finalError, errors.Wrapf(err, "yaml %q", manifest))
+			continue
+		}
+		for _, obj := range objs {
+			if k8sutil.IsNilUnstructured(obj) {
+				continue
+			}
+			if _, err := DryRun(ctx, obj, options...); err != nil {
+				finalError = multierror.Append(
+					finalError,
+					errors.Wrapf(err, "yaml %q: %s", manifest, k8sutil.DescribeObj(obj)),
+				)
+			}
+		}
+	}
+	return finalError.ErrorOrNil()
+}
+
+// ValidateYAMLsRunner validates the objects found in the provided YAML
+// files or directories when run e.g. as a step of a Job before the files
+// get applied
+type ValidateYAMLsRunner struct {
+	FilePaths []string
+}
+
+// compile time check to assert if the structure
+// ValidateYAMLsRunner implements the interface Runner
+var _ Runner = (*ValidateYAMLsRunner)(nil)
+
+// Run validates the YAMLs
+func (v *ValidateYAMLsRunner) Run(ctx context.Context, opts ...RunOption) error {
+	if v == nil {
+		return errors.New("nil validate yamls runner")
+	}
+	return ValidateAllYAMLs(ctx, v.FilePaths, opts...)
+}
+
+// String describes the runner
+func (v *ValidateYAMLsRunner) String() string {
+	if v == nil {
+		return "validate yamls"
+	}
+	return fmt.Sprintf("validate yamls %s", strings.Join(v.FilePaths, ", "))
+}
@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// rejectingClient rejects the patch of objects with the provided name
+// & accepts the rest without forwarding them
+type rejectingClient struct {
+	client.Client
+	reject string
+}
+
+func (c *rejectingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if obj.GetName() == c.reject {
+		return errors.New("denied by admission webhook")
+	}
+	return nil
+}
+
+func TestValidateAllYAMLs(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name             string
+		fixtures         []string
+		reject           string
+		isError          bool
+		expectedErrParts []string
+	}{
+		{
+			name:     "should pass when all objects are valid",
+			fixtures: []string{"testdata/custom_namespace.yaml", "testdata/custom_namespace_list.yaml"},
+		},
+		{
+			name:     "should report the file & object that failed",
+			fixtures: []string{"testdata/custom_namespace.yaml", "testdata/custom_namespace_list.yaml"},
+			reject:   "custom-2",
+			isError:  true,
+			expectedErrParts: []string{
+				`yaml "testdata/custom_namespace_list.yaml"`,
+				"name=custom-2",
+				"Kind=Namespace",
+				"denied by admission webhook",
+			},
+		},
+		{
+			name:     "should fail when file is not found",
+			fixtures: []string{"testdata/not_found.yaml"},
+			isError:  true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := &rejectingClient{Client: fake.NewClientBuilder().Build(), reject: scenario.reject}
+			err := ValidateAllYAMLs(context.Background(), scenario.fixtures, &RunOptions{Client: cli})
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, part := range scenario.expectedErrParts {
+				assert.Contains(t, err.Error(), part)
+			}
+			if scenario.reject != "" {
+				assert.NotContains(t, err.Error(), "name=custom-1")
+			}
+		})
+	}
+}
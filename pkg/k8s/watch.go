@@ -0,0 +1,148 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"github.com/simplekube/kit/pkg/k8sutil"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// WatchCondition decides if a watch should stop based on the provided
+// event. The event's object is of type *unstructured.Unstructured.
+type WatchCondition func(event watch.Event) (done bool, err error)
+
+// getWatchClient returns the client set in the provided options if it
+// supports watch or else builds one from the rest config
+func getWatchClient(opts *RunOptions) (client.WithWatch, error) {
+	if wc, ok := opts.Client.(client.WithWatch); ok {
+		return wc, nil
+	}
+	cfg, err := getClientConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	wc, err := client.NewWithWatch(cfg, client.Options{Scheme: opts.Scheme})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialise watch client")
+	}
+	return wc, nil
+}
+
+// watchUntil watches the provided object till the condition is met. The
+// current state of the object, if found, is passed to the condition as an
+// added event before the watch is opened from its resource version. The
+// returned bool is false if the watch could not be opened or got closed
+// by the server before the condition was met.
+func watchUntil(ctx context.Context, wc client.WithWatch, given *unstructured.Unstructured, condition WatchCondition) (isFinal bool, err error) {
+	listOpts := []client.ListOption{
+		client.InNamespace(given.GetNamespace()),
+		client.MatchingFieldsSelector{Selector: fields.OneTermEqualSelector("metadata.name", given.GetName())},
+	}
+	actual := given.DeepCopy()
+	if getErr := wc.Get(ctx, client.ObjectKeyFromObject(given), actual); getErr == nil {
+		done, err := condition(watch.Event{Type: watch.Added, Object: actual})
+		if err != nil || done {
+			return true, err
+		}
+		listOpts = append(listOpts, &client.ListOptions{
+			Raw: &metav1.ListOptions{ResourceVersion: actual.GetResourceVersion()},
+		})
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(given.GroupVersionKind().GroupVersion().WithKind(given.GetKind() + "List"))
+	watcher, err := wc.Watch(ctx, list, listOpts...)
+	if err != nil {
+		return false, err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			if event.Type == watch.Error {
+				return true, errors.Errorf("watch error: %v", event.Object)
+			}
+			done, err := condition(event)
+			if err != nil || done {
+				return true, err
+			}
+		}
+	}
+}
+
+// pollUntil gets the provided object in intervals till the condition
+// is met. Each observed state is passed to the condition as a modified
+// event.
+func pollUntil(ctx context.Context, cli client.Client, given *unstructured.Unstructured, condition WatchCondition) error {
+	ticker := time.NewTicker(defaultRetryInterval)
+	defer ticker.Stop()
+	for {
+		actual := given.DeepCopy()
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(given), actual); err == nil {
+			done, err := condition(watch.Event{Type: watch.Modified, Object: actual})
+			if err != nil || done {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WatchUntil watches the provided object till the provided condition
+// returns true or the timeout expires. It falls back to polling if the
+// watch can not be opened or gets closed.
+//
+// Note: A zero timeout implies waiting till the context is done
+func WatchUntil(ctx context.Context, given client.Object, condition WatchCondition, timeout time.Duration, options ...RunOption) error {
+	opts, err := makeRunOptions(options...)
+	if err != nil {
+		return err
+	}
+	if given == nil {
+		return errors.New("nil object")
+	}
+	if condition == nil {
+		return errors.New("nil condition")
+	}
+	gvk, err := apiutil.GVKForObject(given, opts.Scheme)
+	if err != nil {
+		return errors.Wrap(err, "extract gvk")
+	}
+	target := &unstructured.Unstructured{}
+	target.SetGroupVersionKind(gvk)
+	target.SetNamespace(given.GetNamespace())
+	target.SetName(given.GetName())
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var isFinal bool
+	if wc, wcErr := getWatchClient(opts); wcErr == nil {
+		isFinal, err = watchUntil(ctx, wc, target, condition)
+	}
+	if !isFinal {
+		err = pollUntil(ctx, opts.Client, target, condition)
+	}
+	return errors.Wrapf(err, "watch until: %s", k8sutil.DescribeObj(given))
+}
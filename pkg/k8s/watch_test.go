@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// noWatchClient hides the watch support of the embedded client
+type noWatchClient struct {
+	client.Client
+}
+
+func TestWatchUntil(t *testing.T) {
+	t.Parallel()
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "watch-until",
+			Namespace: "default",
+		},
+		Status: batchv1.JobStatus{Succeeded: 1},
+	}
+	isSucceeded := func(event watch.Event) (bool, error) {
+		succeeded, _, err := unstructured.NestedInt64(
+			event.Object.(*unstructured.Unstructured).Object, "status", "succeeded",
+		)
+		return succeeded > 0, err
+	}
+	isFailed := func(event watch.Event) (bool, error) {
+		failed, _, err := unstructured.NestedInt64(
+			event.Object.(*unstructured.Unstructured).Object, "status", "failed",
+		)
+		return failed > 0, err
+	}
+
+	var scenarios = []struct {
+		name      string
+		client    func() client.Client
+		condition WatchCondition
+		isError   bool
+	}{
+		{
+			name: "should be done when watched object meets the condition",
+			client: func() client.Client {
+				return fake.NewClientBuilder().WithObjects(job.DeepCopy()).Build()
+			},
+			condition: isSucceeded,
+		},
+		{
+			name: "should be done by polling when watch is not supported",
+			client: func() client.Client {
+				return &noWatchClient{fake.NewClientBuilder().WithObjects(job.DeepCopy()).Build()}
+			},
+			condition: isSucceeded,
+		},
+		{
+			name: "should time out when condition is not met",
+			client: func() client.Client {
+				return &noWatchClient{fake.NewClientBuilder().WithObjects(job.DeepCopy()).Build()}
+			},
+			condition: isFailed,
+			isError:   true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := WatchUntil(
+				context.Background(),
+				job,
+				scenario.condition,
+				100*time.Millisecond,
+				&RunOptions{Client: scenario.client()},
+			)
+			if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package k8sutil
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// IsExtensionArchive returns true if provided file has a tar or
+// gzipped tar extension
+func IsExtensionArchive(f string) bool {
+	return IsExtensionTar(f) || IsExtensionTarGzip(f)
+}
+
+// IsExtensionTar returns true if provided file has tar extension
+func IsExtensionTar(f string) bool {
+	return strings.HasSuffix(f, ".tar")
+}
+
+// IsExtensionTarGzip returns true if provided file has gzipped tar
+// extension
+func IsExtensionTarGzip(f string) bool {
+	return strings.HasSuffix(f, ".tar.gz") || strings.HasSuffix(f, ".tgz")
+}
+
+// BuildObjectsFromArchive decodes the yaml files found in the provided
+// .tar, .tar.gz or .tgz archive into unstructured Kubernetes API objects
+func BuildObjectsFromArchive(archivePath string) ([]*unstructured.Unstructured, error) {
+	if !IsExtensionArchive(archivePath) {
+		return nil, errors.Errorf("unsupported archive %q: want .tar, .tar.gz or .tgz", archivePath)
+	}
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "archive %q", archivePath)
+	}
+	defer f.Close()
+
+	var reader io.Reader = bufio.NewReader(f)
+	if IsExtensionTarGzip(archivePath) {
+		gzReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "archive %q", archivePath)
+		}
+		defer gzReader.Close()
+		reader = gzReader
+	}
+	return ReadKubernetesObjectsFromTar(reader)
+}
+
+// ReadKubernetesObjectsFromTar decodes the yaml files found in the
+// provided tar stream into unstructured Kubernetes API objects
+func ReadKubernetesObjectsFromTar(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objects = make([]*unstructured.Unstructured, 0)
+	var errs []error
+
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return objects, errors.Wrap(err, "read tar")
+		}
+		if header.Typeflag != tar.TypeReg || !IsExtensionYML(header.Name) {
+			continue
+		}
+		objs, err := ReadKubernetesObjects(tarReader)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "yaml %q", header.Name))
+			continue
+		}
+		objects = MaybeAppendUnstructuredList(objects, objs)
+	}
+	return objects, (&multierror.Error{Errors: errs}).ErrorOrNil()
+}
@@ -0,0 +1,71 @@
+package k8sutil
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const archiveConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: from-archive
+  namespace: default
+`
+
+// writeTar writes the provided files as a tar stream
+func writeTar(t *testing.T, w io.Writer, files map[string]string) {
+	tw := tar.NewWriter(w)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		assert.NoError(t, err)
+		_, err = tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+}
+
+func TestBuildObjectsFromArchive(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"manifests/cm.yaml": archiveConfigMap,
+		"README.md":         "not a manifest",
+	}
+	dir := t.TempDir()
+
+	tarPath := filepath.Join(dir, "manifests.tar")
+	tarFile, err := os.Create(tarPath)
+	assert.NoError(t, err)
+	writeTar(t, tarFile, files)
+	assert.NoError(t, tarFile.Close())
+
+	tgzPath := filepath.Join(dir, "manifests.tgz")
+	tgzFile, err := os.Create(tgzPath)
+	assert.NoError(t, err)
+	gzWriter := gzip.NewWriter(tgzFile)
+	writeTar(t, gzWriter, files)
+	assert.NoError(t, gzWriter.Close())
+	assert.NoError(t, tgzFile.Close())
+
+	for _, archivePath := range []string{tarPath, tgzPath} {
+		objs, err := BuildObjectsFromArchive(archivePath)
+		assert.NoError(t, err)
+		if assert.Len(t, objs, 1) {
+			assert.Equal(t, "from-archive", objs[0].GetName())
+		}
+	}
+
+	_, err = BuildObjectsFromArchive(filepath.Join(dir, "manifests.zip"))
+	assert.Error(t, err)
+}
@@ -1,7 +1,9 @@
 package k8sutil
 
 import (
+	"bufio"
 	"io"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -44,3 +46,20 @@ func ReadKubernetesObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
 
 	return objects, nil
 }
+
+// BuildObjectsFromReader decodes the YAML or JSON documents from the
+// provided reader into unstructured Kubernetes API objects
+func BuildObjectsFromReader(r io.Reader) ([]*unstructured.Unstructured, error) {
+	if r == nil {
+		return nil, errors.New("nil reader")
+	}
+	return ReadKubernetesObjects(bufio.NewReader(r))
+}
+
+// BuildObjectsFromString decodes the YAML or JSON documents found in the
+// provided string into unstructured Kubernetes API objects
+//
+// Note: Multiple documents are separated with ---
+func BuildObjectsFromString(manifest string) ([]*unstructured.Unstructured, error) {
+	return BuildObjectsFromReader(strings.NewReader(manifest))
+}
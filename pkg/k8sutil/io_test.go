@@ -0,0 +1,78 @@
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildObjectsFromString(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name          string
+		manifest      string
+		expectedNames []string
+		isError       bool
+	}{
+		{
+			name:     "should return no objects for empty manifest",
+			manifest: "",
+		},
+		{
+			name: "should build objects from multiple yaml documents",
+			manifest: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`,
+			expectedNames: []string{"first", "second"},
+		},
+		{
+			name: "should build objects from mixed yaml & json documents",
+			manifest: `apiVersion: v1
+kind: Namespace
+metadata:
+  name: from-yaml
+---
+{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "from-json"}}
+`,
+			expectedNames: []string{"from-yaml", "from-json"},
+		},
+		{
+			name:          "should build objects from json document",
+			manifest:      `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "only-json"}}`,
+			expectedNames: []string{"only-json"},
+		},
+		{
+			name:     "should fail for invalid document",
+			manifest: "kind: [",
+			isError:  true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			objs, err := BuildObjectsFromString(scenario.manifest)
+			if scenario.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var names []string
+			for _, obj := range objs {
+				names = append(names, obj.GetName())
+			}
+			assert.Equal(t, scenario.expectedNames, names)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package k8sutil
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+// BuildObjectsFromKustomizeDir renders the kustomization found in the
+// provided directory into unstructured Kubernetes API objects. This is
+// same as running kustomize build against the directory.
+//
+// Note: Kustomize is used only by this file
+func BuildObjectsFromKustomizeDir(dir string) ([]*unstructured.Unstructured, error) {
+	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resMap, err := k.Run(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "kustomize build %q", dir)
+	}
+	rendered, err := resMap.AsYaml()
+	if err != nil {
+		return nil, errors.Wrapf(err, "kustomize build %q: encode to yaml", dir)
+	}
+	return ReadKubernetesObjects(bytes.NewReader(rendered))
+}
@@ -0,0 +1,65 @@
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBuildObjectsFromKustomizeDir(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name              string
+		dir               string
+		expectedName      string
+		expectedNamespace string
+		expectedLabels    map[string]string
+		expectedData      map[string]string
+		isError           bool
+	}{
+		{
+			name:         "should render the base",
+			dir:          "testdata/kustomize/base",
+			expectedName: "settings",
+			expectedData: map[string]string{"env": "base", "replicas": "1"},
+		},
+		{
+			name:              "should render the overlay on top of the base",
+			dir:               "testdata/kustomize/overlay",
+			expectedName:      "staging-settings",
+			expectedNamespace: "staging",
+			expectedLabels:    map[string]string{"env": "staging"},
+			expectedData:      map[string]string{"env": "staging", "replicas": "1"},
+		},
+		{
+			name:    "should fail for directory without kustomization",
+			dir:     "testdata",
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			objs, err := BuildObjectsFromKustomizeDir(scenario.dir)
+			if scenario.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if !assert.Len(t, objs, 1) {
+				return
+			}
+			assert.Equal(t, scenario.expectedName, objs[0].GetName())
+			assert.Equal(t, scenario.expectedNamespace, objs[0].GetNamespace())
+			assert.Equal(t, scenario.expectedLabels, objs[0].GetLabels())
+			var cm corev1.ConfigMap
+			assert.NoError(t, ToTyped(objs[0], &cm))
+			assert.Equal(t, scenario.expectedData, cm.Data)
+		})
+	}
+}
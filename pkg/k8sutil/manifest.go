@@ -2,9 +2,13 @@ package k8sutil
 
 import (
 	"bufio"
+	"bytes"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"sort"
+	"text/template"
 
 	"github.com/hashicorp/go-multierror"
 
@@ -51,8 +55,62 @@ func BuildObjectsFromYMLs(filePaths []string) ([]*unstructured.Unstructured, err
 	return objects, (&multierror.Error{Errors: errs}).ErrorOrNil()
 }
 
+// BuildObjectsFromYMLsWithValues is same as BuildObjectsFromYMLs but
+// executes each file as a text/template with the provided values before
+// decoding it. The templates refer to the values by their keys e.g.
+// {{ .namespace }} or {{ .image }}.
+//
+// Note: A key referred by a template but missing in the provided values
+// results in an error
+func BuildObjectsFromYMLsWithValues(filePaths []string, values map[string]interface{}) ([]*unstructured.Unstructured, error) {
+	if len(filePaths) == 0 {
+		return nil, errors.New("no file paths provided")
+	}
+
+	var objects = make([]*unstructured.Unstructured, 0)
+	manifests, err := ScanForYMLsFromPaths(filePaths)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs = make([]error, 0, len(manifests))
+	for _, manifest := range manifests {
+		content, err := os.ReadFile(manifest)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "yaml %q", manifest))
+			continue
+		}
+
+		tpl, err := template.New(manifest).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "parse template: yaml %q", manifest))
+			continue
+		}
+		var rendered bytes.Buffer
+		if err := tpl.Execute(&rendered, values); err != nil {
+			errs = append(errs, errors.Wrapf(err, "execute template: yaml %q", manifest))
+			continue
+		}
+
+		objs, err := ReadKubernetesObjects(&rendered)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "yaml %q", manifest))
+			continue
+		}
+		objects = MaybeAppendUnstructuredList(objects, objs)
+	}
+	return objects, (&multierror.Error{Errors: errs}).ErrorOrNil()
+}
+
+// ScanForYMLsFromPaths scans for yaml files present in the provided file
+// & directory paths. Files are returned in the order of the provided paths
+// & a file is returned once even if it is found via multiple paths.
+//
+// Note: Symlinked directories found within the provided directories are
+// not followed
 func ScanForYMLsFromPaths(paths []string) ([]string, error) {
 	var manifests []string
+	var seen = make(map[string]bool)
 
 	var errs = make([]error, 0, len(paths))
 	for _, path := range paths {
@@ -62,17 +120,24 @@ func ScanForYMLsFromPaths(paths []string) ([]string, error) {
 			continue
 		}
 
+		var found []string
 		switch mode := fi.Mode(); {
 		case mode.IsDir():
-			m, err := ScanForYMLsFromDir(path)
+			found, err = ScanForYMLsFromDir(path, false)
 			if err != nil {
 				errs = append(errs, errors.Wrapf(err, "path %q", path))
 				continue
 			}
-			manifests = append(manifests, m...)
 		case mode.IsRegular():
 			if IsExtensionYML(fi.Name()) {
-				manifests = append(manifests, path)
+				found = []string{path}
+			}
+		}
+		for _, manifest := range found {
+			key := filepath.Clean(manifest)
+			if !seen[key] {
+				seen[key] = true
+				manifests = append(manifests, manifest)
 			}
 		}
 	}
@@ -80,30 +145,83 @@ func ScanForYMLsFromPaths(paths []string) ([]string, error) {
 	return manifests, (&multierror.Error{Errors: errs}).ErrorOrNil()
 }
 
-// ScanForYMLsFromDir scans for files present in the provided directory &
-// its sub-directories if any
-func ScanForYMLsFromDir(dir string) ([]string, error) {
-	var manifests []string
-	files, err := os.ReadDir(dir)
+// ScanForYMLsFromDir scans for yaml files present in the provided
+// directory & its sub-directories if any. Returned files are sorted &
+// free of duplicates.
+//
+// Note: Symlinked sub-directories are scanned only if followSymlinks is
+// true. A directory is scanned once even if it is linked multiple times.
+func ScanForYMLsFromDir(dir string, followSymlinks bool) ([]string, error) {
+	var manifests = make(map[string]bool)
+	var visited = make(map[string]bool)
+	err := scanForYMLsFromDir(dir, followSymlinks, visited, manifests)
+
+	var result = make([]string, 0, len(manifests))
+	for manifest := range manifests {
+		result = append(result, manifest)
+	}
+	sort.Strings(result)
+	return result, err
+}
+
+// scanForYMLsFromDir adds the yaml files present in the provided
+// directory to the provided manifests. Visited keeps track of the real
+// paths of the scanned directories to avoid scanning them again e.g. due
+// to a symlink cycle.
+func scanForYMLsFromDir(dir string, followSymlinks bool, visited, manifests map[string]bool) error {
+	realDir, err := filepath.EvalSymlinks(dir)
 	if err != nil {
-		return nil, errors.Wrapf(err, "dir %q", dir)
+		return errors.Wrapf(err, "dir %q", dir)
+	}
+	if visited[realDir] {
+		return nil
 	}
+	visited[realDir] = true
 
-	var errs = make([]error, 0, len(files))
-	for _, file := range files {
-		if file.IsDir() {
-			m, err := ScanForYMLsFromDir(path.Join(dir, file.Name()))
+	// Note: The real directory is walked since a symlinked directory is
+	// not walked into. Walked paths are reported relative to dir.
+	var errs []error
+	_ = filepath.WalkDir(realDir, func(walkedPath string, entry fs.DirEntry, err error) error {
+		rel, _ := filepath.Rel(realDir, walkedPath)
+		entryPath := filepath.Join(dir, rel)
+		if err != nil {
+			// continue with the rest of the entries
+			errs = append(errs, errors.Wrapf(err, "path %q", entryPath))
+			return nil
+		}
+		if entry.IsDir() {
+			if walkedPath == realDir {
+				return nil
+			}
+			realPath, err := filepath.EvalSymlinks(walkedPath)
 			if err != nil {
-				errs = append(errs, err)
-				continue
+				errs = append(errs, errors.Wrapf(err, "dir %q", entryPath))
+				return filepath.SkipDir
+			}
+			if visited[realPath] {
+				return filepath.SkipDir
+			}
+			visited[realPath] = true
+			return nil
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			// Note: A dangling symlink is treated as a file
+			fi, err := os.Stat(entryPath)
+			if err == nil && fi.IsDir() {
+				if followSymlinks {
+					if err := scanForYMLsFromDir(entryPath, followSymlinks, visited, manifests); err != nil {
+						errs = append(errs, err)
+					}
+				}
+				return nil
 			}
-			manifests = append(manifests, m...)
 		}
-		if IsExtensionYML(file.Name()) {
-			manifests = append(manifests, path.Join(dir, file.Name()))
+		if IsExtensionYML(entry.Name()) {
+			manifests[entryPath] = true
 		}
-	}
-	return manifests, (&multierror.Error{Errors: errs}).ErrorOrNil()
+		return nil
+	})
+	return (&multierror.Error{Errors: errs}).ErrorOrNil()
 }
 
 // IsExtensionYML returns true if provided file has yaml extension
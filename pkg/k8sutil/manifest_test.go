@@ -0,0 +1,139 @@
+package k8sutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const templatedConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: templated
+  namespace: {{ .namespace }}
+  labels:
+    run: {{ .run | printf "%q" }}
+`
+
+func TestBuildObjectsFromYMLsWithValues(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "cm.yaml")
+	assert.NoError(t, os.WriteFile(manifest, []byte(templatedConfigMap), 0600))
+
+	var scenarios = []struct {
+		name              string
+		values            map[string]interface{}
+		expectedNamespace string
+		expectedRun       string
+		isError           bool
+	}{
+		{
+			name:              "should substitute the provided values",
+			values:            map[string]interface{}{"namespace": "e2e-1", "run": "1234"},
+			expectedNamespace: "e2e-1",
+			expectedRun:       "1234",
+		},
+		{
+			name:    "should fail when a referred key is missing",
+			values:  map[string]interface{}{"namespace": "e2e-1"},
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			objs, err := BuildObjectsFromYMLsWithValues([]string{manifest}, scenario.values)
+			if scenario.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if assert.Len(t, objs, 1) {
+				assert.Equal(t, scenario.expectedNamespace, objs[0].GetNamespace())
+				assert.Equal(t, scenario.expectedRun, objs[0].GetLabels()["run"])
+			}
+		})
+	}
+}
+
+// writeManifests writes a manifest at each of the provided relative paths
+// within the provided directory
+func writeManifests(t *testing.T, dir string, paths ...string) {
+	for _, p := range paths {
+		full := filepath.Join(dir, p)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(full), 0700))
+		assert.NoError(t, os.WriteFile(full, []byte(templatedConfigMap), 0600))
+	}
+}
+
+func TestScanForYMLsFromDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "manifests")
+	shared := filepath.Join(root, "shared")
+	writeManifests(t, dir, "b.yaml", "a.yaml", "nested/c.yml", "nested/notes.txt", "dir.yaml/d.yaml")
+	writeManifests(t, shared, "s.yaml")
+	assert.NoError(t, os.Symlink(shared, filepath.Join(dir, "linked")))
+	assert.NoError(t, os.Symlink(dir, filepath.Join(dir, "nested", "cycle")))
+
+	var scenarios = []struct {
+		name           string
+		followSymlinks bool
+		expected       []string
+	}{
+		{
+			name: "should skip symlinked directories",
+			expected: []string{
+				filepath.Join(dir, "a.yaml"),
+				filepath.Join(dir, "b.yaml"),
+				filepath.Join(dir, "dir.yaml", "d.yaml"),
+				filepath.Join(dir, "nested", "c.yml"),
+			},
+		},
+		{
+			name:           "should follow symlinked directories once",
+			followSymlinks: true,
+			expected: []string{
+				filepath.Join(dir, "a.yaml"),
+				filepath.Join(dir, "b.yaml"),
+				filepath.Join(dir, "dir.yaml", "d.yaml"),
+				filepath.Join(dir, "linked", "s.yaml"),
+				filepath.Join(dir, "nested", "c.yml"),
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ScanForYMLsFromDir(dir, scenario.followSymlinks)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expected, got)
+		})
+	}
+}
+
+func TestScanForYMLsFromPaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeManifests(t, dir, "a.yaml", "b.yaml")
+
+	got, err := ScanForYMLsFromPaths([]string{
+		filepath.Join(dir, "b.yaml"),
+		dir,
+		filepath.Join(dir, ".", "a.yaml"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "b.yaml"), filepath.Join(dir, "a.yaml")}, got)
+}
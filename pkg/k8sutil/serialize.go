@@ -0,0 +1,116 @@
+package k8sutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OutputFormatYAML serializes the objects as YAML documents
+	// separated with ---
+	OutputFormatYAML = "yaml"
+
+	// OutputFormatJSON serializes the objects as a JSON v1 List
+	OutputFormatJSON = "json"
+)
+
+// encode serializes the provided object as YAML if isYAML is true or
+// else as indented JSON. The object's kind & apiVersion are set from the
+// client-go scheme if they are missing.
+//
+// Note: Objects are encoded via their unstructured content instead of
+// the runtime serializer since the latter's json-iterator panics on the
+// maps of typed objects with recent go versions
+func encode(obj client.Object, isYAML bool) ([]byte, error) {
+	if obj == nil {
+		return nil, errors.New("nil object")
+	}
+	gvk, err := apiutil.GVKForObject(obj, scheme.Scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, "extract gvk")
+	}
+	encoded := obj.DeepCopyObject()
+	encoded.GetObjectKind().SetGroupVersionKind(gvk)
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(encoded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "convert to unstructured: %s", DescribeObj(obj))
+	}
+	var out []byte
+	if isYAML {
+		out, err = yaml.Marshal(content)
+	} else {
+		out, err = json.MarshalIndent(content, "", "  ")
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "encode %s", DescribeObj(obj))
+	}
+	if !isYAML {
+		out = append(out, '\n')
+	}
+	return out, nil
+}
+
+// ToJSON serializes the provided typed or unstructured object as
+// indented JSON
+func ToJSON(obj client.Object) ([]byte, error) {
+	return encode(obj, false)
+}
+
+// ToYAML serializes the provided typed or unstructured object as YAML
+func ToYAML(obj client.Object) ([]byte, error) {
+	return encode(obj, true)
+}
+
+// WriteObjectsToWriter serializes the provided objects in the provided
+// format i.e. yaml or json & writes them to the provided writer.
+//
+// Note: The output can be read back via ReadKubernetesObjects
+func WriteObjectsToWriter(w io.Writer, objs []client.Object, format string) error {
+	if w == nil {
+		return errors.New("nil writer")
+	}
+	var out bytes.Buffer
+	switch format {
+	case OutputFormatYAML:
+		for _, obj := range objs {
+			encoded, err := ToYAML(obj)
+			if err != nil {
+				return err
+			}
+			out.WriteString("---\n")
+			out.Write(encoded)
+		}
+	case OutputFormatJSON:
+		var items = make([]json.RawMessage, 0, len(objs))
+		for _, obj := range objs {
+			encoded, err := ToJSON(obj)
+			if err != nil {
+				return err
+			}
+			items = append(items, encoded)
+		}
+		encoded, err := json.MarshalIndent(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      items,
+		}, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "encode list")
+		}
+		out.Write(encoded)
+		out.WriteByte('\n')
+	default:
+		return errors.Errorf("unsupported format %q: want %s or %s", format, OutputFormatYAML, OutputFormatJSON)
+	}
+	_, err := w.Write(out.Bytes())
+	return errors.Wrap(err, "failed to write objects")
+}
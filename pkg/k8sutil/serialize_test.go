@@ -0,0 +1,135 @@
+package k8sutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestToJSONAndToYAML(t *testing.T) {
+	t.Parallel()
+
+	unstruct := &unstructured.Unstructured{}
+	unstruct.SetAPIVersion("v1")
+	unstruct.SetKind("ConfigMap")
+	unstruct.SetName("unstructured")
+	unstruct.SetNamespace("default")
+	_ = unstructured.SetNestedStringMap(unstruct.Object, map[string]string{"key": "value"}, "data")
+
+	var scenarios = []struct {
+		name    string
+		obj     client.Object
+		isError bool
+	}{
+		{
+			name: "should round trip typed object without type meta",
+			obj: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "typed", Namespace: "default"},
+				Data:       map[string]string{"key": "value"},
+			},
+		},
+		{
+			name: "should round trip unstructured object",
+			obj:  unstruct,
+		},
+		{
+			name:    "should fail for nil object",
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			for format, serialize := range map[string]func(client.Object) ([]byte, error){
+				OutputFormatJSON: ToJSON,
+				OutputFormatYAML: ToYAML,
+			} {
+				out, err := serialize(scenario.obj)
+				if scenario.isError {
+					assert.Error(t, err, format)
+					continue
+				}
+				assert.NoError(t, err, format)
+
+				objs, err := BuildObjectsFromReader(bytes.NewReader(out))
+				assert.NoError(t, err, format)
+				if !assert.Len(t, objs, 1, format) {
+					continue
+				}
+				assert.Equal(t, "ConfigMap", objs[0].GetKind(), format)
+				assert.Equal(t, "v1", objs[0].GetAPIVersion(), format)
+				assert.Equal(t, scenario.obj.GetName(), objs[0].GetName(), format)
+				var cm corev1.ConfigMap
+				assert.NoError(t, ToTyped(objs[0], &cm), format)
+				assert.Equal(t, map[string]string{"key": "value"}, cm.Data, format)
+			}
+		})
+	}
+}
+
+func TestWriteObjectsToWriter(t *testing.T) {
+	t.Parallel()
+
+	objs := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "first"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "first"}},
+	}
+
+	var scenarios = []struct {
+		name     string
+		format   string
+		contains string
+		isError  bool
+	}{
+		{
+			name:     "should write yaml documents",
+			format:   OutputFormatYAML,
+			contains: "---\napiVersion: v1\nkind: Namespace\n",
+		},
+		{
+			name:     "should write json list",
+			format:   OutputFormatJSON,
+			contains: `"kind": "List"`,
+		},
+		{
+			name:    "should fail for unsupported format",
+			format:  "xml",
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			err := WriteObjectsToWriter(&buf, objs, scenario.format)
+			if scenario.isError {
+				assert.Error(t, err)
+				assert.Zero(t, buf.Len())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, buf.String(), scenario.contains)
+
+			got, err := ReadKubernetesObjects(bytes.NewReader(buf.Bytes()))
+			assert.NoError(t, err)
+			if assert.Len(t, got, 2) {
+				assert.Equal(t, "Namespace", got[0].GetKind())
+				assert.Equal(t, "first", got[0].GetName())
+				assert.Equal(t, "ConfigMap", got[1].GetKind())
+				assert.Equal(t, "second", got[1].GetName())
+				assert.Equal(t, "first", got[1].GetNamespace())
+			}
+		})
+	}
+}
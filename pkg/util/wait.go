@@ -1,6 +1,7 @@
 package util
 
 import (
+	"context"
 	"time"
 
 	"github.com/pkg/errors"
@@ -20,12 +21,36 @@ type RetryOptions struct {
 // Note: It is valid for a condition to return true with error
 // Note: Original error throws by the condition is preserved
 func Retry(opts RetryOptions, cond func() (bool, error)) error {
+	return RetryWithContext(context.Background(), opts, cond)
+}
+
+// RetryWithContext is same as Retry but returns the context's error as
+// soon as the provided context is done
+func RetryWithContext(ctx context.Context, opts RetryOptions, cond func() (bool, error)) error {
 	var count int = 1
 	start := time.Now()
+
+	// wait blocks for an interval or till the context is done
+	wait := func() error {
+		timer := time.NewTimer(opts.Interval)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if !opts.Immediate {
 			// first wait then run the condition
-			time.Sleep(opts.Interval)
+			if err := wait(); err != nil {
+				return err
+			}
 		}
 		done, err := cond()
 		if done {
@@ -39,7 +64,9 @@ func Retry(opts RetryOptions, cond func() (bool, error)) error {
 		count++
 		if opts.Immediate {
 			// first run the condition then wait
-			time.Sleep(opts.Interval)
+			if err := wait(); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryWithContext(t *testing.T) {
+	t.Parallel()
+
+	var scenarios = []struct {
+		name        string
+		opts        RetryOptions
+		cond        func(cancel context.CancelFunc) func() (bool, error)
+		expectedErr error
+		isError     bool
+	}{
+		{
+			name: "should return when condition is met",
+			opts: RetryOptions{Immediate: true, Interval: time.Millisecond, Timeout: time.Second},
+			cond: func(_ context.CancelFunc) func() (bool, error) {
+				return func() (bool, error) { return true, nil }
+			},
+		},
+		{
+			name: "should return promptly when context is cancelled mid retry",
+			opts: RetryOptions{Immediate: true, Interval: time.Minute, Timeout: time.Hour},
+			cond: func(cancel context.CancelFunc) func() (bool, error) {
+				return func() (bool, error) {
+					cancel()
+					return false, errors.New("not yet")
+				}
+			},
+			expectedErr: context.Canceled,
+		},
+		{
+			name: "should return promptly when context is cancelled while waiting first",
+			opts: RetryOptions{Interval: time.Minute, Timeout: time.Hour},
+			cond: func(cancel context.CancelFunc) func() (bool, error) {
+				cancel()
+				return func() (bool, error) { return true, nil }
+			},
+			expectedErr: context.Canceled,
+		},
+		{
+			name: "should time out when condition is never met",
+			opts: RetryOptions{Immediate: true, Interval: time.Millisecond, Timeout: 10 * time.Millisecond},
+			cond: func(_ context.CancelFunc) func() (bool, error) {
+				return func() (bool, error) { return false, nil }
+			},
+			isError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // pin it
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			start := time.Now()
+			err := RetryWithContext(ctx, scenario.opts, scenario.cond(cancel))
+			assert.Less(t, time.Since(start), 5*time.Second)
+			if scenario.expectedErr != nil {
+				assert.ErrorIs(t, err, scenario.expectedErr)
+			} else if scenario.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}